@@ -128,6 +128,22 @@ func (c *APIClient) GetAccountTransactions(ctx context.Context, accountID string
 		return nil, errors.New("accountID cannot be empty")
 	}
 
+	reqHTTP, err := c.newAccountTransactionsRequest(ctx, accountID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp HalTransactions
+	err = c.sendRequest(reqHTTP, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// newAccountTransactionsRequest creates a GET /accounts/{account_id}/transactions request.
+func (c *APIClient) newAccountTransactionsRequest(ctx context.Context, accountID string, params *GetAccountTransactionsRequestParams) (*http.Request, error) {
 	url := "/accounts/" + accountID + "/transactions"
 	reqHTTP, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -163,13 +179,7 @@ func (c *APIClient) GetAccountTransactions(ctx context.Context, accountID string
 		params.Headers.FillHTTPHeader(reqHTTP.Header)
 	}
 
-	var resp HalTransactions
-	err = c.sendRequest(reqHTTP, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
+	return reqHTTP, nil
 }
 
 // GetTransactionDetails retrieves details of a specific transaction for a specific account.
@@ -0,0 +1,288 @@
+package enablebankinggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultAggregateConcurrency is the default number of accounts aggregated concurrently.
+const DefaultAggregateConcurrency = 4
+
+type (
+	// RateLimiter limits the rate of API calls.
+	RateLimiter interface {
+		// Wait blocks until a call is allowed or ctx is done.
+		Wait(ctx context.Context) error
+	}
+
+	// AggregateRequestParams represents the parameters for aggregating the account data of a session.
+	AggregateRequestParams struct {
+		// Concurrency is the number of accounts aggregated concurrently. Defaults to
+		// DefaultAggregateConcurrency.
+		Concurrency int
+
+		// RateLimiter limits the rate of account data API calls, if set.
+		RateLimiter RateLimiter
+
+		// SkipDetails skips retrieving account details.
+		SkipDetails bool
+
+		// SkipBalances skips retrieving account balances.
+		SkipBalances bool
+
+		// SkipTransactions skips retrieving account transactions.
+		SkipTransactions bool
+
+		// DateFrom is the date to fetch transactions from.
+		DateFrom time.Time
+
+		// DateTo is the date to fetch transactions to.
+		DateTo time.Time
+
+		// TransactionStatus is the transaction status to filter by.
+		TransactionStatus TransactionStatus
+
+		// Strategy is the strategy how transactions are fetched.
+		Strategy TransactionsFetchStrategy
+
+		// PrefetchTransactions retrieves the next page of transactions while the current page is
+		// processed.
+		PrefetchTransactions bool
+
+		// Headers represents additional headers to include in the account data requests.
+		Headers Header
+	}
+
+	// AccountAggregate represents the aggregated data of an account.
+	AccountAggregate struct {
+		// AccountID is the ID of the account.
+		AccountID string
+
+		// Details is the account details, unless skipped or failed.
+		Details *AccountResource
+
+		// Balances is the account balances, unless skipped or failed.
+		Balances []*BalanceResource
+
+		// Transactions is the account transactions of all pages, unless skipped or failed.
+		Transactions []*Transaction
+
+		// DetailsErr is the error retrieving account details, if any.
+		DetailsErr error
+
+		// BalancesErr is the error retrieving account balances, if any.
+		BalancesErr error
+
+		// TransactionsErr is the error retrieving account transactions, if any. Transactions of pages
+		// retrieved before the error are kept.
+		TransactionsErr error
+	}
+
+	// AggregateResult represents the aggregated account data of a session.
+	AggregateResult struct {
+		// Session is the session.
+		Session *GetSessionResponse
+
+		// Accounts is the aggregated data of the accounts of the session, in session order.
+		Accounts []*AccountAggregate
+	}
+)
+
+// Err returns the errors of the account data, joined, or nil if all data was retrieved.
+func (a *AccountAggregate) Err() error {
+	var errs []error
+	if a.DetailsErr != nil {
+		errs = append(errs, fmt.Errorf("details: %w", a.DetailsErr))
+	}
+
+	if a.BalancesErr != nil {
+		errs = append(errs, fmt.Errorf("balances: %w", a.BalancesErr))
+	}
+
+	if a.TransactionsErr != nil {
+		errs = append(errs, fmt.Errorf("transactions: %w", a.TransactionsErr))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Failed returns the accounts with errors.
+func (r *AggregateResult) Failed() []*AccountAggregate {
+	var failed []*AccountAggregate
+	for _, account := range r.Accounts {
+		if account.Err() != nil {
+			failed = append(failed, account)
+		}
+	}
+
+	return failed
+}
+
+// Err returns the errors of all accounts, joined, or nil if all account data was retrieved.
+func (r *AggregateResult) Err() error {
+	var errs []error
+	for _, account := range r.Failed() {
+		errs = append(errs, fmt.Errorf("account %s: %w", account.AccountID, account.Err()))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Aggregate retrieves details, balances and transactions of all accounts of a session, with bounded
+// concurrency and optional rate limiting. An error is only returned if the session can't be
+// retrieved, failures of individual accounts are reported in the result.
+func (c *APIClient) Aggregate(ctx context.Context, sessionID string, params *AggregateRequestParams) (*AggregateResult, error) {
+	return Aggregate(ctx, c, c, sessionID, params)
+}
+
+// Aggregate retrieves details, balances and transactions of all accounts of a session using the
+// provided clients, see [APIClient.Aggregate].
+func Aggregate(ctx context.Context, sessions UserSessionsClient, accounts AccountsDataClient, sessionID string, params *AggregateRequestParams) (*AggregateResult, error) {
+	if sessionID == "" {
+		return nil, errors.New("sessionID cannot be empty")
+	}
+
+	if params == nil {
+		params = &AggregateRequestParams{}
+	}
+
+	session, err := sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultAggregateConcurrency
+	}
+
+	result := &AggregateResult{
+		Session:  session,
+		Accounts: make([]*AccountAggregate, len(session.Accounts)),
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, accountID := range session.Accounts {
+		account := &AccountAggregate{AccountID: accountID}
+		result.Accounts[i] = account
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				account.DetailsErr, account.BalancesErr, account.TransactionsErr = ctx.Err(), ctx.Err(), ctx.Err()
+				return
+			}
+
+			aggregateAccount(ctx, accounts, account, params)
+		}()
+	}
+
+	wg.Wait()
+
+	return result, nil
+}
+
+func aggregateAccount(ctx context.Context, client AccountsDataClient, account *AccountAggregate, params *AggregateRequestParams) {
+	wait := func() error {
+		if params.RateLimiter == nil {
+			return ctx.Err()
+		}
+
+		return params.RateLimiter.Wait(ctx)
+	}
+
+	if !params.SkipDetails {
+		account.DetailsErr = wait()
+		if account.DetailsErr == nil {
+			account.Details, account.DetailsErr = client.GetAccountDetails(ctx, account.AccountID, &GetAccountDetailsRequestParams{
+				Headers: params.Headers,
+			})
+		}
+	}
+
+	if !params.SkipBalances {
+		account.BalancesErr = wait()
+		if account.BalancesErr == nil {
+			var resp *HalBalances
+			resp, account.BalancesErr = client.GetAccountBalances(ctx, account.AccountID, &GetAccountBalancesRequestParams{
+				Headers: params.Headers,
+			})
+			if resp != nil {
+				account.Balances = resp.Balances
+			}
+		}
+	}
+
+	if !params.SkipTransactions {
+		req := &GetAccountTransactionsRequestParams{
+			DateFromQueryParam:          params.DateFrom,
+			DateToQueryParam:            params.DateTo,
+			TransactionStatusQueryParam: params.TransactionStatus,
+			StrategyQueryParam:          params.Strategy,
+			Headers:                     params.Headers,
+		}
+
+		iterParams := &TransactionsIteratorParams{
+			Prefetch:    params.PrefetchTransactions,
+			RateLimiter: params.RateLimiter,
+		}
+
+		for tx, err := range IterateTransactions(ctx, client, account.AccountID, req, iterParams) {
+			if err != nil {
+				account.TransactionsErr = err
+				return
+			}
+
+			account.Transactions = append(account.Transactions, tx)
+		}
+	}
+}
+
+// intervalRateLimiter is a rate limiter allowing a call every interval.
+type intervalRateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// NewRateLimiter returns a rate limiter allowing a call every interval, e.g. time.Second/5 for five
+// calls per second, shared by all goroutines using it.
+func NewRateLimiter(interval time.Duration) RateLimiter {
+	return &intervalRateLimiter{interval: interval}
+}
+
+// Wait blocks until a call is allowed or ctx is done.
+func (l *intervalRateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
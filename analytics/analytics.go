@@ -0,0 +1,366 @@
+// Package analytics provides cash flow analytics of transaction history, e.g. monthly inflow and
+// outflow, net cash flow, category breakdowns and burn rate, for lending and financial dashboards.
+package analytics
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// IncomeCategory is the category of salaries and other income.
+	IncomeCategory = "income"
+
+	// UncategorizedCategory is the category of transactions without a category.
+	UncategorizedCategory = "uncategorized"
+)
+
+// merchantCategories is the categories of ISO 18245 merchant category code ranges.
+var merchantCategories = []struct {
+	from, to int
+	category string
+}{
+	{3000, 3999, "travel"},
+	{4011, 4131, "transport"},
+	{4411, 4722, "travel"},
+	{4812, 4816, "utilities"},
+	{4899, 4900, "utilities"},
+	{5200, 5299, "home"},
+	{5300, 5399, "shopping"},
+	{5411, 5499, "groceries"},
+	{5500, 5599, "transport"},
+	{5600, 5699, "shopping"},
+	{5700, 5799, "home"},
+	{5811, 5814, "restaurants"},
+	{5912, 5912, "health"},
+	{5900, 5999, "shopping"},
+	{6010, 6012, "cash"},
+	{6300, 6399, "insurance"},
+	{7011, 7033, "travel"},
+	{7800, 7999, "entertainment"},
+	{8000, 8099, "health"},
+	{8200, 8299, "education"},
+}
+
+// MerchantCategory returns the category of an ISO 18245 merchant category code, e.g. groceries for
+// 5411, or an empty string if unknown.
+func MerchantCategory(mcc string) string {
+	code, err := strconv.Atoi(strings.TrimSpace(mcc))
+	if err != nil {
+		return ""
+	}
+
+	for _, c := range merchantCategories {
+		if code >= c.from && code <= c.to {
+			return c.category
+		}
+	}
+
+	return ""
+}
+
+// DefaultCategorize returns the category of tx based on its merchant category code, IncomeCategory for
+// salaries, the bank transaction code description, or UncategorizedCategory.
+func DefaultCategorize(tx *enablebankinggo.Transaction) string {
+	if category := MerchantCategory(tx.MerchantCategoryCode); category != "" {
+		return category
+	}
+
+	if code := tx.BankTransactionCode; code != nil {
+		if code.SubCode == "SALA" || code.SubCode == "PENS" {
+			return IncomeCategory
+		}
+
+		if code.Description != "" {
+			return strings.ToLower(code.Description)
+		}
+	}
+
+	return UncategorizedCategory
+}
+
+// Options represents the options of a cash flow report.
+type Options struct {
+	// Currency is the currency of the report. Transactions in other currencies are skipped. Defaults to
+	// the currency of the first transaction.
+	Currency string
+
+	// Categorize returns the category of a transaction. Defaults to DefaultCategorize.
+	Categorize func(tx *enablebankinggo.Transaction) string
+
+	// Balance is the current balance, used to compute the runway, if set.
+	Balance string
+}
+
+// Totals represents the cash flow of a set of transactions. Amounts are rounded to two decimals.
+type Totals struct {
+	// Inflow is the sum of credits.
+	Inflow string
+
+	// Outflow is the sum of debits, as a positive amount.
+	Outflow string
+
+	// Net is Inflow minus Outflow.
+	Net string
+
+	// Transactions is the number of transactions.
+	Transactions int
+}
+
+// CategoryTotals represents the cash flow of a category.
+type CategoryTotals struct {
+	Totals
+
+	// Category is the category.
+	Category string
+}
+
+// MonthReport represents the cash flow of a month.
+type MonthReport struct {
+	Totals
+
+	// Month is the month, e.g. 2025-01.
+	Month string
+
+	// Categories is the cash flow by category, sorted by outflow descending.
+	Categories []*CategoryTotals
+}
+
+// Report represents a cash flow report.
+type Report struct {
+	Totals
+
+	// Currency is the currency of the report.
+	Currency string
+
+	// Months is the cash flow by month, in chronological order including months without transactions.
+	Months []*MonthReport
+
+	// Categories is the cash flow by category, sorted by outflow descending.
+	Categories []*CategoryTotals
+
+	// AverageMonthlyInflow is the average inflow per month.
+	AverageMonthlyInflow string
+
+	// AverageMonthlyOutflow is the average outflow per month.
+	AverageMonthlyOutflow string
+
+	// BurnRate is the average net outflow per month, zero if the average net cash flow is positive.
+	BurnRate string
+
+	// RunwayMonths is the number of months Options.Balance lasts at the burn rate, rounded to one
+	// decimal, empty if the balance isn't set or the burn rate is zero.
+	RunwayMonths string
+
+	// Skipped is the number of booked transactions skipped, e.g. in another currency or without a
+	// valid amount or date.
+	Skipped int
+}
+
+type totals struct {
+	inflow       *big.Rat
+	outflow      *big.Rat
+	transactions int
+}
+
+func newTotals() *totals {
+	return &totals{inflow: new(big.Rat), outflow: new(big.Rat)}
+}
+
+func (t *totals) add(amount *big.Rat) {
+	if amount.Sign() < 0 {
+		t.outflow.Sub(t.outflow, amount)
+	} else {
+		t.inflow.Add(t.inflow, amount)
+	}
+	t.transactions++
+}
+
+func (t *totals) report() Totals {
+	return Totals{
+		Inflow:       t.inflow.FloatString(2),
+		Outflow:      t.outflow.FloatString(2),
+		Net:          new(big.Rat).Sub(t.inflow, t.outflow).FloatString(2),
+		Transactions: t.transactions,
+	}
+}
+
+// CashFlow computes a cash flow report of the booked transactions. Transactions that aren't booked are
+// ignored.
+func CashFlow(transactions []*enablebankinggo.Transaction, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	categorize := opts.Categorize
+	if categorize == nil {
+		categorize = DefaultCategorize
+	}
+
+	report := &Report{Currency: opts.Currency}
+	total := newTotals()
+	months := map[string]*totals{}
+	monthCategories := map[string]map[string]*totals{}
+	categories := map[string]*totals{}
+
+	for _, tx := range transactions {
+		if tx == nil || (tx.Status != enablebankinggo.AccountedTransactionStatus && tx.Status != "") {
+			continue
+		}
+
+		if tx.TransactionAmount == nil {
+			report.Skipped++
+			continue
+		}
+
+		if report.Currency == "" {
+			report.Currency = tx.TransactionAmount.Currency
+		}
+
+		amount, ok := signedAmount(tx)
+		month, monthOK := monthOf(tx)
+		if !ok || !monthOK || tx.TransactionAmount.Currency != report.Currency {
+			report.Skipped++
+			continue
+		}
+
+		category := categorize(tx)
+		if category == "" {
+			category = UncategorizedCategory
+		}
+
+		total.add(amount)
+		if months[month] == nil {
+			months[month] = newTotals()
+			monthCategories[month] = map[string]*totals{}
+		}
+		months[month].add(amount)
+
+		if monthCategories[month][category] == nil {
+			monthCategories[month][category] = newTotals()
+		}
+		monthCategories[month][category].add(amount)
+
+		if categories[category] == nil {
+			categories[category] = newTotals()
+		}
+		categories[category].add(amount)
+	}
+
+	report.Totals = total.report()
+	report.Categories = categoryReports(categories)
+
+	for _, month := range monthRange(months) {
+		m := months[month]
+		if m == nil {
+			m = newTotals()
+		}
+
+		report.Months = append(report.Months, &MonthReport{
+			Totals:     m.report(),
+			Month:      month,
+			Categories: categoryReports(monthCategories[month]),
+		})
+	}
+
+	count := big.NewRat(int64(max(1, len(report.Months))), 1)
+	averageInflow := new(big.Rat).Quo(total.inflow, count)
+	averageOutflow := new(big.Rat).Quo(total.outflow, count)
+	burnRate := new(big.Rat).Sub(averageOutflow, averageInflow)
+	if burnRate.Sign() < 0 {
+		burnRate.SetInt64(0)
+	}
+
+	report.AverageMonthlyInflow = averageInflow.FloatString(2)
+	report.AverageMonthlyOutflow = averageOutflow.FloatString(2)
+	report.BurnRate = burnRate.FloatString(2)
+
+	if opts.Balance != "" && burnRate.Sign() > 0 {
+		balance, ok := new(big.Rat).SetString(strings.TrimSpace(opts.Balance))
+		if !ok {
+			return nil, errors.New("invalid balance")
+		}
+
+		runway := new(big.Rat).Quo(balance, burnRate)
+		if runway.Sign() < 0 {
+			runway.SetInt64(0)
+		}
+
+		report.RunwayMonths = runway.FloatString(1)
+	}
+
+	return report, nil
+}
+
+func categoryReports(categories map[string]*totals) []*CategoryTotals {
+	reports := make([]*CategoryTotals, 0, len(categories))
+	for category, t := range categories {
+		reports = append(reports, &CategoryTotals{Totals: t.report(), Category: category})
+	}
+
+	slices.SortFunc(reports, func(a, b *CategoryTotals) int {
+		if c := categories[b.Category].outflow.Cmp(categories[a.Category].outflow); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Category, b.Category)
+	})
+
+	return reports
+}
+
+// monthRange returns the months from the first to the last month with transactions.
+func monthRange(months map[string]*totals) []string {
+	if len(months) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(months))
+	for month := range months {
+		keys = append(keys, month)
+	}
+
+	first, _ := time.Parse("2006-01", slices.Min(keys))
+	last, _ := time.Parse("2006-01", slices.Max(keys))
+
+	var result []string
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		result = append(result, month.Format("2006-01"))
+	}
+
+	return result
+}
+
+// monthOf returns the month of the booking date of tx, falling back to the value and transaction date.
+func monthOf(tx *enablebankinggo.Transaction) (string, bool) {
+	for _, date := range []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate} {
+		if t, err := time.Parse(time.DateOnly, date); err == nil {
+			return t.Format("2006-01"), true
+		}
+	}
+
+	return "", false
+}
+
+// signedAmount returns the amount of tx, negative for debits.
+func signedAmount(tx *enablebankinggo.Transaction) (*big.Rat, bool) {
+	amount, ok := new(big.Rat).SetString(strings.TrimSpace(tx.TransactionAmount.Amount))
+	if !ok {
+		return nil, false
+	}
+
+	switch tx.CreditDebitIndicator {
+	case enablebankinggo.DebitCreditDebitIndicator:
+		amount.Neg(amount.Abs(amount))
+	case enablebankinggo.CreditCreditDebitIndicator:
+		amount.Abs(amount)
+	}
+
+	return amount, true
+}
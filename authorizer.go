@@ -1,21 +1,85 @@
 package enablebankinggo
 
 import (
+	"context"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sync"
 	"time"
 )
 
+// AuthorizerCache shares the JWT of clients of the same application ID, private key, token TTLs and clock
+// created using [WithAuthorizerCache], so at most one JWT is signed per TTL instead of one per client
+// instance, e.g. in multi-tenant services creating many clients. The cache is owned by the caller and
+// released with it. An AuthorizerCache is safe for concurrent use.
+type AuthorizerCache struct {
+	mu          sync.Mutex
+	authorizers map[authorizerKey]*authorizer
+}
+
+// NewAuthorizerCache creates a new, empty authorizer cache.
+func NewAuthorizerCache() *AuthorizerCache {
+	return &AuthorizerCache{authorizers: map[authorizerKey]*authorizer{}}
+}
+
+type authorizerKey struct {
+	applicationID  string
+	keyFingerprint [sha256.Size]byte
+	tokenTTL       int64
+	extraTTL       time.Duration
+	clock          Clock
+}
+
+// WithAuthorizerCache shares the JWT of the client with the other clients of cache, see [AuthorizerCache].
+func WithAuthorizerCache(cache *AuthorizerCache) ClientOption {
+	return func(c *APIClient) {
+		c.authorizerCache = cache
+	}
+}
+
+// get returns the authorizer of the cache with the same application, private key, token TTLs and clock as a,
+// adding a if none exists. Authorizers with a clock that can't be compared aren't shared.
+func (c *AuthorizerCache) get(a *authorizer) *authorizer {
+	if !reflect.TypeOf(a.clock).Comparable() {
+		return a
+	}
+
+	key := authorizerKey{
+		applicationID:  a.applicationID,
+		keyFingerprint: sha256.Sum256(x509.MarshalPKCS1PublicKey(&a.privateKey.PublicKey)),
+		tokenTTL:       a.tokenTTL,
+		extraTTL:       a.extraTTL,
+		clock:          a.clock,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if shared, ok := c.authorizers[key]; ok {
+		return shared
+	}
+
+	c.authorizers[key] = a
+	return a
+}
+
 type authorizer struct {
 	applicationID string
 	privateKey    *rsa.PrivateKey
 	tokenTTL      int64
 	extraTTL      time.Duration
+	clock         Clock
+	sign          func(privateKey *rsa.PrivateKey, data []byte) (string, error)
 	m             sync.RWMutex
 	token         string
 	expiresAt     time.Time
+
+	// refreshing is closed when the in-flight token refresh completes, nil if none is in flight.
+	refreshing chan struct{}
 }
 
 func newAuthorizer(applicationID string, privateKey *rsa.PrivateKey, tokenTTL int, extraTTL time.Duration) *authorizer {
@@ -24,52 +88,101 @@ func newAuthorizer(applicationID string, privateKey *rsa.PrivateKey, tokenTTL in
 		privateKey:    privateKey,
 		tokenTTL:      int64(tokenTTL),
 		extraTTL:      extraTTL,
+		clock:         SystemClock,
+		sign:          sign,
 	}
 }
 
 func (a *authorizer) AuthorizeRequest(req *http.Request) error {
-	a.m.RLock()
-	if a.token != "" && time.Now().Add(a.extraTTL).Before(a.expiresAt) {
-		token := a.token
-		a.m.RUnlock()
-		req.Header.Set("Authorization", "Bearer "+token)
-		return nil
+	token, err := a.getToken(req.Context())
+	if err != nil {
+		return err
 	}
-	a.m.RUnlock()
 
-	a.m.Lock()
-	defer a.m.Unlock()
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// getToken returns a valid token, refreshing it if needed. Concurrent callers share a single refresh,
+// i.e. exactly one goroutine signs the new JWT while the others wait for it.
+func (a *authorizer) getToken(ctx context.Context) (string, error) {
+	for {
+		a.m.RLock()
+		if a.validLocked() {
+			token := a.token
+			a.m.RUnlock()
+			return token, nil
+		}
+		a.m.RUnlock()
+
+		a.m.Lock()
+		if a.validLocked() {
+			token := a.token
+			a.m.Unlock()
+			return token, nil
+		}
 
-	if a.token != "" && time.Now().Add(a.extraTTL).Before(a.expiresAt) {
-		req.Header.Set("Authorization", "Bearer "+a.token)
-		return nil
+		if refreshing := a.refreshing; refreshing != nil {
+			a.m.Unlock()
+
+			// Check the token again once the refresh completes, refreshing it if the refresh failed.
+			select {
+			case <-refreshing:
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		refreshing := make(chan struct{})
+		a.refreshing = refreshing
+		a.m.Unlock()
+
+		return a.refresh(refreshing)
 	}
+}
+
+// refresh generates a new token and releases the callers waiting for refreshing, even if generating
+// the token panics, e.g. in a custom sign function.
+func (a *authorizer) refresh(refreshing chan struct{}) (string, error) {
+	defer func() {
+		a.m.Lock()
+		a.refreshing = nil
+		close(refreshing)
+		a.m.Unlock()
+	}()
 
-	err := a.generateJWT()
+	token, expiresAt, err := a.generateJWT()
 	if err != nil {
-		return fmt.Errorf("failed to create JWT: %w", err)
+		return "", fmt.Errorf("failed to create JWT: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.token)
-	return nil
+	a.m.Lock()
+	a.token = token
+	a.expiresAt = expiresAt
+	a.m.Unlock()
+
+	return token, nil
+}
+
+func (a *authorizer) validLocked() bool {
+	return a.token != "" && a.clock.Now().Add(a.extraTTL).Before(a.expiresAt)
 }
 
-func (a *authorizer) generateJWT() error {
+func (a *authorizer) generateJWT() (string, time.Time, error) {
 	header, err := getJwtHeader(a.applicationID)
 	if err != nil {
-		return err
+		return "", time.Time{}, err
 	}
-	body, expiresAt, err := getJwtBody(a.tokenTTL)
+	body, expiresAt, err := getJwtBody(a.clock.Now(), a.tokenTTL)
 	if err != nil {
-		return err
+		return "", time.Time{}, err
 	}
 	signBody := fmt.Sprintf("%s.%s", header, body)
-	signature, err := sign(a.privateKey, []byte(signBody))
+	signature, err := a.sign(a.privateKey, []byte(signBody))
 	if err != nil {
-		return err
+		return "", time.Time{}, err
 	}
 
-	a.token = fmt.Sprintf("%s.%s", signBody, signature)
-	a.expiresAt = expiresAt
-	return nil
+	return fmt.Sprintf("%s.%s", signBody, signature), expiresAt, nil
 }
@@ -0,0 +1,175 @@
+package enablebankinggo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stepClock is a clock advancing by step on every call of Now.
+type stepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func newTestAuthorizer(tb testing.TB) *authorizer {
+	tb.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return newAuthorizer("app", privateKey, ClientDefaultTokenTTL, ClientDefaultTokenTTLExtraTime)
+}
+
+func TestAuthorizerSignsOnceWhenExpired(t *testing.T) {
+	a := newTestAuthorizer(t)
+
+	var signed atomic.Int32
+	a.sign = func(privateKey *rsa.PrivateKey, data []byte) (string, error) {
+		signed.Add(1)
+		// Widen the refresh window, so concurrent callers observe the in-flight refresh.
+		time.Sleep(10 * time.Millisecond)
+		return sign(privateKey, data)
+	}
+
+	// An expired token.
+	a.token = "expired"
+	a.expiresAt = time.Now().Add(-time.Minute)
+
+	const n = 50
+	start := make(chan struct{})
+	tokens := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			tokens[i], errs[i] = a.getToken(context.Background())
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	if got := signed.Load(); got != 1 {
+		t.Fatalf("expected exactly one JWT to be signed, got %d", got)
+	}
+
+	for i := range n {
+		if errs[i] != nil {
+			t.Fatalf("getToken failed: %v", errs[i])
+		}
+
+		if tokens[i] == "expired" || tokens[i] != tokens[0] {
+			t.Fatalf("expected all callers to get the refreshed token, got %q and %q", tokens[i], tokens[0])
+		}
+	}
+}
+
+func TestAuthorizerRecoversFromSignPanic(t *testing.T) {
+	a := newTestAuthorizer(t)
+
+	var panicked atomic.Bool
+	a.sign = func(privateKey *rsa.PrivateKey, data []byte) (string, error) {
+		if panicked.CompareAndSwap(false, true) {
+			panic("sign failed")
+		}
+
+		return sign(privateKey, data)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected getToken to panic")
+			}
+		}()
+
+		_, _ = a.getToken(context.Background())
+	}()
+
+	// A panicked refresh must not leave later callers waiting for it.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := a.getToken(ctx); err != nil {
+		t.Fatalf("getToken failed after a panicked refresh: %v", err)
+	}
+}
+
+func BenchmarkAuthorizeRequestParallel(b *testing.B) {
+	a := newTestAuthorizer(b)
+
+	// Every call of Now advances a second, so the token expires repeatedly during the benchmark.
+	a.clock = &stepClock{now: time.Now(), step: time.Second}
+	a.token = "expired"
+	a.expiresAt = time.Now().Add(-time.Minute)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		req, err := http.NewRequest(http.MethodGet, ClientDefaultAPIBaseURL+"/application", nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+
+		for pb.Next() {
+			if err := a.AuthorizeRequest(req); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func TestAuthorizerCache(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewAuthorizerCache()
+	newClient := func(options ...ClientOption) *APIClient {
+		client, err := NewClient("app", privateKey, append(options, WithAuthorizerCache(cache))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return client
+	}
+
+	a, b := newClient(), newClient()
+	if a.authorizer != b.authorizer {
+		t.Error("expected clients of the same application to share the authorizer")
+	}
+
+	if c := newClient(WithClock(&stepClock{now: time.Now()})); c.authorizer == a.authorizer {
+		t.Error("expected a client with another clock not to share the authorizer")
+	}
+
+	if d, err := NewClient("app", privateKey); err != nil || d.authorizer == a.authorizer {
+		t.Error("expected a client without the cache not to share the authorizer")
+	}
+}
@@ -0,0 +1,201 @@
+// Package bankdata provides a vendor-neutral model of bank accounts, balances and transactions, with
+// converters from Enable Banking models and the NextGenPSD2 (Berlin Group) shapes used by other
+// aggregators, allowing downstream code to be shared between providers.
+package bankdata
+
+import (
+	"strings"
+	"time"
+)
+
+// Provider represents the provider of bank data.
+type Provider string
+
+const (
+	// EnableBankingProvider is the Enable Banking API.
+	EnableBankingProvider Provider = "enablebanking"
+
+	// BerlinGroupProvider is a provider using the NextGenPSD2 (Berlin Group) data model.
+	BerlinGroupProvider Provider = "berlingroup"
+)
+
+// AccountType represents the type of an account.
+type AccountType string
+
+const (
+	CurrentAccountType AccountType = "current"
+	SavingsAccountType AccountType = "savings"
+	CardAccountType    AccountType = "card"
+	LoanAccountType    AccountType = "loan"
+	OtherAccountType   AccountType = "other"
+)
+
+// BalanceKind represents the kind of a balance.
+type BalanceKind string
+
+const (
+	// BookedBalanceKind is a balance of booked transactions, e.g. closing or interim booked.
+	BookedBalanceKind BalanceKind = "booked"
+
+	// AvailableBalanceKind is a balance available for spending, e.g. closing or interim available.
+	AvailableBalanceKind BalanceKind = "available"
+
+	// OtherBalanceKind is any other balance, e.g. expected or information.
+	OtherBalanceKind BalanceKind = "other"
+)
+
+// TransactionStatus represents the status of a transaction.
+type TransactionStatus string
+
+const (
+	BookedTransactionStatus   TransactionStatus = "booked"
+	PendingTransactionStatus  TransactionStatus = "pending"
+	RejectedTransactionStatus TransactionStatus = "rejected"
+	OtherTransactionStatus    TransactionStatus = "other"
+)
+
+// Amount represents a monetary amount.
+type Amount struct {
+	// Value is the signed decimal amount, e.g. -12.30, negative for debits.
+	Value string `json:"value"`
+
+	// Currency is the ISO 4217 currency code.
+	Currency string `json:"currency"`
+}
+
+// IsNegative returns whether the amount is negative.
+func (a Amount) IsNegative() bool {
+	return strings.HasPrefix(strings.TrimSpace(a.Value), "-")
+}
+
+// Abs returns the absolute value of the amount.
+func (a Amount) Abs() Amount {
+	value := strings.TrimSpace(a.Value)
+	value = strings.TrimPrefix(value, "-")
+	value = strings.TrimPrefix(value, "+")
+
+	return Amount{Value: value, Currency: a.Currency}
+}
+
+// Account represents a bank account.
+type Account struct {
+	// ID is the provider's identifier of the account.
+	ID string `json:"id"`
+
+	// Provider is the provider of the account data.
+	Provider Provider `json:"provider"`
+
+	// IBAN is the IBAN of the account, if any.
+	IBAN string `json:"iban,omitempty"`
+
+	// OtherIdentification is a non-IBAN identification of the account, e.g. BBAN or masked PAN.
+	OtherIdentification string `json:"other_identification,omitempty"`
+
+	// Name is the name of the account, e.g. given by the account holder or the bank.
+	Name string `json:"name,omitempty"`
+
+	// HolderName is the name of the account holder.
+	HolderName string `json:"holder_name,omitempty"`
+
+	// Product is the bank's product name of the account.
+	Product string `json:"product,omitempty"`
+
+	// Type is the type of the account.
+	Type AccountType `json:"type"`
+
+	// Currency is the ISO 4217 currency code of the account.
+	Currency string `json:"currency"`
+
+	// BIC is the BIC of the bank servicing the account.
+	BIC string `json:"bic,omitempty"`
+
+	// BankName is the name of the bank servicing the account.
+	BankName string `json:"bank_name,omitempty"`
+}
+
+// Balance represents a balance of an account.
+type Balance struct {
+	// AccountID is the identifier of the account.
+	AccountID string `json:"account_id"`
+
+	// Kind is the kind of balance.
+	Kind BalanceKind `json:"kind"`
+
+	// ProviderType is the provider's balance type, e.g. CLBD or closingBooked.
+	ProviderType string `json:"provider_type,omitempty"`
+
+	// Amount is the balance amount.
+	Amount Amount `json:"amount"`
+
+	// ReferenceDate is the date of the balance, if known.
+	ReferenceDate time.Time `json:"reference_date,omitzero"`
+}
+
+// Transaction represents a transaction of an account.
+type Transaction struct {
+	// ID is the provider's identifier of the transaction, if any.
+	ID string `json:"id,omitempty"`
+
+	// AccountID is the identifier of the account.
+	AccountID string `json:"account_id"`
+
+	// Status is the status of the transaction.
+	Status TransactionStatus `json:"status"`
+
+	// Amount is the signed amount, negative for debits.
+	Amount Amount `json:"amount"`
+
+	// BookingDate is the booking date, if any.
+	BookingDate time.Time `json:"booking_date,omitzero"`
+
+	// ValueDate is the value date, if any.
+	ValueDate time.Time `json:"value_date,omitzero"`
+
+	// CounterpartyName is the name of the creditor of debits and the debtor of credits.
+	CounterpartyName string `json:"counterparty_name,omitempty"`
+
+	// CounterpartyIBAN is the IBAN of the creditor of debits and the debtor of credits.
+	CounterpartyIBAN string `json:"counterparty_iban,omitempty"`
+
+	// Description is the unstructured remittance information.
+	Description string `json:"description,omitempty"`
+
+	// Reference is the structured creditor reference, if any.
+	Reference string `json:"reference,omitempty"`
+
+	// MerchantCategoryCode is the ISO 18245 merchant category code of card transactions.
+	MerchantCategoryCode string `json:"merchant_category_code,omitempty"`
+
+	// OriginalAmount is the instructed amount of currency exchanged transactions.
+	OriginalAmount *Amount `json:"original_amount,omitempty"`
+
+	// ExchangeRate is the exchange rate of currency exchanged transactions.
+	ExchangeRate string `json:"exchange_rate,omitempty"`
+}
+
+func parseDate(date string) time.Time {
+	if len(date) > len(time.DateOnly) {
+		date = date[:len(time.DateOnly)]
+	}
+
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+func signed(value string, negative bool) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "+")
+	if !negative {
+		return value
+	}
+
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		return rest
+	}
+
+	return "-" + value
+}
@@ -0,0 +1,211 @@
+package bankdata
+
+import "strings"
+
+// BerlinGroupAmount represents a NextGenPSD2 amount.
+type BerlinGroupAmount struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// BerlinGroupAccountReference represents a NextGenPSD2 account reference.
+type BerlinGroupAccountReference struct {
+	IBAN      string `json:"iban,omitempty"`
+	BBAN      string `json:"bban,omitempty"`
+	MaskedPan string `json:"maskedPan,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+// BerlinGroupAccount represents a NextGenPSD2 account, e.g. as returned by GET /accounts/{id} or
+// /accounts/{id}/details of NextGenPSD2 based aggregators.
+type BerlinGroupAccount struct {
+	ResourceID      string `json:"resourceId"`
+	IBAN            string `json:"iban,omitempty"`
+	BBAN            string `json:"bban,omitempty"`
+	MaskedPan       string `json:"maskedPan,omitempty"`
+	Currency        string `json:"currency"`
+	Name            string `json:"name,omitempty"`
+	DisplayName     string `json:"displayName,omitempty"`
+	OwnerName       string `json:"ownerName,omitempty"`
+	Product         string `json:"product,omitempty"`
+	CashAccountType string `json:"cashAccountType,omitempty"`
+	BIC             string `json:"bic,omitempty"`
+}
+
+// BerlinGroupBalance represents a NextGenPSD2 balance.
+type BerlinGroupBalance struct {
+	BalanceAmount      BerlinGroupAmount `json:"balanceAmount"`
+	BalanceType        string            `json:"balanceType"`
+	ReferenceDate      string            `json:"referenceDate,omitempty"`
+	LastChangeDateTime string            `json:"lastChangeDateTime,omitempty"`
+}
+
+// BerlinGroupExchangeRate represents a NextGenPSD2 exchange rate.
+type BerlinGroupExchangeRate struct {
+	SourceCurrency string `json:"sourceCurrency"`
+	ExchangeRate   string `json:"exchangeRate"`
+	UnitCurrency   string `json:"unitCurrency,omitempty"`
+	TargetCurrency string `json:"targetCurrency,omitempty"`
+}
+
+// BerlinGroupTransaction represents a NextGenPSD2 transaction. The amount is signed, negative for
+// debits.
+type BerlinGroupTransaction struct {
+	TransactionID                     string                       `json:"transactionId,omitempty"`
+	EntryReference                    string                       `json:"entryReference,omitempty"`
+	InternalTransactionID             string                       `json:"internalTransactionId,omitempty"`
+	BookingDate                       string                       `json:"bookingDate,omitempty"`
+	ValueDate                         string                       `json:"valueDate,omitempty"`
+	TransactionAmount                 BerlinGroupAmount            `json:"transactionAmount"`
+	CurrencyExchange                  []*BerlinGroupExchangeRate   `json:"currencyExchange,omitempty"`
+	CreditorName                      string                       `json:"creditorName,omitempty"`
+	CreditorAccount                   *BerlinGroupAccountReference `json:"creditorAccount,omitempty"`
+	DebtorName                        string                       `json:"debtorName,omitempty"`
+	DebtorAccount                     *BerlinGroupAccountReference `json:"debtorAccount,omitempty"`
+	RemittanceInformationUnstructured string                       `json:"remittanceInformationUnstructured,omitempty"`
+	RemittanceInformationStructured   string                       `json:"remittanceInformationStructured,omitempty"`
+	MerchantCategoryCode              string                       `json:"merchantCategoryCode,omitempty"`
+}
+
+// BerlinGroupTransactions represents the NextGenPSD2 booked and pending transaction lists.
+type BerlinGroupTransactions struct {
+	Booked  []*BerlinGroupTransaction `json:"booked"`
+	Pending []*BerlinGroupTransaction `json:"pending,omitempty"`
+}
+
+// FromBerlinGroupAccount converts a NextGenPSD2 account to the neutral model.
+func FromBerlinGroupAccount(account *BerlinGroupAccount) *Account {
+	if account == nil {
+		return nil
+	}
+
+	a := &Account{
+		ID:                  account.ResourceID,
+		Provider:            BerlinGroupProvider,
+		IBAN:                account.IBAN,
+		OtherIdentification: account.BBAN,
+		Name:                account.Name,
+		HolderName:          account.OwnerName,
+		Product:             account.Product,
+		Type:                OtherAccountType,
+		Currency:            account.Currency,
+		BIC:                 account.BIC,
+	}
+
+	if a.Name == "" {
+		a.Name = account.DisplayName
+	}
+
+	if a.OtherIdentification == "" {
+		a.OtherIdentification = account.MaskedPan
+	}
+
+	switch account.CashAccountType {
+	case "CACC":
+		a.Type = CurrentAccountType
+	case "SVGS":
+		a.Type = SavingsAccountType
+	case "CARD":
+		a.Type = CardAccountType
+	case "LOAN":
+		a.Type = LoanAccountType
+	}
+
+	return a
+}
+
+// FromBerlinGroupBalance converts a NextGenPSD2 balance of the account with the provided ID to the
+// neutral model.
+func FromBerlinGroupBalance(accountID string, balance *BerlinGroupBalance) *Balance {
+	if balance == nil {
+		return nil
+	}
+
+	b := &Balance{
+		AccountID:     accountID,
+		Kind:          OtherBalanceKind,
+		ProviderType:  balance.BalanceType,
+		Amount:        Amount{Value: strings.TrimSpace(balance.BalanceAmount.Amount), Currency: balance.BalanceAmount.Currency},
+		ReferenceDate: parseDate(balance.ReferenceDate),
+	}
+
+	if b.ReferenceDate.IsZero() {
+		b.ReferenceDate = parseDate(balance.LastChangeDateTime)
+	}
+
+	switch balance.BalanceType {
+	case "closingBooked", "interimBooked", "openingBooked":
+		b.Kind = BookedBalanceKind
+	case "interimAvailable", "closingAvailable", "openingAvailable", "forwardAvailable":
+		b.Kind = AvailableBalanceKind
+	}
+
+	return b
+}
+
+// FromBerlinGroupTransaction converts a NextGenPSD2 transaction of the account with the provided ID and
+// status to the neutral model.
+func FromBerlinGroupTransaction(accountID string, status TransactionStatus, tx *BerlinGroupTransaction) *Transaction {
+	if tx == nil {
+		return nil
+	}
+
+	t := &Transaction{
+		ID:                   tx.TransactionID,
+		AccountID:            accountID,
+		Status:               status,
+		Amount:               Amount{Value: signed(tx.TransactionAmount.Amount, false), Currency: tx.TransactionAmount.Currency},
+		BookingDate:          parseDate(tx.BookingDate),
+		ValueDate:            parseDate(tx.ValueDate),
+		Description:          tx.RemittanceInformationUnstructured,
+		Reference:            tx.RemittanceInformationStructured,
+		MerchantCategoryCode: tx.MerchantCategoryCode,
+	}
+
+	if t.ID == "" {
+		t.ID = tx.InternalTransactionID
+	}
+
+	if t.ID == "" {
+		t.ID = tx.EntryReference
+	}
+
+	name, account := tx.DebtorName, tx.DebtorAccount
+	if t.Amount.IsNegative() {
+		name, account = tx.CreditorName, tx.CreditorAccount
+	}
+
+	t.CounterpartyName = name
+	if account != nil {
+		t.CounterpartyIBAN = account.IBAN
+	}
+
+	if len(tx.CurrencyExchange) > 0 && tx.CurrencyExchange[0] != nil {
+		t.ExchangeRate = tx.CurrencyExchange[0].ExchangeRate
+	}
+
+	return t
+}
+
+// FromBerlinGroupTransactions converts NextGenPSD2 booked and pending transactions of the account with
+// the provided ID to the neutral model.
+func FromBerlinGroupTransactions(accountID string, transactions *BerlinGroupTransactions) []*Transaction {
+	if transactions == nil {
+		return nil
+	}
+
+	result := make([]*Transaction, 0, len(transactions.Booked)+len(transactions.Pending))
+	for _, tx := range transactions.Booked {
+		if t := FromBerlinGroupTransaction(accountID, BookedTransactionStatus, tx); t != nil {
+			result = append(result, t)
+		}
+	}
+
+	for _, tx := range transactions.Pending {
+		if t := FromBerlinGroupTransaction(accountID, PendingTransactionStatus, tx); t != nil {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,196 @@
+package bankdata
+
+import (
+	"strings"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// FromAccount converts an Enable Banking account to the neutral model.
+func FromAccount(account *enablebankinggo.AccountResource) *Account {
+	if account == nil {
+		return nil
+	}
+
+	a := &Account{
+		ID:       account.UID,
+		Provider: EnableBankingProvider,
+		Name:     account.Name,
+		Product:  account.Product,
+		Type:     accountTypeOf(account.CashAccountType),
+		Currency: account.Currency,
+	}
+
+	if account.AccountID != nil {
+		a.IBAN = account.AccountID.IBAN
+		if account.AccountID.Other != nil {
+			a.OtherIdentification = account.AccountID.Other.Identification
+		}
+	}
+
+	if a.OtherIdentification == "" && a.IBAN == "" {
+		for _, id := range account.AllAccountIDs {
+			if id != nil && id.Identification != "" {
+				a.OtherIdentification = id.Identification
+				break
+			}
+		}
+	}
+
+	if account.AccountServicer != nil {
+		a.BIC = account.AccountServicer.BICFI
+		a.BankName = account.AccountServicer.Name
+	}
+
+	if a.ID == "" {
+		a.ID = account.IdentificationHash
+	}
+
+	return a
+}
+
+// FromBalance converts an Enable Banking balance of the account with the provided ID to the neutral
+// model.
+func FromBalance(accountID string, balance *enablebankinggo.BalanceResource) *Balance {
+	if balance == nil {
+		return nil
+	}
+
+	b := &Balance{
+		AccountID:     accountID,
+		Kind:          balanceKindOf(balance.BalanceType),
+		ProviderType:  string(balance.BalanceType),
+		ReferenceDate: parseDate(balance.ReferenceDate),
+	}
+
+	if balance.BalanceAmmount != nil {
+		b.Amount = Amount{Value: strings.TrimSpace(balance.BalanceAmmount.Amount), Currency: balance.BalanceAmmount.Currency}
+	}
+
+	if b.ReferenceDate.IsZero() && balance.LastChangeDateTime != nil {
+		b.ReferenceDate = parseDate(balance.LastChangeDateTime.Format("2006-01-02"))
+	}
+
+	return b
+}
+
+// FromTransaction converts an Enable Banking transaction of the account with the provided ID to the
+// neutral model.
+func FromTransaction(accountID string, tx *enablebankinggo.Transaction) *Transaction {
+	if tx == nil {
+		return nil
+	}
+
+	t := &Transaction{
+		ID:                   tx.TransactionID,
+		AccountID:            accountID,
+		Status:               transactionStatusOf(tx.Status),
+		BookingDate:          parseDate(tx.BookingDate),
+		ValueDate:            parseDate(tx.ValueDate),
+		Description:          strings.Join(tx.RemittanceInformation, " "),
+		Reference:            tx.ReferenceNumber,
+		MerchantCategoryCode: tx.MerchantCategoryCode,
+	}
+
+	if t.ID == "" {
+		t.ID = tx.EntryReference
+	}
+
+	if t.BookingDate.IsZero() {
+		t.BookingDate = parseDate(tx.TransactionDate)
+	}
+
+	debit := tx.CreditDebitIndicator == enablebankinggo.DebitCreditDebitIndicator
+	if tx.TransactionAmount != nil {
+		t.Amount = Amount{Value: signed(tx.TransactionAmount.Amount, debit), Currency: tx.TransactionAmount.Currency}
+	}
+
+	party, account := tx.Creditor, tx.CreditorAccount
+	if !debit {
+		party, account = tx.Debtor, tx.DebtorAccount
+	}
+
+	if party != nil {
+		t.CounterpartyName = party.Name
+	}
+
+	if account != nil {
+		t.CounterpartyIBAN = account.IBAN
+	}
+
+	if rate := tx.ExchangeRate; rate != nil {
+		t.ExchangeRate = rate.ExchangeRate
+		if rate.InstructedAmount != nil {
+			t.OriginalAmount = &Amount{Value: signed(rate.InstructedAmount.Amount, debit), Currency: rate.InstructedAmount.Currency}
+		}
+	}
+
+	return t
+}
+
+// FromBalances converts Enable Banking balances of the account with the provided ID to the neutral
+// model.
+func FromBalances(accountID string, balances []*enablebankinggo.BalanceResource) []*Balance {
+	result := make([]*Balance, 0, len(balances))
+	for _, balance := range balances {
+		if b := FromBalance(accountID, balance); b != nil {
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// FromTransactions converts Enable Banking transactions of the account with the provided ID to the
+// neutral model.
+func FromTransactions(accountID string, transactions []*enablebankinggo.Transaction) []*Transaction {
+	result := make([]*Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if t := FromTransaction(accountID, tx); t != nil {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+func accountTypeOf(cashAccountType enablebankinggo.CashAccountType) AccountType {
+	switch cashAccountType {
+	case enablebankinggo.CurrentCashAccountType:
+		return CurrentAccountType
+	case enablebankinggo.SavingsCashAccountType:
+		return SavingsAccountType
+	case enablebankinggo.CardPaymentCashAccountType:
+		return CardAccountType
+	case enablebankinggo.LoanCashAccountType:
+		return LoanAccountType
+	}
+
+	return OtherAccountType
+}
+
+func balanceKindOf(balanceType enablebankinggo.BalanceType) BalanceKind {
+	switch balanceType {
+	case enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType,
+		enablebankinggo.OpeningBookedBalanceType, enablebankinggo.PreviouslyClosedBookedBalanceType:
+		return BookedBalanceKind
+	case enablebankinggo.ClosingAvailableBalanceType, enablebankinggo.InterimAvailableBalanceType,
+		enablebankinggo.OpeningAvailableBalanceType, enablebankinggo.ForwardAvailableBalanceType:
+		return AvailableBalanceKind
+	}
+
+	return OtherBalanceKind
+}
+
+func transactionStatusOf(status enablebankinggo.TransactionStatus) TransactionStatus {
+	switch status {
+	case enablebankinggo.AccountedTransactionStatus, "":
+		return BookedTransactionStatus
+	case enablebankinggo.InstantBalanceTransactionStatus, enablebankinggo.HoldTransactionStatus, enablebankinggo.ScheduledTransactionStatus:
+		return PendingTransactionStatus
+	case enablebankinggo.RejectedTransactionStatus, enablebankinggo.CancelledTransactionStatus:
+		return RejectedTransactionStatus
+	}
+
+	return OtherTransactionStatus
+}
@@ -0,0 +1,236 @@
+// Package cache provides opt-in caching of the read-only API endpoints, i.e. application, ASPSPs and
+// account details, using a pluggable cache backend with per-endpoint TTLs, reducing latency and rate
+// limit pressure.
+//
+// [Memory] is an in-memory backend. Other backends implement [Cache], e.g. a Redis backend shared by
+// several processes using github.com/redis/go-redis:
+//
+//	type redisCache struct {
+//		client *redis.Client
+//	}
+//
+//	func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+//		value, err := c.client.Get(ctx, key).Bytes()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, false, nil
+//		}
+//
+//		return value, err == nil, err
+//	}
+//
+//	func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return c.client.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (c *redisCache) Delete(ctx context.Context, key string) error {
+//		return c.client.Del(ctx, key).Err()
+//	}
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// DefaultKeyPrefix is the default prefix of cache keys.
+	DefaultKeyPrefix = "enablebanking:"
+
+	// DefaultApplicationTTL is the default TTL of the application.
+	DefaultApplicationTTL = time.Hour
+
+	// DefaultASPSPsTTL is the default TTL of the list of ASPSPs.
+	DefaultASPSPsTTL = 24 * time.Hour
+
+	// DefaultAccountDetailsTTL is the default TTL of account details.
+	DefaultAccountDetailsTTL = time.Hour
+)
+
+// Cache is a cache backend.
+type Cache interface {
+	// Get returns the value of key, and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set sets the value of key, expiring after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete deletes key.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config represents the configuration of the caching clients.
+type Config struct {
+	// Cache is the cache backend. Required.
+	Cache Cache
+
+	// KeyPrefix is the prefix of cache keys, e.g. to separate applications sharing a cache backend.
+	// Defaults to DefaultKeyPrefix.
+	KeyPrefix string
+
+	// ApplicationTTL is the TTL of the application. Defaults to DefaultApplicationTTL, negative
+	// disables caching.
+	ApplicationTTL time.Duration
+
+	// ASPSPsTTL is the TTL of the list of ASPSPs. Defaults to DefaultASPSPsTTL, negative disables
+	// caching.
+	ASPSPsTTL time.Duration
+
+	// AccountDetailsTTL is the TTL of account details. Defaults to DefaultAccountDetailsTTL, negative
+	// disables caching.
+	AccountDetailsTTL time.Duration
+}
+
+func (c Config) withDefaults() (Config, error) {
+	if c.Cache == nil {
+		return c, errors.New("config.Cache cannot be nil")
+	}
+
+	if c.KeyPrefix == "" {
+		c.KeyPrefix = DefaultKeyPrefix
+	}
+
+	if c.ApplicationTTL == 0 {
+		c.ApplicationTTL = DefaultApplicationTTL
+	}
+
+	if c.ASPSPsTTL == 0 {
+		c.ASPSPsTTL = DefaultASPSPsTTL
+	}
+
+	if c.AccountDetailsTTL == 0 {
+		c.AccountDetailsTTL = DefaultAccountDetailsTTL
+	}
+
+	return c, nil
+}
+
+var _ enablebankinggo.MiscClient = (*MiscClient)(nil)
+
+// MiscClient is a [enablebankinggo.MiscClient] caching the application and the list of ASPSPs.
+type MiscClient struct {
+	next   enablebankinggo.MiscClient
+	config Config
+}
+
+// NewMiscClient creates a new caching client of the miscellaneous API operations of next.
+func NewMiscClient(next enablebankinggo.MiscClient, config Config) (*MiscClient, error) {
+	if next == nil {
+		return nil, errors.New("next cannot be nil")
+	}
+
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MiscClient{next: next, config: config}, nil
+}
+
+// GetApplication returns the cached application, or retrieves and caches it.
+func (c *MiscClient) GetApplication(ctx context.Context) (*enablebankinggo.GetApplicationResponse, error) {
+	return cached(ctx, c.config, c.config.ApplicationTTL, c.config.KeyPrefix+"application", func() (*enablebankinggo.GetApplicationResponse, error) {
+		return c.next.GetApplication(ctx)
+	})
+}
+
+// GetASPSPs returns the cached list of ASPSPs of params, or retrieves and caches it.
+func (c *MiscClient) GetASPSPs(ctx context.Context, params *enablebankinggo.GetASPSPsRequestParams) (*enablebankinggo.GetASPSPsResponse, error) {
+	key := c.config.KeyPrefix + "aspsps"
+	if params != nil {
+		key += ":" + strings.Join([]string{params.CountryQueryParam, string(params.PSUTypeQueryParam), string(params.ServiceQueryParam)}, ":")
+	}
+
+	return cached(ctx, c.config, c.config.ASPSPsTTL, key, func() (*enablebankinggo.GetASPSPsResponse, error) {
+		return c.next.GetASPSPs(ctx, params)
+	})
+}
+
+var _ enablebankinggo.AccountsDataClient = (*AccountsDataClient)(nil)
+
+// AccountsDataClient is a [enablebankinggo.AccountsDataClient] caching account details. Balances and
+// transactions aren't cached.
+type AccountsDataClient struct {
+	next   enablebankinggo.AccountsDataClient
+	config Config
+}
+
+// NewAccountsDataClient creates a new caching client of the accounts data API operations of next.
+func NewAccountsDataClient(next enablebankinggo.AccountsDataClient, config Config) (*AccountsDataClient, error) {
+	if next == nil {
+		return nil, errors.New("next cannot be nil")
+	}
+
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountsDataClient{next: next, config: config}, nil
+}
+
+// GetAccountDetails returns the cached details of an account, or retrieves and caches them. Requests
+// with headers, e.g. PSU headers, aren't cached.
+func (c *AccountsDataClient) GetAccountDetails(ctx context.Context, accountID string, params *enablebankinggo.GetAccountDetailsRequestParams) (*enablebankinggo.AccountResource, error) {
+	if accountID == "" || (params != nil && len(params.Headers) > 0) {
+		return c.next.GetAccountDetails(ctx, accountID, params)
+	}
+
+	return cached(ctx, c.config, c.config.AccountDetailsTTL, c.accountDetailsKey(accountID), func() (*enablebankinggo.AccountResource, error) {
+		return c.next.GetAccountDetails(ctx, accountID, params)
+	})
+}
+
+// InvalidateAccountDetails deletes the cached details of an account.
+func (c *AccountsDataClient) InvalidateAccountDetails(ctx context.Context, accountID string) error {
+	return c.config.Cache.Delete(ctx, c.accountDetailsKey(accountID))
+}
+
+// GetAccountBalances retrieves balances of a specific account.
+func (c *AccountsDataClient) GetAccountBalances(ctx context.Context, accountID string, params *enablebankinggo.GetAccountBalancesRequestParams) (*enablebankinggo.HalBalances, error) {
+	return c.next.GetAccountBalances(ctx, accountID, params)
+}
+
+// GetAccountTransactions retrieves transactions of a specific account.
+func (c *AccountsDataClient) GetAccountTransactions(ctx context.Context, accountID string, params *enablebankinggo.GetAccountTransactionsRequestParams) (*enablebankinggo.HalTransactions, error) {
+	return c.next.GetAccountTransactions(ctx, accountID, params)
+}
+
+// GetTransactionDetails retrieves details of a specific transaction for a specific account.
+func (c *AccountsDataClient) GetTransactionDetails(ctx context.Context, accountID string, transactionID string, params *enablebankinggo.GetTransactionDetailsRequestParams) (*enablebankinggo.Transaction, error) {
+	return c.next.GetTransactionDetails(ctx, accountID, transactionID, params)
+}
+
+func (c *AccountsDataClient) accountDetailsKey(accountID string) string {
+	return c.config.KeyPrefix + "account_details:" + accountID
+}
+
+// cached returns the cached value of key, or retrieves it using fetch and caches it for ttl. Cache
+// errors are ignored, falling back to fetch.
+func cached[T any](ctx context.Context, config Config, ttl time.Duration, key string, fetch func() (*T, error)) (*T, error) {
+	if ttl < 0 {
+		return fetch()
+	}
+
+	if data, ok, err := config.Cache.Get(ctx, key); err == nil && ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return &value, nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		_ = config.Cache.Set(ctx, key, data, ttl)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// DefaultChangeStateTTL is the default TTL of the stored content hashes of a [ChangeTracker].
+const DefaultChangeStateTTL = 30 * 24 * time.Hour
+
+// ChangeTrackerConfig represents the configuration of a [ChangeTracker].
+type ChangeTrackerConfig struct {
+	// Cache stores the content hashes, e.g. a Redis cache shared by a fleet of workers. Required.
+	Cache Cache
+
+	// KeyPrefix is the prefix of cache keys. Defaults to DefaultKeyPrefix.
+	KeyPrefix string
+
+	// MinInterval is the minimum interval between requests of an endpoint. Syncs within the interval
+	// of the last request, by any worker sharing the cache, are skipped without requesting, if set.
+	MinInterval time.Duration
+
+	// StateTTL is the TTL of the stored content hashes. Defaults to DefaultChangeStateTTL.
+	StateTTL time.Duration
+}
+
+// ChangeTracker fetches the application and the list of ASPSPs, calling a handler only if the response
+// changed since the last processed response, based on a content hash stored in the cache, reducing
+// the startup cost of fleets of workers processing the catalog. The API doesn't provide cache
+// validators, so responses are requested unless skipped by MinInterval.
+type ChangeTracker struct {
+	next   enablebankinggo.MiscClient
+	config ChangeTrackerConfig
+}
+
+// NewChangeTracker creates a new change tracker of the application and ASPSPs of next.
+func NewChangeTracker(next enablebankinggo.MiscClient, config ChangeTrackerConfig) (*ChangeTracker, error) {
+	if next == nil {
+		return nil, errors.New("next cannot be nil")
+	}
+
+	if config.Cache == nil {
+		return nil, errors.New("config.Cache cannot be nil")
+	}
+
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultKeyPrefix
+	}
+
+	if config.StateTTL <= 0 {
+		config.StateTTL = DefaultChangeStateTTL
+	}
+
+	return &ChangeTracker{next: next, config: config}, nil
+}
+
+// SyncApplication retrieves the application and calls handle if it changed, returning whether it
+// changed. The content hash is only stored if handle succeeds, so a failed handler is retried by the
+// next sync.
+func (t *ChangeTracker) SyncApplication(ctx context.Context, handle func(ctx context.Context, app *enablebankinggo.GetApplicationResponse) error) (bool, error) {
+	return syncChanges(ctx, t, t.config.KeyPrefix+"hash:application", func() (*enablebankinggo.GetApplicationResponse, error) {
+		return t.next.GetApplication(ctx)
+	}, handle)
+}
+
+// SyncASPSPs retrieves the list of ASPSPs of params and calls handle if it changed, returning whether
+// it changed, see SyncApplication.
+func (t *ChangeTracker) SyncASPSPs(ctx context.Context, params *enablebankinggo.GetASPSPsRequestParams, handle func(ctx context.Context, aspsps *enablebankinggo.GetASPSPsResponse) error) (bool, error) {
+	key := t.config.KeyPrefix + "hash:aspsps"
+	if params != nil {
+		key += ":" + strings.Join([]string{params.CountryQueryParam, string(params.PSUTypeQueryParam), string(params.ServiceQueryParam)}, ":")
+	}
+
+	return syncChanges(ctx, t, key, func() (*enablebankinggo.GetASPSPsResponse, error) {
+		return t.next.GetASPSPs(ctx, params)
+	}, handle)
+}
+
+// changeState represents the stored state of an endpoint.
+type changeState struct {
+	Hash        string    `json:"hash"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func syncChanges[T any](ctx context.Context, t *ChangeTracker, key string, fetch func() (*T, error), handle func(ctx context.Context, value *T) error) (bool, error) {
+	if handle == nil {
+		return false, errors.New("handle cannot be nil")
+	}
+
+	var state changeState
+	if data, ok, err := t.config.Cache.Get(ctx, key); err == nil && ok {
+		_ = json.Unmarshal(data, &state)
+	}
+
+	if t.config.MinInterval > 0 && time.Since(state.RequestedAt) < t.config.MinInterval {
+		return false, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return false, err
+	}
+
+	// Encoding the decoded response is deterministic, unlike the raw response, e.g. whitespace.
+	content, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	changed := hash != state.Hash
+	if changed {
+		if err := handle(ctx, value); err != nil {
+			return true, err
+		}
+	}
+
+	data, err := json.Marshal(&changeState{Hash: hash, RequestedAt: time.Now()})
+	if err != nil {
+		return changed, err
+	}
+
+	return changed, t.config.Cache.Set(ctx, key, data, t.config.StateTTL)
+}
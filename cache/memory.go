@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Cache = (*Memory)(nil)
+
+// Memory is an in-memory [Cache]. Expired entries are removed when accessed or by Prune.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemory creates a new in-memory cache.
+func NewMemory() *Memory {
+	return &Memory{
+		entries: map[string]*memoryEntry{},
+	}
+}
+
+// Get returns the value of key, and whether it was found and not expired.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !time.Now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Set sets the value of key, expiring after ttl.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = &memoryEntry{
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return nil
+}
+
+// Delete deletes key.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+// Prune removes expired entries.
+func (m *Memory) Prune() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
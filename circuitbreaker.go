@@ -0,0 +1,219 @@
+package enablebankinggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is the default number of consecutive failures opening a circuit.
+	DefaultCircuitBreakerThreshold = 5
+
+	// DefaultCircuitBreakerCooldown is the default time a circuit is open before probing.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned, without requesting the API, when the circuit of a request is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitState represents the state of a circuit.
+type CircuitState int
+
+const (
+	// CircuitClosed is a healthy circuit allowing requests.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen is a circuit rejecting requests with ErrCircuitOpen, until the cooldown elapsed.
+	CircuitOpen
+
+	// CircuitHalfOpen is a circuit allowing a single probe request, closing the circuit if it succeeds
+	// and opening it again if it fails.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig represents the configuration of the circuit breaker of a client.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failures opening a circuit. Defaults to
+	// DefaultCircuitBreakerThreshold.
+	Threshold int
+
+	// Cooldown is the time a circuit is open before probing. Defaults to
+	// DefaultCircuitBreakerCooldown.
+	Cooldown time.Duration
+
+	// Key returns the circuit of a request. Defaults to CircuitKeyByEndpoint, use CircuitKeyByASPSP for
+	// a circuit per ASPSP.
+	Key func(req *http.Request) string
+
+	// IsFailure returns whether an error is a failure counted by the circuit. Defaults to
+	// IsASPSPFailure.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called when the state of a circuit changes, e.g. for alerting.
+	OnStateChange func(key string, from, to CircuitState)
+}
+
+// WithCircuitBreaker enables a circuit breaker, rejecting requests with ErrCircuitOpen after
+// consecutive ASPSP failures, to stop hammering a degraded ASPSP.
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(c *APIClient) {
+		c.circuitBreaker = newCircuitBreaker(config)
+	}
+}
+
+// IsASPSPFailure returns whether err is an ASPSP_ERROR or ASPSP_TIMEOUT error response.
+func IsASPSPFailure(err error) bool {
+	errResp, ok := IsErrorResponse(err)
+	return ok && (errResp.ErrorCode == ASPSPErrorErrorCode || errResp.ErrorCode == ASPSPTimeoutErrorCode)
+}
+
+// CircuitKeyByEndpoint returns the method and path of req with IDs replaced, e.g.
+// "GET /accounts/{id}/transactions", i.e. a circuit per endpoint.
+func CircuitKeyByEndpoint(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		segments[i] = "{id}"
+	}
+
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
+// CircuitKeyByASPSP returns the ASPSP of the request context, see [ContextWithASPSP], i.e. a circuit
+// per ASPSP, falling back to CircuitKeyByEndpoint.
+func CircuitKeyByASPSP(req *http.Request) string {
+	if aspsp, ok := req.Context().Value(aspspContextKey{}).(ASPSP); ok && aspsp.Name != "" {
+		return aspsp.Country + "/" + aspsp.Name
+	}
+
+	return CircuitKeyByEndpoint(req)
+}
+
+type aspspContextKey struct{}
+
+// ContextWithASPSP returns a context with the ASPSP requests are made for, used by CircuitKeyByASPSP.
+func ContextWithASPSP(ctx context.Context, aspsp ASPSP) context.Context {
+	return context.WithValue(ctx, aspspContextKey{}, aspsp)
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	clock  Clock
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultCircuitBreakerThreshold
+	}
+
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCircuitBreakerCooldown
+	}
+
+	if config.Key == nil {
+		config.Key = CircuitKeyByEndpoint
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = IsASPSPFailure
+	}
+
+	return &circuitBreaker{config: config, clock: SystemClock, circuits: map[string]*circuit{}}
+}
+
+// allow returns ErrCircuitOpen if the circuit of key rejects a request, transitioning open circuits
+// to half-open when the cooldown elapsed.
+func (b *circuitBreaker) allow(key string) error {
+	b.mu.Lock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+
+	from := c.state
+	switch c.state {
+	case CircuitOpen:
+		if b.clock.Now().Sub(c.openedAt) < b.config.Cooldown {
+			b.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, key)
+		}
+
+		c.state = CircuitHalfOpen
+		c.probing = true
+	case CircuitHalfOpen:
+		if c.probing {
+			b.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, key)
+		}
+
+		c.probing = true
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(key, from, to)
+	return nil
+}
+
+// record records the result of an allowed request. Successes, i.e. no error or an error response that
+// isn't a failure, close the circuit. Other errors, e.g. cancelled requests and network errors, leave the
+// circuit unchanged and only release the probe of a half-open circuit.
+func (b *circuitBreaker) record(key string, err error) {
+	b.mu.Lock()
+	c := b.circuits[key]
+	from := c.state
+	c.probing = false
+
+	_, isErrResp := IsErrorResponse(err)
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+	case err != nil && b.config.IsFailure(err):
+		c.failures++
+		if c.state == CircuitHalfOpen || c.failures >= b.config.Threshold {
+			c.state = CircuitOpen
+			c.openedAt = b.clock.Now()
+		}
+	case err == nil || isErrResp:
+		c.failures = 0
+		c.state = CircuitClosed
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(key, from, to)
+}
+
+func (b *circuitBreaker) notify(key string, from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(key, from, to)
+	}
+}
@@ -2,14 +2,20 @@ package enablebankinggo
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/rsa"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +31,9 @@ const (
 
 	// ClientDefaultTokenTTLExtraTime is the extra time added to the token TTL to account for clock skew.
 	ClientDefaultTokenTTLExtraTime = 10 * time.Second
+
+	// ClientDefaultTimeout is the default timeout of requests made by the client.
+	ClientDefaultTimeout = 30 * time.Second
 )
 
 // ClientOption represents a configuration option for the client.
@@ -37,17 +46,29 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client for the Enable Banking API client.
+// WithHTTPClient sets a custom HTTP client for the Enable Banking API client. Defaults to a client created by
+// [NewHTTPClient].
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *APIClient) {
 		c.httpClient = httpClient
 	}
 }
 
-// WithHTTPTransport sets a custom HTTP transport for the client.
+// WithHTTPTransport sets a custom HTTP transport for the client. The HTTP client is copied, leaving a client set
+// using [WithHTTPClient] unchanged.
 func WithHTTPTransport(transport http.RoundTripper) ClientOption {
 	return func(c *APIClient) {
-		c.httpClient.Transport = transport
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request, identifying the application, e.g.
+// "myapp/1.2.0". The library remains identified by appending [DefaultUserAgent].
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *APIClient) {
+		c.userAgent = strings.TrimSpace(userAgent + " " + DefaultUserAgent)
 	}
 }
 
@@ -134,6 +155,22 @@ func WithPSUGeoLocationHeader(geoLocation string) ClientOption {
 	}
 }
 
+type psuHeadersContextKey struct{}
+
+// ContextWithPSUHeaders returns a context with PSU headers included in every request made with it, e.g. attached
+// once per inbound request by middleware of an HTTP service. The headers take precedence over headers of the
+// client, and are overridden by headers of the request parameters.
+func ContextWithPSUHeaders(ctx context.Context, headers Header) context.Context {
+	if existing, ok := ctx.Value(psuHeadersContextKey{}).(Header); ok {
+		merged := NewHeaders()
+		maps.Copy(merged, existing)
+		maps.Copy(merged, headers)
+		headers = merged
+	}
+
+	return context.WithValue(ctx, psuHeadersContextKey{}, headers)
+}
+
 // NewClientWithKeyFile creates a new Enable Banking API client with the provided application ID, private key file path, and options.
 // If no options are provided, the client will use default settings of [ClientDefaultAPIBaseURL], [ClientDefaultTokenTTL], and [ClientDefaultTokenTTLExtraTime].
 func NewClientWithKeyFile(applicationID, privateKeyPath string, options ...ClientOption) (*APIClient, error) {
@@ -158,25 +195,88 @@ func NewClient(applicationID string, privateKey *rsa.PrivateKey, options ...Clie
 
 	c := &APIClient{
 		baseURL:    ClientDefaultAPIBaseURL,
-		httpClient: http.DefaultClient,
+		httpClient: NewHTTPClient(),
 		headers:    NewHeaders(),
+		userAgent:  DefaultUserAgent,
 		authorizer: newAuthorizer(applicationID, privateKey, ClientDefaultTokenTTL, ClientDefaultTokenTTLExtraTime),
 	}
 
-	c.httpClient.Timeout = 30 * time.Second
-
 	for _, option := range options {
 		option(c)
 	}
 
+	if err := c.validateEnvironment(); err != nil {
+		return nil, err
+	}
+
+	if err := c.applyTLSOptions(); err != nil {
+		return nil, err
+	}
+
+	if c.clock == nil {
+		c.clock = SystemClock
+	}
+
+	c.authorizer.clock = c.clock
+	if c.circuitBreaker != nil {
+		c.circuitBreaker.clock = c.clock
+	}
+
+	if c.authorizerCache != nil {
+		c.authorizer = c.authorizerCache.get(c.authorizer)
+	}
+
+	if c.codec == nil {
+		c.codec = jsonCodec{strict: c.strictDecoding}
+	}
+
 	return c, nil
 }
 
 type APIClient struct {
-	baseURL    string
-	httpClient *http.Client
-	headers    Header
-	authorizer *authorizer
+	baseURL     string
+	httpClient  *http.Client
+	headers     Header
+	userAgent   string
+	authorizer  *authorizer
+	logger      *slog.Logger
+	debugLogger *slog.Logger
+	observer    RequestObserver
+	codec       Codec
+	clock       Clock
+
+	environment Environment
+
+	paymentStatusNotifier PaymentStatusNotifier
+	circuitBreaker        *circuitBreaker
+	tlsOptions            []func(config *tls.Config)
+
+	connectionDiagnostics bool
+	authorizerCache       *AuthorizerCache
+	strictDecoding        bool
+}
+
+// Do sends a request to an API endpoint without a typed method, e.g. endpoints not yet supported by the client,
+// using the authorization, headers and error handling of the client. The path is relative to the base URL, query
+// is added to the URL if not nil, body is encoded as JSON if not nil and the response is decoded into out if not nil.
+// API errors are returned as [*ErrorResponse].
+func (c *APIClient) Do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	if len(query) > 0 {
+		queryParams := req.URL.Query()
+		for k, values := range query {
+			for _, v := range values {
+				queryParams.Add(k, v)
+			}
+		}
+		req.URL.RawQuery = queryParams.Encode()
+	}
+
+	return c.sendRequest(req, out)
 }
 
 func (c *APIClient) newRequest(ctx context.Context, method, url string, reqBody any) (*http.Request, error) {
@@ -184,22 +284,36 @@ func (c *APIClient) newRequest(ctx context.Context, method, url string, reqBody
 		url = "/" + url
 	}
 
-	var body io.Reader
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+url, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	if reqBody != nil {
-		jsonData, err := json.Marshal(reqBody)
+		body, err := c.encodeRequestBody(reqBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		body = bytes.NewReader(jsonData)
-	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+url, body)
-	if err != nil {
-		return nil, err
+		req.ContentLength = int64(body.buf.Len())
+		req.Body = body.reader()
+		req.GetBody = func() (io.ReadCloser, error) {
+			return body.reader(), nil
+		}
 	}
 
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(RequestIDHeader, requestID(ctx))
 	c.headers.FillHTTPHeader(req.Header)
 
+	if headers, ok := ctx.Value(psuHeadersContextKey{}).(Header); ok {
+		headers.FillHTTPHeader(req.Header)
+	}
+
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
 	if reqBody != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -212,30 +326,226 @@ func (c *APIClient) newRequest(ctx context.Context, method, url string, reqBody
 	return req, nil
 }
 
-func (c *APIClient) sendRequest(req *http.Request, resp any) error {
-	response, err := c.httpClient.Do(req)
+func (c *APIClient) sendRequest(req *http.Request, resp any) (err error) {
+	if c.circuitBreaker != nil {
+		key := c.circuitBreaker.config.Key(req)
+		if err := c.circuitBreaker.allow(key); err != nil {
+			return err
+		}
+		defer func() {
+			c.circuitBreaker.record(key, err)
+		}()
+	}
+
+	start := time.Now()
+	var response *http.Response
+	var tracer *connectionTracer
+	if c.connectionDiagnostics {
+		req, tracer = traceRequest(req)
+	}
+	defer func() {
+		c.observeRequest(req, response, time.Since(start), tracer.connectionInfo(), err)
+	}()
+
+	if body, ok := req.Body.(*requestBodyReader); ok {
+		defer body.body.release()
+	}
+
+	if c.debugLogger != nil {
+		c.dumpRequest(req)
+	}
+
+	response, err = c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
+	err = decompressResponse(response)
+	if err != nil {
+		return err
+	}
+
+	if c.debugLogger != nil {
+		err = c.dumpResponse(req, response)
+		if err != nil {
+			return err
+		}
+	}
+
+	requestID := req.Header.Get(RequestIDHeader)
 	if response.StatusCode < 200 || response.StatusCode > 500 {
-		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		return fmt.Errorf("unexpected status code: %d (request ID %s)", response.StatusCode, requestID)
 	}
 
-	if response.StatusCode != 200 {
+	if response.StatusCode >= 300 {
 		var errResp ErrorResponse
 		err = json.NewDecoder(response.Body).Decode(&errResp)
 		if err != nil {
-			return fmt.Errorf("unexpected API error: status code %d", response.StatusCode)
+			return fmt.Errorf("unexpected API error: status code %d (request ID %s)", response.StatusCode, requestID)
 		}
 
+		errResp.RequestID = requestID
 		return &errResp
 	}
 
-	if resp != nil {
-		return json.NewDecoder(response.Body).Decode(resp)
+	// Empty and 204 responses leave resp unchanged, e.g. endpoints without a response body.
+	if resp != nil && response.StatusCode != http.StatusNoContent && response.ContentLength != 0 {
+		if dec, ok := resp.(responseDecoder); ok {
+			return dec.decodeResponse(response.Body, c.strictDecoding)
+		}
+
+		return decodeResponse(response, resp, c.codec)
+	}
+
+	return nil
+}
+
+// decompressResponse replaces the body of a gzip encoded response with a decompressing reader. Since the client sets
+// Accept-Encoding itself, the transport doesn't decompress responses, regardless of its DisableCompression.
+func decompressResponse(response *http.Response) error {
+	if response.Uncompressed || !strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	body, err := gzip.NewReader(response.Body)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			// Empty body, e.g. HEAD requests or 204 responses.
+			response.Body = http.NoBody
+			return nil
+		}
+
+		return fmt.Errorf("failed to decompress response: %w", err)
 	}
 
+	response.Body = body
+	response.ContentLength = -1
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.Uncompressed = true
+
 	return nil
 }
+
+// responseDecoder is implemented by response targets decoding the body themselves, e.g. streaming decoders.
+type responseDecoder interface {
+	decodeResponse(body io.Reader, strict bool) error
+}
+
+// maxPooledBufferSize is the maximum capacity of buffers returned to the pool, avoiding retaining memory of
+// exceptionally large requests and responses.
+const maxPooledBufferSize = 8 << 20
+
+// bufferPool pools request and response buffers, since encoding and decoding, e.g. transactions and high
+// throughput payment and authorization requests, otherwise allocates and grows a buffer per request.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}
+}
+
+// requestBody is a request body encoded into a pooled buffer. Since the transport may read the body more than
+// once, e.g. retries and redirects using GetBody, the buffer is only returned to the pool by release when every
+// reader of it is closed.
+type requestBody struct {
+	buf *bytes.Buffer
+
+	mu      sync.Mutex
+	readers int
+}
+
+// encodeRequestBody encodes v into a pooled buffer, using the codec of the client if set by [WithCodec].
+func (c *APIClient) encodeRequestBody(v any) (*requestBody, error) {
+	buf := getBuffer()
+
+	if _, ok := c.codec.(jsonCodec); ok {
+		if err := json.NewEncoder(buf).Encode(v); err != nil {
+			putBuffer(buf)
+			return nil, err
+		}
+
+		// Trim the newline added by Encode, matching json.Marshal.
+		buf.Truncate(buf.Len() - 1)
+	} else {
+		data, err := c.codec.Marshal(v)
+		if err != nil {
+			putBuffer(buf)
+			return nil, err
+		}
+
+		buf.Write(data)
+	}
+
+	return &requestBody{buf: buf}, nil
+}
+
+func (b *requestBody) reader() *requestBodyReader {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.readers++
+	return &requestBodyReader{Reader: bytes.NewReader(b.buf.Bytes()), body: b}
+}
+
+// release returns the buffer to the pool once the request has completed, unless a reader is still open, e.g. when
+// the transport is still writing the body, leaving the buffer to the garbage collector.
+func (b *requestBody) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.readers == 0 && b.buf != nil {
+		putBuffer(b.buf)
+	}
+
+	b.buf = nil
+}
+
+type requestBodyReader struct {
+	*bytes.Reader
+	body *requestBody
+	once sync.Once
+}
+
+func (r *requestBodyReader) Close() error {
+	r.once.Do(func() {
+		r.body.mu.Lock()
+		defer r.body.mu.Unlock()
+
+		r.body.readers--
+	})
+
+	return nil
+}
+
+// decodeResponse reads the response body into a pooled buffer and decodes it into v using codec. An empty body
+// leaves v unchanged.
+func decodeResponse(response *http.Response, v any, codec Codec) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if response.ContentLength > 0 && response.ContentLength <= maxPooledBufferSize {
+		buf.Grow(int(response.ContentLength))
+	}
+
+	if _, err := buf.ReadFrom(response.Body); err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil
+	}
+
+	return codec.Unmarshal(buf.Bytes(), v)
+}
@@ -0,0 +1,136 @@
+package enablebankinggo
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func newTestClient(tb testing.TB) *APIClient {
+	tb.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	client, err := NewClient("app", privateKey, WithBaseURL("http://localhost"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return client
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	client := newTestClient(b)
+	ctx := context.Background()
+
+	benchmarks := []struct {
+		name string
+		path string
+		body any
+	}{
+		{
+			name: "payment",
+			path: "/payments",
+			body: &CreatePaymentRequest{
+				PaymentType: SepaPaymentType,
+				PaymentRequest: &PaymentRequestResource{
+					CreditTransferTransaction: []*CreditTransferTransaction{
+						{
+							InstructedAmount: &AmountType{Amount: "12.50", Currency: "EUR"},
+							Beneficiary: &Beneficiary{
+								Creditor:        &PartyIdentification{Name: "Creditor"},
+								CreditorAccount: NewIBANAccountIdentification("FI14 1009 3000 1234 58"),
+							},
+						},
+					},
+				},
+				ASPSP:       ASPSP{Name: "Nordea", Country: "FI"},
+				State:       "state",
+				RedirectURL: "https://example.com/callback",
+				PSUType:     PersonalPSUType,
+			},
+		},
+		{
+			name: "auth",
+			path: "/auth",
+			body: &StartAuthorizationRequest{
+				Access:      &Access{Balances: true, Transactions: true},
+				ASPSP:       ASPSP{Name: "Nordea", Country: "FI"},
+				State:       "state",
+				RedirectURL: "https://example.com/callback",
+				PSUType:     PersonalPSUType,
+			},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				req, err := client.newRequest(ctx, http.MethodPost, bm.path, bm.body)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				body := req.Body.(*requestBodyReader)
+				_ = body.Close()
+				body.body.release()
+			}
+		})
+	}
+}
+
+func TestRequestBodyNotReusedWhileReaderOpen(t *testing.T) {
+	client := newTestClient(t)
+
+	req, err := client.newRequest(context.Background(), http.MethodPost, "/auth", &StartAuthorizationRequest{State: "state"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = req.Body.Close()
+
+	// A reader still open when the request completes, e.g. a retry by the transport.
+	reader, err := req.GetBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := reader.(*requestBodyReader).body
+	buf := body.buf
+	body.release()
+
+	for range 100 {
+		pooled := getBuffer()
+		if pooled == buf {
+			t.Fatal("buffer returned to the pool while a reader is open")
+		}
+
+		pooled.WriteString("overwritten")
+		defer putBuffer(pooled)
+	}
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("got body %q, want %q", got, want)
+	}
+
+	_ = reader.Close()
+}
@@ -0,0 +1,34 @@
+package enablebankinggo
+
+import "time"
+
+// Clock provides the current time and timers, allowing token expiry, circuit breaker cooldowns and polling to be
+// tested deterministically using a fake clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel receiving the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the [Clock] of the system time, used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock sets the clock of the client, used for token expiry, circuit breaker cooldowns and polling of
+// [APIClient.WaitForPaymentStatus]. Defaults to [SystemClock].
+func WithClock(clock Clock) ClientOption {
+	return func(c *APIClient) {
+		c.clock = clock
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+func runASPSPs(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("aspsps")
+	country := fs.String("country", "", "two-letter country code")
+	psuType := fs.String("psu-type", "", "PSU type, personal or business")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	resp, err := a.client.GetASPSPs(ctx, &enablebankinggo.GetASPSPsRequestParams{
+		CountryQueryParam: strings.ToUpper(*country),
+		PSUTypeQueryParam: enablebankinggo.PSUType(*psuType),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp.ASPSPs, func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tCOUNTRY\tBIC\tPSU TYPES\tMAX CONSENT DAYS\tBETA")
+		for _, aspsp := range resp.ASPSPs {
+			psuTypes := make([]string, 0, len(aspsp.PSUTypes))
+			for _, psuType := range aspsp.PSUTypes {
+				psuTypes = append(psuTypes, string(psuType))
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%t\n", aspsp.Name, aspsp.Country, aspsp.BIC, strings.Join(psuTypes, ","),
+				aspsp.MaximumConsentValidity/(24*60*60), aspsp.Beta)
+		}
+	})
+}
+
+func runAuth(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("auth")
+	aspsp := fs.String("aspsp", "", "ASPSP name")
+	country := fs.String("country", "", "two-letter country code of the ASPSP")
+	redirectURL := fs.String("redirect-url", "", "redirect URL registered with the application")
+	state := fs.String("state", "ebgo", "state returned in the redirect")
+	psuType := fs.String("psu-type", string(enablebankinggo.PersonalPSUType), "PSU type, personal or business")
+	validDays := fs.Int("valid-days", 90, "consent validity in days")
+	language := fs.String("language", "", "preferred PSU language")
+	if err := a.parse(fs, args, "aspsp", "country", "redirect-url"); err != nil {
+		return err
+	}
+
+	resp, err := a.client.StartAuthorization(ctx, &enablebankinggo.StartAuthorizationRequest{
+		Access: &enablebankinggo.Access{
+			ValidUntil: time.Now().AddDate(0, 0, *validDays).UTC().Format(time.RFC3339),
+		},
+		ASPSP:       enablebankinggo.ASPSP{Name: *aspsp, Country: strings.ToUpper(*country)},
+		State:       *state,
+		RedirectURL: *redirectURL,
+		PSUType:     enablebankinggo.PSUType(*psuType),
+		Language:    *language,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "Open the URL to authorize, then run ebgo authorize -code CODE with the code of the redirect.")
+		fmt.Fprintf(w, "Authorization ID:\t%s\n", resp.AuthorizationID)
+		fmt.Fprintf(w, "URL:\t%s\n", resp.URL)
+	})
+}
+
+func runAuthorize(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("authorize")
+	code := fs.String("code", "", "authorization code of the redirect")
+	if err := a.parse(fs, args, "code"); err != nil {
+		return err
+	}
+
+	resp, err := a.client.AuthorizeSession(ctx, &enablebankinggo.AuthorizeSessionRequest{Code: *code})
+	if err != nil {
+		return err
+	}
+
+	if a.sessions != nil {
+		session := &storedSession{SessionID: resp.SessionID, ASPSP: resp.ASPSP, PSUType: string(resp.PSUType), CreatedAt: time.Now().UTC()}
+		if resp.Access != nil {
+			session.ValidUntil = resp.Access.ValidUntil
+		}
+
+		if err := a.sessions.add(session); err != nil {
+			return fmt.Errorf("failed to update session store: %w", err)
+		}
+	}
+
+	return a.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "Session ID:\t%s\n", resp.SessionID)
+		if resp.Access != nil {
+			fmt.Fprintf(w, "Valid until:\t%s\n", resp.Access.ValidUntil)
+		}
+		fmt.Fprintln(w)
+		printAccounts(w, resp.Accounts)
+	})
+}
+
+func runAccounts(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("accounts")
+	sessionID := fs.String("session", "", "session ID")
+	if err := a.parse(fs, args, "session"); err != nil {
+		return err
+	}
+
+	session, err := a.client.GetSession(ctx, *sessionID)
+	if err != nil {
+		return err
+	}
+
+	accounts := make([]*enablebankinggo.AccountResource, 0, len(session.Accounts))
+	for _, uid := range session.Accounts {
+		account, err := a.client.GetAccountDetails(ctx, uid, nil)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", uid, err)
+		}
+
+		if account.UID == "" {
+			account.UID = uid
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return a.print(accounts, func(w io.Writer) {
+		printAccounts(w, accounts)
+	})
+}
+
+func printAccounts(w io.Writer, accounts []*enablebankinggo.AccountResource) {
+	fmt.Fprintln(w, "UID\tIBAN\tNAME\tCURRENCY\tTYPE\tPRODUCT")
+	for _, account := range accounts {
+		iban := ""
+		if account.AccountID != nil {
+			iban = account.AccountID.IBAN
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", account.UID, iban, account.Name, account.Currency, account.CashAccountType, account.Product)
+	}
+}
+
+func runBalances(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("balances")
+	accountID := fs.String("account", "", "account UID")
+	if err := a.parse(fs, args, "account"); err != nil {
+		return err
+	}
+
+	resp, err := a.client.GetAccountBalances(ctx, *accountID, nil)
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp.Balances, func(w io.Writer) {
+		fmt.Fprintln(w, "TYPE\tAMOUNT\tCURRENCY\tREFERENCE DATE\tNAME")
+		for _, balance := range resp.Balances {
+			amount, currency := "", ""
+			if balance.BalanceAmmount != nil {
+				amount, currency = balance.BalanceAmmount.Amount, balance.BalanceAmmount.Currency
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", balance.BalanceType, amount, currency, balance.ReferenceDate, balance.Name)
+		}
+	})
+}
+
+func runTransactions(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("transactions")
+	accountID := fs.String("account", "", "account UID")
+	from := fs.String("from", "", "first date, YYYY-MM-DD")
+	to := fs.String("to", "", "last date, YYYY-MM-DD")
+	status := fs.String("status", "", "transaction status, e.g. BOOK or PDNG")
+	all := fs.Bool("all", true, "fetch all pages")
+	if err := a.parse(fs, args, "account"); err != nil {
+		return err
+	}
+
+	params := &enablebankinggo.GetAccountTransactionsRequestParams{
+		TransactionStatusQueryParam: enablebankinggo.TransactionStatus(*status),
+	}
+
+	for _, date := range []struct {
+		value string
+		dest  *time.Time
+	}{{*from, &params.DateFromQueryParam}, {*to, &params.DateToQueryParam}} {
+		if date.value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.DateOnly, date.value)
+		if err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date.value)
+		}
+		*date.dest = t
+	}
+
+	var transactions []*enablebankinggo.Transaction
+	for {
+		resp, err := a.client.GetAccountTransactions(ctx, *accountID, params)
+		if err != nil {
+			return err
+		}
+
+		transactions = append(transactions, resp.Transactions...)
+		if !*all || resp.ContinuationKey == "" {
+			break
+		}
+
+		params.ContinuationKeyQueryParam = resp.ContinuationKey
+	}
+
+	return a.print(transactions, func(w io.Writer) {
+		fmt.Fprintln(w, "BOOKING DATE\tSTATUS\tAMOUNT\tCURRENCY\tCOUNTERPARTY\tREMITTANCE INFORMATION")
+		for _, tx := range transactions {
+			amount, currency := "", ""
+			if tx.TransactionAmount != nil {
+				amount, currency = tx.TransactionAmount.Amount, tx.TransactionAmount.Currency
+				if tx.CreditDebitIndicator == enablebankinggo.DebitCreditDebitIndicator {
+					amount = "-" + amount
+				}
+			}
+
+			counterparty := tx.Creditor
+			if tx.CreditDebitIndicator == enablebankinggo.CreditCreditDebitIndicator {
+				counterparty = tx.Debtor
+			}
+
+			name := ""
+			if counterparty != nil {
+				name = counterparty.Name
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", tx.BookingDate, tx.Status, amount, currency, name, strings.Join(tx.RemittanceInformation, " "))
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Command ebgo is a command line interface of the Enable Banking API for account information
+// operations, e.g. for support, debugging and demos.
+//
+// Usage:
+//
+//	ebgo [global flags] <command> [flags]
+//
+// The application ID and private key are read from the -app-id and -key flags, or the
+// ENABLEBANKING_APPLICATION_ID and ENABLEBANKING_PRIVATE_KEY_FILE environment variables. Sessions
+// authorized using the CLI are recorded in the session store file of the -sessions flag, or the
+// ENABLEBANKING_SESSIONS_FILE environment variable, if set.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	applicationIDEnv  = "ENABLEBANKING_APPLICATION_ID"
+	privateKeyFileEnv = "ENABLEBANKING_PRIVATE_KEY_FILE"
+	baseURLEnv        = "ENABLEBANKING_BASE_URL"
+)
+
+// errUsage is returned on invalid usage, after the usage is printed.
+var errUsage = errors.New("invalid usage")
+
+// command represents a subcommand.
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(ctx context.Context, app *app, args []string) error
+}
+
+// app represents the global state of the CLI.
+type app struct {
+	stdout   io.Writer
+	stderr   io.Writer
+	json     bool
+	client   *enablebankinggo.APIClient
+	sessions *sessionStore
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ebgo", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	applicationID := fs.String("app-id", os.Getenv(applicationIDEnv), "application ID (env "+applicationIDEnv+")")
+	privateKeyFile := fs.String("key", os.Getenv(privateKeyFileEnv), "private key file (env "+privateKeyFileEnv+")")
+	baseURL := fs.String("base-url", envOrDefault(baseURLEnv, enablebankinggo.ClientDefaultAPIBaseURL), "API base URL (env "+baseURLEnv+")")
+	sessionsFile := fs.String("sessions", os.Getenv(sessionsFileEnv), "session store file (env "+sessionsFileEnv+")")
+	jsonOutput := fs.Bool("json", false, "print JSON output")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: ebgo [global flags] <command> [flags]")
+		fmt.Fprintln(stderr, "\nCommands:")
+		w := tabwriter.NewWriter(stderr, 0, 4, 2, ' ', 0)
+		for _, cmd := range commands() {
+			fmt.Fprintf(w, "  %s\t%s\n", cmd.name, cmd.description)
+		}
+		_ = w.Flush()
+		fmt.Fprintln(stderr, "\nGlobal flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return errUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	var cmd *command
+	for _, c := range commands() {
+		if c.name == fs.Arg(0) {
+			cmd = c
+			break
+		}
+	}
+
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+
+	if *applicationID == "" || *privateKeyFile == "" {
+		return errors.New("application ID and private key file are required, see ebgo -h")
+	}
+
+	client, err := enablebankinggo.NewClientWithKeyFile(*applicationID, *privateKeyFile, enablebankinggo.WithBaseURL(*baseURL))
+	if err != nil {
+		return err
+	}
+
+	a := &app{stdout: stdout, stderr: stderr, json: *jsonOutput, client: client}
+	if *sessionsFile != "" {
+		a.sessions = &sessionStore{path: *sessionsFile}
+	}
+
+	return cmd.run(ctx, a, fs.Args()[1:])
+}
+
+func commands() []*command {
+	return []*command{
+		{name: "aspsps", usage: "aspsps [-country FI] [-psu-type personal]", description: "List ASPSPs", run: runASPSPs},
+		{name: "auth", usage: "auth -aspsp NAME -country FI -redirect-url URL [flags]", description: "Start an authorization and print the URL", run: runAuth},
+		{name: "authorize", usage: "authorize -code CODE", description: "Exchange an authorization code for a session", run: runAuthorize},
+		{name: "accounts", usage: "accounts -session ID", description: "List the accounts of a session", run: runAccounts},
+		{name: "balances", usage: "balances -account UID", description: "Get the balances of an account", run: runBalances},
+		{name: "transactions", usage: "transactions -account UID [-from DATE] [-to DATE] [flags]", description: "Get the transactions of an account", run: runTransactions},
+		{name: "session", usage: "session <get|list|delete> [flags]", description: "Inspect, list and delete sessions", run: runSession},
+		{name: "session get", usage: "session get -id ID", description: "Get a session"},
+		{name: "session list", usage: "session list [-status]", description: "List the sessions of the session store"},
+		{name: "session delete", usage: "session delete -id ID | [-aspsp NAME] [-country FI] [-status STATUS] [-expired] [flags]", description: "Delete a session, or the stored sessions matching the filters"},
+	}
+}
+
+// newFlagSet creates the flag set of a command.
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			fs.Usage = func() {
+				fmt.Fprintf(a.stderr, "Usage: ebgo %s\n\n%s.\n\nFlags:\n", cmd.usage, cmd.description)
+				fs.PrintDefaults()
+			}
+		}
+	}
+
+	return fs
+}
+
+// parse parses the flags of a command, requiring the named flags to be set.
+func (a *app) parse(fs *flag.FlagSet, args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	for _, name := range required {
+		if fs.Lookup(name).Value.String() == "" {
+			fmt.Fprintf(a.stderr, "flag -%s is required\n", name)
+			fs.Usage()
+			return errUsage
+		}
+	}
+
+	return nil
+}
+
+// print prints v as JSON if JSON output is enabled, otherwise calls table with a tab writer.
+func (a *app) print(v any, table func(w io.Writer)) error {
+	if a.json {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const sessionsFileEnv = "ENABLEBANKING_SESSIONS_FILE"
+
+// storedSession represents a session in the session store.
+type storedSession struct {
+	SessionID  string                 `json:"session_id"`
+	ASPSP      *enablebankinggo.ASPSP `json:"aspsp,omitempty"`
+	PSUType    string                 `json:"psu_type,omitempty"`
+	ValidUntil string                 `json:"valid_until,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// sessionStore is a session store backed by a JSON file, recording the sessions authorized using the
+// CLI.
+type sessionStore struct {
+	path string
+}
+
+func (s *sessionStore) load() ([]*storedSession, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*storedSession
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode session store %s: %w", s.path, err)
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionStore) save(sessions []*storedSession) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func (s *sessionStore) add(session *storedSession) error {
+	sessions, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	sessions = slices.DeleteFunc(sessions, func(stored *storedSession) bool {
+		return stored.SessionID == session.SessionID
+	})
+
+	return s.save(append(sessions, session))
+}
+
+func (s *sessionStore) remove(sessionIDs ...string) error {
+	sessions, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	return s.save(slices.DeleteFunc(sessions, func(stored *storedSession) bool {
+		return slices.Contains(sessionIDs, stored.SessionID)
+	}))
+}
+
+func runSession(ctx context.Context, a *app, args []string) error {
+	subcommands := map[string]func(ctx context.Context, a *app, args []string) error{
+		"get":    runSessionGet,
+		"list":   runSessionList,
+		"delete": runSessionDelete,
+	}
+
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintln(a.stderr, "Usage: ebgo session <get|list|delete> [flags]")
+		return errUsage
+	}
+
+	return subcommands[args[0]](ctx, a, args[1:])
+}
+
+func runSessionGet(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("session get")
+	sessionID := fs.String("id", "", "session ID")
+	if err := a.parse(fs, args, "id"); err != nil {
+		return err
+	}
+
+	session, err := a.client.GetSession(ctx, *sessionID)
+	if err != nil {
+		return err
+	}
+
+	return a.print(session, func(w io.Writer) {
+		fmt.Fprintf(w, "Status:\t%s\n", session.Status)
+		if session.ASPSP != nil {
+			fmt.Fprintf(w, "ASPSP:\t%s (%s)\n", session.ASPSP.Name, session.ASPSP.Country)
+		}
+		fmt.Fprintf(w, "PSU type:\t%s\n", session.PSUType)
+		fmt.Fprintf(w, "Created:\t%s\n", session.Created.Format(time.RFC3339))
+		if session.Authorized != nil {
+			fmt.Fprintf(w, "Authorized:\t%s\n", session.Authorized.Format(time.RFC3339))
+		}
+		if session.Access != nil {
+			fmt.Fprintf(w, "Valid until:\t%s\n", session.Access.ValidUntil)
+		}
+		if session.Closed != nil {
+			fmt.Fprintf(w, "Closed:\t%s\n", session.Closed.Format(time.RFC3339))
+		}
+		fmt.Fprintf(w, "Accounts:\t%s\n", strings.Join(session.Accounts, ", "))
+	})
+}
+
+// sessionListItem represents a listed session.
+type sessionListItem struct {
+	*storedSession
+	Status enablebankinggo.SessionStatus `json:"status,omitempty"`
+}
+
+func runSessionList(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("session list")
+	withStatus := fs.Bool("status", false, "retrieve the current status of each session")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	items, err := a.listSessions(ctx, *withStatus)
+	if err != nil {
+		return err
+	}
+
+	return a.print(items, func(w io.Writer) {
+		printSessions(w, items)
+	})
+}
+
+func (a *app) listSessions(ctx context.Context, withStatus bool) ([]*sessionListItem, error) {
+	if a.sessions == nil {
+		return nil, errors.New("session store not configured, set -sessions or " + sessionsFileEnv)
+	}
+
+	sessions, err := a.sessions.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*sessionListItem, 0, len(sessions))
+	for _, session := range sessions {
+		item := &sessionListItem{storedSession: session}
+		if withStatus {
+			resp, err := a.client.GetSession(ctx, session.SessionID)
+			if errResp, ok := enablebankinggo.IsErrorResponse(err); ok && errResp.ErrorCode == enablebankinggo.SessionDoesNotExistErrorCode {
+				item.Status = enablebankinggo.ClosedSessionStatus
+			} else if err != nil {
+				return nil, fmt.Errorf("session %s: %w", session.SessionID, err)
+			} else {
+				item.Status = resp.Status
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func printSessions(w io.Writer, items []*sessionListItem) {
+	fmt.Fprintln(w, "SESSION ID\tASPSP\tCOUNTRY\tPSU TYPE\tVALID UNTIL\tSTATUS")
+	for _, item := range items {
+		name, country := "", ""
+		if item.ASPSP != nil {
+			name, country = item.ASPSP.Name, item.ASPSP.Country
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", item.SessionID, name, country, item.PSUType, item.ValidUntil, item.Status)
+	}
+}
+
+func runSessionDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("session delete")
+	sessionID := fs.String("id", "", "session ID")
+	aspsp := fs.String("aspsp", "", "delete stored sessions of the ASPSP")
+	country := fs.String("country", "", "delete stored sessions of ASPSPs of the country")
+	status := fs.String("status", "", "delete stored sessions with the status, e.g. EXPIRED")
+	expired := fs.Bool("expired", false, "delete stored sessions past their validity")
+	dryRun := fs.Bool("dry-run", false, "print the sessions to delete without deleting them")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	filtered := *aspsp != "" || *country != "" || *status != "" || *expired
+	if (*sessionID == "") == !filtered {
+		fmt.Fprintln(a.stderr, "either -id or a filter is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	var items []*sessionListItem
+	if *sessionID != "" {
+		items = []*sessionListItem{{storedSession: &storedSession{SessionID: *sessionID}}}
+	} else {
+		all, err := a.listSessions(ctx, *status != "")
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, item := range all {
+			if matchesSessionFilter(item, *aspsp, *country, *status, *expired, now) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	if *dryRun {
+		return a.print(items, func(w io.Writer) {
+			printSessions(w, items)
+		})
+	}
+
+	var deleted []string
+	var errs []error
+	for _, item := range items {
+		_, err := a.client.DeleteSession(ctx, item.SessionID, nil)
+		if errResp, ok := enablebankinggo.IsErrorResponse(err); ok && errResp.ErrorCode == enablebankinggo.SessionDoesNotExistErrorCode {
+			err = nil
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", item.SessionID, err))
+			continue
+		}
+
+		deleted = append(deleted, item.SessionID)
+		if !a.json {
+			fmt.Fprintf(a.stdout, "Deleted session %s\n", item.SessionID)
+		}
+	}
+
+	if a.sessions != nil && len(deleted) > 0 {
+		if err := a.sessions.remove(deleted...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update session store: %w", err))
+		}
+	}
+
+	if a.json {
+		if err := a.print(deleted, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func matchesSessionFilter(item *sessionListItem, aspsp, country, status string, expired bool, now time.Time) bool {
+	if aspsp != "" && (item.ASPSP == nil || !strings.EqualFold(item.ASPSP.Name, aspsp)) {
+		return false
+	}
+
+	if country != "" && (item.ASPSP == nil || !strings.EqualFold(item.ASPSP.Country, country)) {
+		return false
+	}
+
+	if status != "" && !strings.EqualFold(string(item.Status), status) {
+		return false
+	}
+
+	if expired {
+		validUntil, err := time.Parse(time.RFC3339, item.ValidUntil)
+		if err != nil || validUntil.After(now) {
+			return false
+		}
+	}
+
+	return true
+}
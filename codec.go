@@ -0,0 +1,45 @@
+package enablebankinggo
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Codec encodes request bodies and decodes response bodies, allowing to swap encoding/json for a faster
+// implementation, e.g. jsoniter or encoding/json/v2, in transaction heavy workloads. Implementations must honor
+// [json.Marshaler] and [json.Unmarshaler], used by models for lenient decoding, and be safe for concurrent use.
+type Codec interface {
+	// Marshal returns the JSON encoding of v.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal decodes the JSON encoded data into v.
+	Unmarshal(data []byte, v any) error
+}
+
+// WithCodec sets a custom codec for encoding request bodies and decoding response bodies. Defaults to
+// encoding/json. Error responses are always decoded using encoding/json.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *APIClient) {
+		c.codec = codec
+	}
+}
+
+// jsonCodec is the default codec using encoding/json, rejecting unknown fields if strict, see
+// [WithStrictDecoding].
+type jsonCodec struct {
+	strict bool
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (c jsonCodec) Unmarshal(data []byte, v any) error {
+	if c.strict {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	}
+
+	return json.Unmarshal(data, v)
+}
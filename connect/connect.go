@@ -0,0 +1,400 @@
+// Package connect provides ready-made HTTP handlers for the "connect your bank" flow, i.e. listing
+// ASPSPs, starting authorization, handling the redirect callback and authorizing the session, handing
+// the authorized session to a callback. It's a drop-in backend of a bank connection widget.
+package connect
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/internal/hmactoken"
+)
+
+const (
+	// DefaultStateTTL is the default time the PSU has to complete authorization.
+	DefaultStateTTL = 30 * time.Minute
+
+	// DefaultConsentValidity is the default requested consent validity.
+	DefaultConsentValidity = 90 * 24 * time.Hour
+
+	// DefaultCookieName is the default name of the cookie binding the authorization to the browser of
+	// the PSU.
+	DefaultCookieName = "ebgo_connect"
+
+	// maxRequestBodySize is the maximum size of the request body of the start handler.
+	maxRequestBodySize = 64 << 10
+)
+
+var (
+	// ErrInvalidState is returned when the state of a callback is invalid, expired or belongs to
+	// another PSU or browser.
+	ErrInvalidState = errors.New("invalid or expired state")
+
+	// ErrAuthorizationFailed is returned when the ASPSP redirects with an error, e.g. when the PSU
+	// cancels authorization.
+	ErrAuthorizationFailed = errors.New("authorization failed")
+)
+
+// Client is the API client used by the handlers.
+type Client interface {
+	// GetASPSPs retrieves a list of ASPSPs with their meta information based on provided parameters.
+	GetASPSPs(ctx context.Context, params *enablebankinggo.GetASPSPsRequestParams) (*enablebankinggo.GetASPSPsResponse, error)
+
+	// StartAuthorization starts user authorization with an ASPSP.
+	StartAuthorization(ctx context.Context, req *enablebankinggo.StartAuthorizationRequest) (*enablebankinggo.StartAuthorizationResponse, error)
+
+	// AuthorizeSession authorizes a user session using the authorization code.
+	AuthorizeSession(ctx context.Context, req *enablebankinggo.AuthorizeSessionRequest) (*enablebankinggo.AuthorizeSessionResponse, error)
+}
+
+// Session represents an authorized session.
+type Session struct {
+	// PSUID is the PSU ID the authorization was started with, if any.
+	PSUID string
+
+	// Session is the authorized session.
+	Session *enablebankinggo.AuthorizeSessionResponse
+}
+
+// Config represents the configuration of the [Handlers].
+type Config struct {
+	// Client is the API client. Required.
+	Client Client
+
+	// RedirectURL is the URL of the callback handler, registered as a redirect URL of the application.
+	// Required.
+	RedirectURL string
+
+	// Key is the secret key signing the authorization state, at least 32 bytes. Required.
+	Key []byte
+
+	// StateTTL is the time the PSU has to complete authorization. Defaults to DefaultStateTTL.
+	StateTTL time.Duration
+
+	// ConsentValidity is the requested consent validity, adjusted to the maximum consent validity of
+	// the ASPSP. Defaults to DefaultConsentValidity.
+	ConsentValidity time.Duration
+
+	// PSUID returns the PSU ID of a request, e.g. the user ID of the application session, if set. The
+	// callback is rejected unless it's requested by the PSU that started authorization.
+	PSUID func(r *http.Request) string
+
+	// CookieName is the name of the cookie binding the authorization to the browser that started it,
+	// rejecting callbacks in other browsers. Defaults to DefaultCookieName.
+	CookieName string
+
+	// CookiePath is the path of the cookie, which must cover the callback handler. Defaults to "/".
+	CookiePath string
+
+	// OnSession is called with the authorized session and writes the response of the callback, e.g.
+	// stores the session and redirects the PSU back to the application. Required.
+	OnSession func(w http.ResponseWriter, r *http.Request, session *Session)
+
+	// OnError is called with errors of the callback and writes the response, e.g. redirects the PSU to
+	// an error page. Defaults to writing a JSON error.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Handlers provides the HTTP handlers of the connect flow. Handlers is an [http.Handler] serving:
+//
+//   - GET /aspsps?country=&psu_type= listing ASPSPs supporting account information.
+//   - POST /auth with a JSON body of aspsp_name, aspsp_country, and optionally psu_type and language,
+//     responding with the url to redirect the PSU to.
+//   - GET /callback handling the redirect back from the ASPSP.
+//
+// The start handler sets an HttpOnly cookie with a nonce of the state, verified by the callback handler,
+// so the callback is only accepted in the browser that started authorization.
+//
+// Use [http.StripPrefix] to mount it below a path.
+type Handlers struct {
+	config Config
+	mux    *http.ServeMux
+}
+
+// New creates the handlers of the connect flow.
+func New(config Config) (*Handlers, error) {
+	if config.Client == nil {
+		return nil, errors.New("config.Client cannot be nil")
+	}
+
+	if config.RedirectURL == "" {
+		return nil, errors.New("config.RedirectURL cannot be empty")
+	}
+
+	if len(config.Key) < 32 {
+		return nil, errors.New("config.Key must be at least 32 bytes")
+	}
+
+	if config.OnSession == nil {
+		return nil, errors.New("config.OnSession cannot be nil")
+	}
+
+	if config.StateTTL <= 0 {
+		config.StateTTL = DefaultStateTTL
+	}
+
+	if config.ConsentValidity <= 0 {
+		config.ConsentValidity = DefaultConsentValidity
+	}
+
+	if config.CookieName == "" {
+		config.CookieName = DefaultCookieName
+	}
+
+	if config.CookiePath == "" {
+		config.CookiePath = "/"
+	}
+
+	if config.OnError == nil {
+		config.OnError = func(w http.ResponseWriter, _ *http.Request, err error) {
+			statusCode := http.StatusBadGateway
+			if errors.Is(err, ErrInvalidState) || errors.Is(err, ErrAuthorizationFailed) {
+				statusCode = http.StatusBadRequest
+			}
+
+			writeError(w, statusCode, err.Error())
+		}
+	}
+
+	h := &Handlers{config: config, mux: http.NewServeMux()}
+	h.mux.Handle("GET /aspsps", h.ASPSPs())
+	h.mux.Handle("POST /auth", h.Start())
+	h.mux.Handle("GET /callback", h.Callback())
+
+	return h, nil
+}
+
+// ServeHTTP serves the handlers of the connect flow.
+func (h *Handlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// ASPSPs returns a handler listing the ASPSPs supporting account information, filtered by the country
+// and psu_type query parameters.
+func (h *Handlers) ASPSPs() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := h.config.Client.GetASPSPs(r.Context(), &enablebankinggo.GetASPSPsRequestParams{
+			CountryQueryParam: r.URL.Query().Get("country"),
+			PSUTypeQueryParam: enablebankinggo.PSUType(r.URL.Query().Get("psu_type")),
+			ServiceQueryParam: enablebankinggo.AccountInformationService,
+		})
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "failed to get ASPSPs")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// startRequest represents the request body of the start handler.
+type startRequest struct {
+	ASPSPName    string                  `json:"aspsp_name"`
+	ASPSPCountry string                  `json:"aspsp_country"`
+	PSUType      enablebankinggo.PSUType `json:"psu_type,omitempty"`
+	Language     string                  `json:"language,omitempty"`
+}
+
+// startResponse represents the response body of the start handler.
+type startResponse struct {
+	URL string `json:"url"`
+}
+
+// Start returns a handler starting authorization with an ASPSP, responding with the URL to redirect
+// the PSU to.
+func (h *Handlers) Start() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req startRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		if req.ASPSPName == "" || req.ASPSPCountry == "" {
+			writeError(w, http.StatusBadRequest, "aspsp_name and aspsp_country cannot be empty")
+			return
+		}
+
+		if req.PSUType == "" {
+			req.PSUType = enablebankinggo.PersonalPSUType
+		}
+
+		psuID := h.psuID(r)
+		state, nonce, err := h.newState(psuID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to create state")
+			return
+		}
+
+		resp, err := h.config.Client.StartAuthorization(r.Context(), &enablebankinggo.StartAuthorizationRequest{
+			Access: &enablebankinggo.Access{
+				ValidUntil: time.Now().Add(h.config.ConsentValidity).UTC().Format(time.RFC3339),
+			},
+			ASPSP:       enablebankinggo.ASPSP{Name: req.ASPSPName, Country: req.ASPSPCountry},
+			State:       state,
+			RedirectURL: h.config.RedirectURL,
+			PSUType:     req.PSUType,
+			Language:    req.Language,
+			PSUID:       psuID,
+		})
+		if err != nil {
+			if errResp, ok := enablebankinggo.IsErrorResponse(err); ok && errResp.Code >= 400 && errResp.Code < 500 {
+				writeError(w, http.StatusBadRequest, errResp.Message)
+				return
+			}
+
+			writeError(w, http.StatusBadGateway, "failed to start authorization")
+			return
+		}
+
+		h.setCookie(w, nonce, int(h.config.StateTTL/time.Second))
+		writeJSON(w, http.StatusOK, &startResponse{URL: resp.URL})
+	})
+}
+
+// Callback returns a handler of the redirect back from the ASPSP, verifying the state and authorizing
+// the session using the code, handing the session to OnSession.
+func (h *Handlers) Callback() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		psuID := h.psuID(r)
+
+		var nonce string
+		if cookie, err := r.Cookie(h.config.CookieName); err == nil {
+			nonce = cookie.Value
+		}
+
+		if err := h.verifyState(query.Get("state"), nonce, psuID); err != nil {
+			h.config.OnError(w, r, err)
+			return
+		}
+
+		// The state is used once, whatever the outcome of the authorization.
+		h.setCookie(w, "", -1)
+
+		if errorCode := query.Get("error"); errorCode != "" {
+			description := query.Get("error_description")
+			if description == "" {
+				description = errorCode
+			}
+
+			h.config.OnError(w, r, fmt.Errorf("%w: %s", ErrAuthorizationFailed, description))
+			return
+		}
+
+		code := query.Get("code")
+		if code == "" {
+			h.config.OnError(w, r, fmt.Errorf("%w: code missing", ErrAuthorizationFailed))
+			return
+		}
+
+		resp, err := h.config.Client.AuthorizeSession(r.Context(), &enablebankinggo.AuthorizeSessionRequest{Code: code})
+		if err != nil {
+			h.config.OnError(w, r, fmt.Errorf("failed to authorize session: %w", err))
+			return
+		}
+
+		h.config.OnSession(w, r, &Session{PSUID: psuID, Session: resp})
+	})
+}
+
+func (h *Handlers) psuID(r *http.Request) string {
+	if h.config.PSUID == nil {
+		return ""
+	}
+
+	return h.config.PSUID(r)
+}
+
+// state represents the signed state of an authorization.
+type state struct {
+	Nonce     string `json:"n"`
+	PSUIDHash string `json:"p,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// newState returns a signed state of an authorization started by psuID, and its nonce to be set as the
+// cookie of the browser. The state is passed through the ASPSP, so only a keyed hash of psuID is included.
+func (h *Handlers) newState(psuID string) (string, string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	nonce := hex.EncodeToString(b)
+	value, err := hmactoken.Sign(&state{
+		Nonce:     nonce,
+		PSUIDHash: h.psuIDHash(psuID),
+		ExpiresAt: time.Now().Add(h.config.StateTTL).Unix(),
+	}, h.config.Key)
+	if err != nil {
+		return "", "", err
+	}
+
+	return value, nonce, nil
+}
+
+// verifyState verifies the signature and expiry of value, that it was started by the browser having the
+// nonce cookie and by psuID.
+func (h *Handlers) verifyState(value, nonce, psuID string) error {
+	var s state
+	if err := hmactoken.Parse(value, h.config.Key, &s); err != nil {
+		return ErrInvalidState
+	}
+
+	if time.Now().Unix() > s.ExpiresAt || !hmac.Equal([]byte(s.PSUIDHash), []byte(h.psuIDHash(psuID))) {
+		return ErrInvalidState
+	}
+
+	if nonce == "" || !hmac.Equal([]byte(s.Nonce), []byte(nonce)) {
+		return ErrInvalidState
+	}
+
+	return nil
+}
+
+// setCookie sets the cookie binding the authorization to the browser, or removes it when maxAge is
+// negative. SameSite is lax, since the redirect back from the ASPSP is a cross-site navigation.
+func (h *Handlers) setCookie(w http.ResponseWriter, nonce string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.config.CookieName,
+		Value:    nonce,
+		Path:     h.config.CookiePath,
+		MaxAge:   maxAge,
+		Secure:   strings.HasPrefix(h.config.RedirectURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (h *Handlers) psuIDHash(psuID string) string {
+	if psuID == "" {
+		return ""
+	}
+
+	return hmactoken.MAC("psu:"+psuID, h.config.Key)
+}
+
+// errorResponse represents the response body of errors.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, &errorResponse{Message: message})
+}
@@ -0,0 +1,359 @@
+// Package consent provides a manager tracking the consent validity of stored sessions, transitioning
+// them through states using GetSession, session status notifications and the consent expiry time, and
+// notifying callbacks prompting renewal.
+package consent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/events"
+)
+
+// DefaultExpiringSoonThreshold is the default duration before consent expiry a consent is expiring
+// soon.
+const DefaultExpiringSoonThreshold = 7 * 24 * time.Hour
+
+// ErrSessionNotTracked is returned when a session isn't tracked.
+var ErrSessionNotTracked = errors.New("session not tracked")
+
+// State represents the state of a consent.
+type State string
+
+const (
+	// PendingState is a consent not yet authorized by the PSU.
+	PendingState State = "pending"
+
+	// ActiveState is an authorized consent.
+	ActiveState State = "active"
+
+	// ExpiringSoonState is an authorized consent expiring within the expiring soon threshold.
+	ExpiringSoonState State = "expiring_soon"
+
+	// ExpiredState is an expired consent.
+	ExpiredState State = "expired"
+
+	// RevokedState is a consent revoked by the PSU or the ASPSP.
+	RevokedState State = "revoked"
+
+	// ClosedState is a consent closed by the application, cancelled or invalid.
+	ClosedState State = "closed"
+)
+
+// NeedsRenewal returns whether a consent in the state should be renewed by the PSU.
+func (s State) NeedsRenewal() bool {
+	return s == ExpiringSoonState || s == ExpiredState || s == RevokedState
+}
+
+// Consent represents the tracked consent of a session.
+type Consent struct {
+	// SessionID is the session ID.
+	SessionID string
+
+	// State is the state of the consent.
+	State State
+
+	// Status is the last known status of the session.
+	Status enablebankinggo.SessionStatus
+
+	// ASPSP is the ASPSP of the session, if known.
+	ASPSP *enablebankinggo.ASPSP
+
+	// ValidUntil is the time the consent expires, if known.
+	ValidUntil time.Time
+
+	// UpdatedAt is the time the state was last evaluated.
+	UpdatedAt time.Time
+}
+
+// Event represents a state transition of a consent.
+type Event struct {
+	// From is the previous state, empty when a session is first tracked.
+	From State
+
+	// To is the new state.
+	To State
+
+	// Consent is the consent after the transition.
+	Consent Consent
+}
+
+// Config represents the configuration of a [Manager].
+type Config struct {
+	// Client is used to retrieve sessions. Required.
+	Client enablebankinggo.UserSessionsClient
+
+	// ExpiringSoonThreshold is the duration before consent expiry a consent is expiring soon. Defaults
+	// to DefaultExpiringSoonThreshold.
+	ExpiringSoonThreshold time.Duration
+
+	// OnChange is called on every state transition, e.g. to prompt the PSU to renew the consent.
+	// Called without holding locks of the manager.
+	OnChange func(Event)
+
+	// Publisher publishes session events on transitions to authorized, expired, revoked and closed
+	// states, if set. Publish errors are ignored.
+	Publisher events.Publisher
+}
+
+// Manager tracks the consents of sessions.
+type Manager struct {
+	config Config
+
+	mu       sync.Mutex
+	consents map[string]*Consent
+}
+
+// NewManager creates a new consent manager.
+func NewManager(config Config) (*Manager, error) {
+	if config.Client == nil {
+		return nil, errors.New("config.Client cannot be nil")
+	}
+
+	if config.ExpiringSoonThreshold <= 0 {
+		config.ExpiringSoonThreshold = DefaultExpiringSoonThreshold
+	}
+
+	return &Manager{
+		config:   config,
+		consents: map[string]*Consent{},
+	}, nil
+}
+
+// Track starts tracking the consent of a session, retrieving the session. The session isn't tracked
+// if it can't be retrieved.
+func (m *Manager) Track(ctx context.Context, sessionID string) (*Consent, error) {
+	if sessionID == "" {
+		return nil, errors.New("sessionID cannot be empty")
+	}
+
+	m.mu.Lock()
+	_, tracked := m.consents[sessionID]
+	if !tracked {
+		m.consents[sessionID] = &Consent{SessionID: sessionID}
+	}
+	m.mu.Unlock()
+
+	consent, err := m.refresh(ctx, sessionID, tracked)
+	if err != nil && !tracked {
+		m.Untrack(sessionID)
+	}
+
+	return consent, err
+}
+
+// Untrack stops tracking the consent of a session.
+func (m *Manager) Untrack(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.consents, sessionID)
+}
+
+// Consent returns the tracked consent of a session.
+func (m *Manager) Consent(sessionID string) (*Consent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.consents[sessionID]
+	if !ok {
+		return nil, ErrSessionNotTracked
+	}
+
+	consent := *c
+	return &consent, nil
+}
+
+// Consents returns the tracked consents, sorted by session ID.
+func (m *Manager) Consents() []*Consent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	consents := make([]*Consent, 0, len(m.consents))
+	for _, c := range m.consents {
+		consent := *c
+		consents = append(consents, &consent)
+	}
+
+	slices.SortFunc(consents, func(a, b *Consent) int {
+		return strings.Compare(a.SessionID, b.SessionID)
+	})
+
+	return consents
+}
+
+// Refresh retrieves a tracked session and updates the state of its consent. Sessions that no longer
+// exist are closed.
+func (m *Manager) Refresh(ctx context.Context, sessionID string) (*Consent, error) {
+	return m.refresh(ctx, sessionID, true)
+}
+
+func (m *Manager) refresh(ctx context.Context, sessionID string, closeMissing bool) (*Consent, error) {
+	resp, err := m.config.Client.GetSession(ctx, sessionID)
+	if err != nil {
+		if errResp, ok := enablebankinggo.IsErrorResponse(err); ok && closeMissing && errResp.ErrorCode == enablebankinggo.SessionDoesNotExistErrorCode {
+			return m.update(ctx, events.PollingSource, sessionID, func(c *Consent) {
+				c.Status = enablebankinggo.ClosedSessionStatus
+			})
+		}
+
+		return nil, err
+	}
+
+	return m.update(ctx, events.PollingSource, sessionID, func(c *Consent) {
+		c.Status = resp.Status
+		c.ASPSP = resp.ASPSP
+		if resp.Access != nil {
+			if validUntil, err := time.Parse(time.RFC3339, resp.Access.ValidUntil); err == nil {
+				c.ValidUntil = validUntil
+			}
+		}
+	})
+}
+
+// RefreshAll refreshes all tracked sessions, returning the errors joined.
+func (m *Manager) RefreshAll(ctx context.Context) error {
+	var errs []error
+	for _, c := range m.Consents() {
+		_, err := m.Refresh(ctx, c.SessionID)
+		if err != nil {
+			if errors.Is(err, ErrSessionNotTracked) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("session %s: %w", c.SessionID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetStatus updates the state of a tracked consent from a session status notification, e.g. received
+// by a webhook, without retrieving the session.
+func (m *Manager) SetStatus(sessionID string, status enablebankinggo.SessionStatus) (*Consent, error) {
+	return m.update(context.Background(), events.WebhookSource, sessionID, func(c *Consent) {
+		c.Status = status
+	})
+}
+
+// Evaluate updates the states of all tracked consents based on the current time, e.g. transitioning
+// active consents to expiring soon and expired, without retrieving sessions.
+func (m *Manager) Evaluate() {
+	for _, c := range m.Consents() {
+		_, _ = m.update(context.Background(), events.PollingSource, c.SessionID, func(*Consent) {})
+	}
+}
+
+// Run evaluates the tracked consents every interval and refreshes all sessions every refreshInterval,
+// unless zero, until ctx is done. Refresh errors are ignored, the sessions are refreshed next interval.
+func (m *Manager) Run(ctx context.Context, interval, refreshInterval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastRefresh time.Time
+	for {
+		if refreshInterval > 0 && time.Since(lastRefresh) >= refreshInterval {
+			_ = m.RefreshAll(ctx)
+			lastRefresh = time.Now()
+		}
+
+		m.Evaluate()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// update applies fn to a tracked consent, evaluates its state and notifies OnChange and Publisher of a
+// transition.
+func (m *Manager) update(ctx context.Context, source events.Source, sessionID string, fn func(c *Consent)) (*Consent, error) {
+	m.mu.Lock()
+	c, ok := m.consents[sessionID]
+	if !ok {
+		m.mu.Unlock()
+		return nil, ErrSessionNotTracked
+	}
+
+	from := c.State
+	fn(c)
+	c.UpdatedAt = time.Now()
+	c.State = m.stateOf(c, c.UpdatedAt)
+	consent := *c
+	m.mu.Unlock()
+
+	if from == consent.State {
+		return &consent, nil
+	}
+
+	if m.config.OnChange != nil {
+		m.config.OnChange(Event{From: from, To: consent.State, Consent: consent})
+	}
+
+	if m.config.Publisher != nil {
+		if eventType, ok := eventTypeOf(from, consent.State); ok {
+			_ = m.config.Publisher.Publish(ctx, &events.Event{
+				Type:      eventType,
+				Source:    source,
+				SessionID: sessionID,
+				Status:    string(consent.Status),
+			})
+		}
+	}
+
+	return &consent, nil
+}
+
+// eventTypeOf returns the type of session event published on a transition, if any.
+func eventTypeOf(from, to State) (events.Type, bool) {
+	switch to {
+	case ActiveState, ExpiringSoonState:
+		if from == "" || from == PendingState {
+			return events.SessionAuthorizedType, true
+		}
+	case ExpiredState:
+		return events.SessionExpiredType, true
+	case RevokedState:
+		return events.SessionRevokedType, true
+	case ClosedState:
+		return events.SessionClosedType, true
+	}
+
+	return "", false
+}
+
+func (m *Manager) stateOf(c *Consent, now time.Time) State {
+	switch c.Status {
+	case enablebankinggo.RevokedSessionStatus:
+		return RevokedState
+	case enablebankinggo.ExpiredSessionStatus:
+		return ExpiredState
+	case enablebankinggo.ClosedSessionStatus, enablebankinggo.CancelledSessionStatus, enablebankinggo.InvalidSessionStatus:
+		return ClosedState
+	case enablebankinggo.AuthorizedSessionStatus:
+		switch {
+		case c.ValidUntil.IsZero():
+			return ActiveState
+		case !now.Before(c.ValidUntil):
+			return ExpiredState
+		case c.ValidUntil.Sub(now) <= m.config.ExpiringSoonThreshold:
+			return ExpiringSoonState
+		}
+
+		return ActiveState
+	}
+
+	return PendingState
+}
@@ -2,12 +2,28 @@ package controlpanel
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/marefr/enablebankinggo"
 )
 
+// ApplicationServices represents the countries and services enabled for an application.
+type ApplicationServices struct {
+	// Countries is the list of countries enabled for the application.
+	Countries []enablebankinggo.Country `json:"countries"`
+
+	// Services is the list of services enabled for the application.
+	Services []enablebankinggo.Service `json:"services"`
+}
+
+// DefaultLinkApplicationAccountRedirectURL is the default URL the PSU is redirected to after linking an
+// application account, see [LinkApplicationAccountRequest].
+const DefaultLinkApplicationAccountRedirectURL = "https://enablebanking.com/api/auth_redirect"
+
 // RegisterApplicationRequest represents the request payload for registering a new application.
 type RegisterApplicationRequest struct {
 	// Environment is the application environment.
@@ -43,10 +59,50 @@ type RegisterApplicationResponse struct {
 
 // LinkApplicationAccountRequest represents the request payload for linking an application account.
 type LinkApplicationAccountRequest struct {
-	Country string `json:"country"`
-	Aspsp   string `json:"aspsp"`
-	AppID   string `json:"appId"`
-	PsuType string `json:"psuType"`
+	// Country is the country of the ASPSP.
+	Country enablebankinggo.Country `json:"country"`
+
+	// Aspsp is the name of the ASPSP.
+	Aspsp string `json:"aspsp"`
+
+	// AppID is the ID of the application to link the account to.
+	AppID string `json:"appId"`
+
+	// PsuType is the PSU type of the account.
+	PsuType enablebankinggo.PSUType `json:"psuType"`
+
+	// RedirectURL is the URL the PSU is redirected to after the account has been linked.
+	// Defaults to [DefaultLinkApplicationAccountRedirectURL] if empty.
+	RedirectURL string `json:"redirectUrl,omitempty"`
+}
+
+// Validate validates the request and returns a descriptive error for the first invalid field.
+func (r *LinkApplicationAccountRequest) Validate() error {
+	if r.Country.IsEmpty() {
+		return errors.New("country cannot be empty")
+	}
+
+	if !r.Country.IsValid() {
+		return fmt.Errorf("country %q is not a valid two-letter ISO 3166 country code", r.Country)
+	}
+
+	if r.Aspsp == "" {
+		return errors.New("aspsp cannot be empty")
+	}
+
+	if r.AppID == "" {
+		return errors.New("appId cannot be empty")
+	}
+
+	if r.PsuType.IsEmpty() {
+		return errors.New("psuType cannot be empty")
+	}
+
+	if !r.PsuType.IsValid() {
+		return fmt.Errorf("psuType %q is not valid, must be one of %s", r.PsuType, strings.Join(enablebankinggo.PSUTypeKeys(), ", "))
+	}
+
+	return nil
 }
 
 // LinkApplicationAccountResponse represents the response from linking an application account.
@@ -88,6 +144,51 @@ func (c *APIClient) GetApplication(ctx context.Context, applicationID string) (*
 	return app, nil
 }
 
+// GetApplicationCertificate get the certificate, including the public key as JWK, registered for an application.
+func (c *APIClient) GetApplicationCertificate(ctx context.Context, applicationID string) (*Certificate, error) {
+	if applicationID == "" {
+		return nil, errors.New("applicationID cannot be empty")
+	}
+
+	app, err := c.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if app == nil || app.Certificate == nil {
+		return nil, fmt.Errorf("application %s has no certificate", applicationID)
+	}
+
+	return app.Certificate, nil
+}
+
+// GetApplicationServices get the countries and services enabled for an application.
+func (c *APIClient) GetApplicationServices(ctx context.Context, applicationID string) (*ApplicationServices, error) {
+	if applicationID == "" {
+		return nil, errors.New("applicationID cannot be empty")
+	}
+
+	app, err := c.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ApplicationServices{
+		Countries: []enablebankinggo.Country{},
+		Services:  []enablebankinggo.Service{},
+	}
+	if app == nil {
+		return resp, nil
+	}
+
+	for _, country := range app.Countries {
+		resp.Countries = append(resp.Countries, enablebankinggo.Country(country))
+	}
+	resp.Services = append(resp.Services, app.Services...)
+
+	return resp, nil
+}
+
 // RegisterApplication registers a new application.
 func (c *APIClient) RegisterApplication(ctx context.Context, req *RegisterApplicationRequest) (*RegisterApplicationResponse, error) {
 	httpReq, err := c.newRequest(ctx, http.MethodPost, "/applications", req)
@@ -121,12 +222,33 @@ func (c *APIClient) DeleteApplication(ctx context.Context, applicationID string)
 
 // LinkApplicationAccount links (whitelists) an account for production tests.
 func (c *APIClient) LinkApplicationAccount(ctx context.Context, req *LinkApplicationAccountRequest) (*LinkApplicationAccountResponse, error) {
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.aspspCatalog != nil {
+		err = c.validateASPSP(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	redirectURL := req.RedirectURL
+	if redirectURL == "" {
+		redirectURL = DefaultLinkApplicationAccountRedirectURL
+	}
+
 	data := url.Values{}
-	data.Set("country", req.Country)
+	data.Set("country", string(req.Country))
 	data.Set("aspsp", req.Aspsp)
 	data.Set("appId", req.AppID)
-	data.Set("psuType", req.PsuType)
-	data.Set("redirectUrl", "https://enablebanking.com/api/auth_redirect")
+	data.Set("psuType", string(req.PsuType))
+	data.Set("redirectUrl", redirectURL)
 
 	httpReq, err := c.newFormDataRequest(ctx, http.MethodPost, "/link_accounts", data)
 	if err != nil {
@@ -155,3 +277,43 @@ func (c *APIClient) UnlinkApplicationAccount(ctx context.Context, applicationID
 
 	return c.sendAuthenticatedRequest(httpReq, nil)
 }
+
+// ListWhiteListedAccounts retrieves the accounts whitelisted for production tests of an application, with
+// their ASPSP, linker and creation date. The control panel API has no dedicated endpoint, so the accounts
+// are those of [APIClient.GetApplication].
+func (c *APIClient) ListWhiteListedAccounts(ctx context.Context, applicationID string) ([]*WhiteListedAccount, error) {
+	if applicationID == "" {
+		return nil, errors.New("applicationID cannot be empty")
+	}
+
+	app, err := c.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if app == nil {
+		return nil, nil
+	}
+
+	return app.WhiteListedAccounts, nil
+}
+
+// validateASPSP validates that the ASPSP of the request exists in the ASPSP catalog for the requested
+// country and PSU type.
+func (c *APIClient) validateASPSP(ctx context.Context, req *LinkApplicationAccountRequest) error {
+	resp, err := c.aspspCatalog.GetASPSPs(ctx, &enablebankinggo.GetASPSPsRequestParams{
+		CountryQueryParam: string(req.Country),
+		PSUTypeQueryParam: req.PsuType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get ASPSPs: %w", err)
+	}
+
+	for _, aspsp := range resp.ASPSPs {
+		if aspsp != nil && aspsp.Name == req.Aspsp {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("aspsp %q supporting psuType %q not found in country %s", req.Aspsp, req.PsuType, req.Country)
+}
@@ -7,6 +7,9 @@ import (
 	"net/url"
 )
 
+// GoogleProviderID is the identity provider ID of Google accounts.
+const GoogleProviderID = "google.com"
+
 // RelyingpartyGetOOBConfirmationCodeRequest represents the request payload for the RelyingpartyGetOOBConfirmationCode endpoint.
 type RelyingpartyGetOOBConfirmationCodeRequest struct {
 	RequestType        string `json:"requestType"`
@@ -53,6 +56,52 @@ type EmailLinkSigninResponse struct {
 	RefreshToken string `json:"refreshToken,omitempty"`
 }
 
+// RelyingpartyVerifyAssertionRequest represents the request payload for the RelyingpartyVerifyAssertion endpoint.
+type RelyingpartyVerifyAssertionRequest struct {
+	// RequestURI: The URI to which the IDP redirects the user back.
+	RequestURI string `json:"requestUri"`
+	// PostBody: The post body containing the IDP credential, e.g. "id_token=...&providerId=google.com".
+	PostBody string `json:"postBody"`
+	// ReturnSecureToken: Whether to return an ID and refresh token.
+	ReturnSecureToken bool `json:"returnSecureToken"`
+	// ReturnIdpCredential: Whether to return the IDP credential on error.
+	ReturnIdpCredential bool `json:"returnIdpCredential,omitempty"`
+}
+
+// VerifyAssertionResponse represents the response from the RelyingpartyVerifyAssertion endpoint.
+type VerifyAssertionResponse struct {
+	// Email: The email returned by the IDP.
+	Email string `json:"email,omitempty"`
+	// ExpiresIn: Expiration time of STS id token in seconds.
+	ExpiresIn int64 `json:"expiresIn,omitempty,string"`
+	// IDToken: The STS id token to login the newly signed in user.
+	IDToken string `json:"idToken,omitempty"`
+	// RefreshToken: The refresh token for the signed in user.
+	RefreshToken string `json:"refreshToken,omitempty"`
+	// IsNewUser: Whether the user is new.
+	IsNewUser bool `json:"isNewUser,omitempty"`
+	// Kind: The fixed string "identitytoolkit#VerifyAssertionResponse".
+	Kind string `json:"kind,omitempty"`
+	// LocalID: The RP local ID of the user.
+	LocalID string `json:"localId,omitempty"`
+	// ProviderID: The IDP ID, e.g. google.com.
+	ProviderID string `json:"providerId,omitempty"`
+	// FederatedID: The unique ID identifies the IDP account.
+	FederatedID string `json:"federatedId,omitempty"`
+	// DisplayName: The display name of the user.
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// Token returns the authentication token of the signed in user, usable with [WithToken].
+func (r *VerifyAssertionResponse) Token() *Token {
+	return newToken(r.IDToken, r.RefreshToken, r.ExpiresIn)
+}
+
+// Token returns the authentication token of the signed in user, usable with [WithToken].
+func (r *EmailLinkSigninResponse) Token() *Token {
+	return newToken(r.IDToken, r.RefreshToken, r.ExpiresIn)
+}
+
 // RefreshTokenResponse represents the response from the token refresh endpoint.
 type RefreshTokenResponse struct {
 	// AccessToken: The access token for the signed in user.
@@ -117,6 +166,49 @@ func (c *APIClient) RelyingpartyEmailLinkSignin(ctx context.Context, req *Relyin
 	return &resp, nil
 }
 
+// RelyingpartyVerifyAssertion signs in using a credential of an identity provider, e.g. a Google account.
+func (c *APIClient) RelyingpartyVerifyAssertion(ctx context.Context, req *RelyingpartyVerifyAssertionRequest) (*VerifyAssertionResponse, error) {
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	reqHTTP, err := c.newRequest(ctx, http.MethodPost, "/relyingparty/verifyAssertion", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp VerifyAssertionResponse
+	err = c.sendUnauthenticatedRequest(reqHTTP, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SignInWithGoogle signs in using a Google OAuth ID token, e.g. obtained from a Google SSO or OAuth flow,
+// by exchanging it for a control panel token. The requestURI is the URI the Google OAuth flow redirected
+// back to.
+func (c *APIClient) SignInWithGoogle(ctx context.Context, googleIDToken string, requestURI string) (*VerifyAssertionResponse, error) {
+	if googleIDToken == "" {
+		return nil, errors.New("googleIDToken cannot be empty")
+	}
+
+	if requestURI == "" {
+		return nil, errors.New("requestURI cannot be empty")
+	}
+
+	postBody := url.Values{}
+	postBody.Set("id_token", googleIDToken)
+	postBody.Set("providerId", GoogleProviderID)
+
+	return c.RelyingpartyVerifyAssertion(ctx, &RelyingpartyVerifyAssertionRequest{
+		RequestURI:        requestURI,
+		PostBody:          postBody.Encode(),
+		ReturnSecureToken: true,
+	})
+}
+
 // RefreshToken refreshes the ID token using the provided refresh token.
 func (c *APIClient) RefreshToken(ctx context.Context, refreshToken string) (*RefreshTokenResponse, error) {
 	values := url.Values{}
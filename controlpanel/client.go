@@ -4,17 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/internal/observe"
 )
 
 const (
 	// ClientDefaultAPIBaseURL is the default base URL for the Enable Banking control panel API.
 	ClientDefaultAPIBaseURL = "https://enablebanking.com/api"
+
+	// ClientDefaultTokenRefreshMargin is the default time before token expiration at which the token is
+	// proactively refreshed.
+	ClientDefaultTokenRefreshMargin = time.Minute
 )
 
 // ClientOption represents an option for configuring the API client.
@@ -25,6 +35,45 @@ type Token struct {
 	IDToken      string `json:"id_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+
+	// ExpiresAt is the time when the ID token expires, if known. Set by the client when the token
+	// is refreshed, based on ExpiresIn. A token configured using [WithToken] or loaded from the token
+	// store without ExpiresAt is assumed to be issued when configured or loaded.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newToken(idToken, refreshToken string, expiresIn int64) *Token {
+	token := &Token{
+		IDToken:      idToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    expiresIn,
+	}
+
+	if expiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return token
+}
+
+// fillExpiresAt sets ExpiresAt based on ExpiresIn, assuming the token was issued at now, if it's unknown.
+func (t *Token) fillExpiresAt(now time.Time) {
+	if t.ExpiresAt.IsZero() && t.ExpiresIn > 0 {
+		t.ExpiresAt = now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+// needsRefresh checks whether the token is known to expire within the provided margin.
+func (t *Token) needsRefresh(now time.Time, margin time.Duration) bool {
+	if t.RefreshToken == "" {
+		return false
+	}
+
+	if t.IDToken == "" {
+		return true
+	}
+
+	return !t.ExpiresAt.IsZero() && !now.Add(margin).Before(t.ExpiresAt)
 }
 
 // WithBaseURL sets a custom base URL for the Enable Banking API client.
@@ -34,27 +83,82 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client for the Enable Banking API client.
+// WithHTTPClient sets a custom HTTP client for the Enable Banking API client. Defaults to a client created by
+// [enablebankinggo.NewHTTPClient].
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *APIClient) {
 		c.httpClient = httpClient
 	}
 }
 
-// WithHTTPTransport sets a custom HTTP transport for the client.
+// WithHTTPTransport sets a custom HTTP transport for the client. The HTTP client is copied, leaving a client set
+// using [WithHTTPClient] unchanged.
 func WithHTTPTransport(transport http.RoundTripper) ClientOption {
 	return func(c *APIClient) {
-		c.httpClient.Transport = transport
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request, identifying the application, e.g.
+// "myapp/1.2.0". The library remains identified by appending [enablebankinggo.DefaultUserAgent].
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *APIClient) {
+		c.userAgent = strings.TrimSpace(userAgent + " " + enablebankinggo.DefaultUserAgent)
 	}
 }
 
 // WithToken configures the client to use existing token.
 func WithToken(token *Token) ClientOption {
 	return func(c *APIClient) {
+		if token != nil {
+			token.fillExpiresAt(time.Now())
+		}
+
 		c.token = token
 	}
 }
 
+// WithTokenStore configures the client to load the token from the store before the first authenticated
+// request and to save the token to the store every time it has been refreshed. A token loaded from the
+// store takes precedence over a token configured using [WithToken].
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *APIClient) {
+		c.tokenStore = store
+	}
+}
+
+// WithTokenRefreshMargin sets the time before token expiration at which the token is proactively
+// refreshed. Default is [ClientDefaultTokenRefreshMargin].
+func WithTokenRefreshMargin(margin time.Duration) ClientOption {
+	return func(c *APIClient) {
+		c.tokenRefreshMargin = margin
+	}
+}
+
+// WithASPSPCatalog configures the client to validate ASPSP names against the ASPSP catalog of the
+// provided Enable Banking API client before linking application accounts.
+func WithASPSPCatalog(catalog enablebankinggo.MiscClient) ClientOption {
+	return func(c *APIClient) {
+		c.aspspCatalog = catalog
+	}
+}
+
+// WithLogger sets a logger used for logging every request made by the client.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *APIClient) {
+		c.logger = logger
+	}
+}
+
+// WithRequestObserver sets an observer notified about every request made by the client.
+func WithRequestObserver(observer enablebankinggo.RequestObserver) ClientOption {
+	return func(c *APIClient) {
+		c.observer = observer
+	}
+}
+
 // OnTokenRefreshed configures a callback function to be called after the token have been refreshed.
 func OnTokenRefreshed(fn func(token *Token)) ClientOption {
 	return func(c *APIClient) {
@@ -64,26 +168,42 @@ func OnTokenRefreshed(fn func(token *Token)) ClientOption {
 
 // APIClient is the Enable Banking control panel API client.
 type APIClient struct {
-	baseURL          string
-	httpClient       *http.Client
-	token            *Token
-	onTokenRefreshed func(token *Token)
-	mu               sync.Mutex
+	baseURL            string
+	httpClient         *http.Client
+	userAgent          string
+	token              *Token
+	tokenStore         TokenStore
+	tokenLoaded        bool
+	tokenRefreshMargin time.Duration
+	onTokenRefreshed   func(token *Token)
+	aspspCatalog       enablebankinggo.MiscClient
+	logger             *slog.Logger
+	observer           enablebankinggo.RequestObserver
+	keepAlive          bool
+	keepAliveJitter    time.Duration
+	stopKeepAlive      context.CancelFunc
+	mu                 sync.Mutex
 }
 
 // NewClient creates a new Enable Banking control panel API client with default settings.
 // If no options are provided, the client will use default settings of [ClientDefaultAPIBaseURL].
 func NewClient(options ...ClientOption) *APIClient {
 	client := &APIClient{
-		baseURL:    ClientDefaultAPIBaseURL,
-		httpClient: http.DefaultClient,
-		token:      &Token{},
+		baseURL:            ClientDefaultAPIBaseURL,
+		httpClient:         enablebankinggo.NewHTTPClient(),
+		userAgent:          enablebankinggo.DefaultUserAgent,
+		token:              &Token{},
+		tokenRefreshMargin: ClientDefaultTokenRefreshMargin,
 	}
 
 	for _, option := range options {
 		option(client)
 	}
 
+	if client.keepAlive {
+		client.startKeepAlive()
+	}
+
 	return client
 }
 
@@ -133,45 +253,42 @@ func (c *APIClient) sendUnauthenticatedRequest(req *http.Request, resp any) erro
 }
 
 func (c *APIClient) sendAuthenticatedRequest(req *http.Request, resp any) error {
-	req.Header.Set("Authorization", "Bearer "+c.token.IDToken)
-
-	var bodyBytes []byte
-	if req.Body != nil {
-		var err error
-		bodyBytes, err = io.ReadAll(req.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read request body: %w", err)
-		}
+	idToken, err := c.currentIDToken(req.Context())
+	if err != nil {
+		return err
+	}
 
-		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	err = ensureRewindableBody(req)
+	if err != nil {
+		return err
 	}
 
-	err := c.sendRequestInternal(req, resp)
+	req.Header.Set("Authorization", "Bearer "+idToken)
+
+	err = c.sendRequestInternal(req, resp)
 	if err != nil {
-		if errResp, ok := IsErrorResponse(err); ok && errResp.ErrorObj.Message == "Unauthorized" {
+		if errResp, ok := IsErrorResponse(err); ok && errResp.IsUnauthorized() {
 			c.mu.Lock()
 			defer c.mu.Unlock()
 			if c.token == nil {
 				return err
 			}
 
-			newTokenResp, refreshErr := c.RefreshToken(req.Context(), c.token.RefreshToken)
-			if refreshErr != nil {
-				return fmt.Errorf("failed to refresh token: %w", refreshErr)
+			// Another request may already have refreshed the token while this one was in flight.
+			if c.token.IDToken == idToken {
+				refreshErr := c.refreshTokenLocked(req.Context())
+				if refreshErr != nil {
+					return refreshErr
+				}
 			}
 
-			c.token.IDToken = newTokenResp.IDToken
-			c.token.RefreshToken = newTokenResp.RefreshToken
-			c.token.ExpiresIn = newTokenResp.ExpiresIn
-
-			if c.onTokenRefreshed != nil {
-				c.onTokenRefreshed(c.token)
+			retryReq, rewindErr := rewindRequest(req)
+			if rewindErr != nil {
+				return rewindErr
 			}
 
-			clonedReq := req.Clone(req.Context())
-			clonedReq.Header.Set("Authorization", "Bearer "+newTokenResp.IDToken)
-			clonedReq.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-			return c.sendRequestInternal(clonedReq, resp)
+			retryReq.Header.Set("Authorization", "Bearer "+c.token.IDToken)
+			return c.sendRequestInternal(retryReq, resp)
 		}
 
 		return err
@@ -180,8 +297,116 @@ func (c *APIClient) sendAuthenticatedRequest(req *http.Request, resp any) error
 	return nil
 }
 
-func (c *APIClient) sendRequestInternal(req *http.Request, resp any) error {
-	response, err := c.httpClient.Do(req)
+// ensureRewindableBody makes sure the body of the request can be replayed by [rewindRequest]. Requests
+// created by [http.NewRequestWithContext] with an in-memory body already provide GetBody, in which case
+// the body is left untouched. Other bodies are read once and buffered.
+func ensureRewindableBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	closeErr := req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close request body: %w", closeErr)
+	}
+
+	req.ContentLength = int64(len(bodyBytes))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
+
+// rewindRequest returns a clone of the request with a fresh body obtained from GetBody, allowing the
+// request to be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clonedReq := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return clonedReq, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	clonedReq.Body = body
+
+	return clonedReq, nil
+}
+
+// currentIDToken returns the ID token to use for authenticated requests, loading the token from the
+// token store on first use and proactively refreshing it if it is about to expire.
+func (c *APIClient) currentIDToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokenStore != nil && !c.tokenLoaded {
+		token, err := c.tokenStore.LoadToken(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to load token: %w", err)
+		}
+
+		if token != nil {
+			token.fillExpiresAt(time.Now())
+			c.token = token
+		}
+		c.tokenLoaded = true
+	}
+
+	if c.token == nil {
+		return "", nil
+	}
+
+	if c.token.needsRefresh(time.Now(), c.tokenRefreshMargin) {
+		err := c.refreshTokenLocked(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return c.token.IDToken, nil
+}
+
+// refreshTokenLocked refreshes the token using the current refresh token. Must be called with c.mu held.
+func (c *APIClient) refreshTokenLocked(ctx context.Context) error {
+	newTokenResp, err := c.RefreshToken(ctx, c.token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	*c.token = *newToken(newTokenResp.IDToken, newTokenResp.RefreshToken, newTokenResp.ExpiresIn)
+
+	if c.onTokenRefreshed != nil {
+		c.onTokenRefreshed(c.token)
+	}
+
+	if c.tokenStore != nil {
+		err = c.tokenStore.SaveToken(ctx, c.token)
+		if err != nil {
+			return fmt.Errorf("failed to save token: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *APIClient) sendRequestInternal(req *http.Request, resp any) (err error) {
+	start := time.Now()
+	var response *http.Response
+	defer func() {
+		c.observeRequest(req, response, time.Since(start), err)
+	}()
+
+	req.Header.Set("User-Agent", c.userAgent)
+
+	response, err = c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -191,7 +416,7 @@ func (c *APIClient) sendRequestInternal(req *http.Request, resp any) error {
 		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
 	}
 
-	if response.StatusCode != 200 {
+	if response.StatusCode >= 300 {
 		var errResp ErrorResponse
 		err = json.NewDecoder(response.Body).Decode(&errResp)
 		if err != nil {
@@ -201,9 +426,32 @@ func (c *APIClient) sendRequestInternal(req *http.Request, resp any) error {
 		return &errResp
 	}
 
-	if resp != nil {
-		return json.NewDecoder(response.Body).Decode(resp)
+	// Empty and 204 responses leave resp unchanged, e.g. endpoints without a response body.
+	if resp != nil && response.StatusCode != http.StatusNoContent && response.ContentLength != 0 {
+		err = json.NewDecoder(response.Body).Decode(resp)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err
 	}
 
 	return nil
 }
+
+func (c *APIClient) observeRequest(req *http.Request, response *http.Response, duration time.Duration, err error) {
+	info := &enablebankinggo.RequestInfo{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		RequestID: req.Header.Get(enablebankinggo.RequestIDHeader),
+		Duration:  duration,
+		Err:       err,
+	}
+
+	if response != nil {
+		info.StatusCode = response.StatusCode
+		info.Header = response.Header
+	}
+
+	observe.Request(req.Context(), c.logger, c.observer, info, err)
+}
@@ -0,0 +1,391 @@
+// Package controlpaneltest provides a fake Enable Banking Control Panel API server for testing code
+// built on the controlpanel package without real credentials.
+package controlpaneltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/controlpanel"
+)
+
+// DefaultTokenTTL is the default time-to-live of tokens issued by the fake server.
+const DefaultTokenTTL = time.Hour
+
+// Server is a fake Enable Banking Control Panel API server implementing the auth (email link sign-in,
+// token refresh) and application endpoints.
+type Server struct {
+	*httptest.Server
+
+	// TokenTTL is the time-to-live of issued tokens. Defaults to [DefaultTokenTTL].
+	TokenTTL time.Duration
+
+	mu            sync.Mutex
+	oobCodes      map[string]string
+	idTokens      map[string]string
+	refreshTokens map[string]string
+	applications  map[string]*controlpanel.Application
+}
+
+// NewServer starts and returns a new fake control panel server. The caller should call Close when finished,
+// to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		TokenTTL:      DefaultTokenTTL,
+		oobCodes:      map[string]string{},
+		idTokens:      map[string]string{},
+		refreshTokens: map[string]string{},
+		applications:  map[string]*controlpanel.Application{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /relyingparty/getOobConfirmationCode", s.handleGetOOBConfirmationCode)
+	mux.HandleFunc("POST /relyingparty/emailLinkSignin", s.handleEmailLinkSignin)
+	mux.HandleFunc("POST /relyingparty/verifyAssertion", s.handleVerifyAssertion)
+	mux.HandleFunc("POST /token", s.handleRefreshToken)
+	mux.HandleFunc("GET /applications", s.authenticated(s.handleListApplications))
+	mux.HandleFunc("POST /applications", s.authenticated(s.handleRegisterApplication))
+	mux.HandleFunc("DELETE /applications/", s.authenticated(s.handleDeleteApplication))
+	mux.HandleFunc("GET /application/{id}", s.authenticated(s.handleGetApplication))
+	mux.HandleFunc("POST /link_accounts", s.authenticated(s.handleLinkAccount))
+	mux.HandleFunc("POST /unlink_accounts", s.authenticated(s.handleUnlinkAccount))
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// APIClient creates a new control panel API client targeting the fake server. The client is signed in
+// using a token issued for the provided email.
+func (s *Server) APIClient(email string, options ...controlpanel.ClientOption) *controlpanel.APIClient {
+	opts := []controlpanel.ClientOption{
+		controlpanel.WithBaseURL(s.URL),
+		controlpanel.WithHTTPClient(s.Client()),
+		controlpanel.WithToken(s.IssueToken(email)),
+	}
+
+	return controlpanel.NewClient(append(opts, options...)...)
+}
+
+// IssueToken issues a new token for the provided email.
+func (s *Server) IssueToken(email string) *controlpanel.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.issueTokenLocked(email)
+}
+
+// ExpireTokens invalidates all issued ID tokens, while keeping refresh tokens valid, forcing clients to
+// refresh their token.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idTokens = map[string]string{}
+}
+
+// AddApplication adds an application to the fake server.
+func (s *Server) AddApplication(applicationID string, app *controlpanel.Application) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.applications[applicationID] = app
+}
+
+// Application returns the application with the provided ID, if any.
+func (s *Server) Application(applicationID string) (*controlpanel.Application, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, ok := s.applications[applicationID]
+	return app, ok
+}
+
+func (s *Server) issueTokenLocked(email string) *controlpanel.Token {
+	idToken := randomString()
+	refreshToken := randomString()
+	s.idTokens[idToken] = email
+	s.refreshTokens[refreshToken] = email
+
+	return &controlpanel.Token{
+		IDToken:      idToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.TokenTTL.Seconds()),
+		ExpiresAt:    time.Now().Add(s.TokenTTL),
+	}
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		_, valid := s.idTokens[idToken]
+		s.mu.Unlock()
+
+		if !ok || !valid {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) handleGetOOBConfirmationCode(w http.ResponseWriter, r *http.Request) {
+	var req controlpanel.RelyingpartyGetOOBConfirmationCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_EMAIL")
+		return
+	}
+
+	s.mu.Lock()
+	oobCode := randomString()
+	s.oobCodes[oobCode] = req.Email
+	s.mu.Unlock()
+
+	writeJSON(w, &controlpanel.GetOOBConfirmationCodeResponse{
+		Kind:    "identitytoolkit#GetOobConfirmationCodeResponse",
+		Email:   req.Email,
+		OOBCode: oobCode,
+	})
+}
+
+func (s *Server) handleEmailLinkSignin(w http.ResponseWriter, r *http.Request) {
+	var req controlpanel.RelyingpartyEmailLinkSigninRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_OOB_CODE")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.oobCodes[req.OOBCode]
+	if !ok || email != req.Email {
+		writeError(w, http.StatusBadRequest, "INVALID_OOB_CODE")
+		return
+	}
+	delete(s.oobCodes, req.OOBCode)
+
+	token := s.issueTokenLocked(email)
+	writeJSON(w, map[string]any{
+		"kind":         "identitytoolkit#EmailLinkSigninResponse",
+		"email":        email,
+		"idToken":      token.IDToken,
+		"refreshToken": token.RefreshToken,
+		"expiresIn":    strconv.FormatInt(token.ExpiresIn, 10),
+		"localId":      email,
+	})
+}
+
+func (s *Server) handleVerifyAssertion(w http.ResponseWriter, r *http.Request) {
+	var req controlpanel.RelyingpartyVerifyAssertionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_IDP_RESPONSE")
+		return
+	}
+
+	postBody, err := url.ParseQuery(req.PostBody)
+	if err != nil || postBody.Get("id_token") == "" || postBody.Get("providerId") != controlpanel.GoogleProviderID {
+		writeError(w, http.StatusBadRequest, "INVALID_IDP_RESPONSE")
+		return
+	}
+
+	// The fake server accepts any Google ID token and uses it as the email of the signed in user.
+	email := postBody.Get("id_token")
+
+	s.mu.Lock()
+	token := s.issueTokenLocked(email)
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"kind":         "identitytoolkit#VerifyAssertionResponse",
+		"email":        email,
+		"idToken":      token.IDToken,
+		"refreshToken": token.RefreshToken,
+		"expiresIn":    strconv.FormatInt(token.ExpiresIn, 10),
+		"localId":      email,
+		"providerId":   controlpanel.GoogleProviderID,
+		"federatedId":  "https://accounts.google.com/" + email,
+	})
+}
+
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "refresh_token" {
+		writeError(w, http.StatusBadRequest, "INVALID_GRANT_TYPE")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refreshToken := r.PostForm.Get("refresh_token")
+	email, ok := s.refreshTokens[refreshToken]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_REFRESH_TOKEN")
+		return
+	}
+	delete(s.refreshTokens, refreshToken)
+
+	token := s.issueTokenLocked(email)
+	writeJSON(w, map[string]any{
+		"access_token":  token.IDToken,
+		"expires_in":    strconv.FormatInt(token.ExpiresIn, 10),
+		"token_type":    "Bearer",
+		"refresh_token": token.RefreshToken,
+		"id_token":      token.IDToken,
+		"user_id":       email,
+		"project_id":    "controlpaneltest",
+	})
+}
+
+func (s *Server) handleListApplications(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apps := make([]*controlpanel.Application, 0, len(s.applications))
+	for _, app := range s.applications {
+		apps = append(apps, app)
+	}
+
+	writeJSON(w, apps)
+}
+
+func (s *Server) handleRegisterApplication(w http.ResponseWriter, r *http.Request) {
+	var req controlpanel.RegisterApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "Invalid application")
+		return
+	}
+
+	applicationID := randomString()
+	app := &controlpanel.Application{
+		Name:         req.Name,
+		PrivacyURL:   req.PrivacyURL,
+		TermsURL:     req.TermsURL,
+		GDPREmail:    req.GDPREmail,
+		Description:  req.Description,
+		KID:          applicationID,
+		Certificate:  &controlpanel.Certificate{Source: map[string]any{"certificate": req.CertificateContent}},
+		Environment:  req.Environment,
+		RedirectUrls: req.RedirectUrls,
+		Services:     []enablebankinggo.Service{enablebankinggo.AccountInformationService},
+		Active:       req.Environment == enablebankinggo.SandboxEnvironment,
+		Created:      time.Now().UTC(),
+	}
+
+	s.AddApplication(applicationID, app)
+
+	writeJSON(w, &controlpanel.RegisterApplicationResponse{ApplicationID: applicationID})
+}
+
+func (s *Server) handleDeleteApplication(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ApplicationID string `json:"appId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.applications[req.ApplicationID]; !ok {
+		writeError(w, http.StatusNotFound, "Application not found")
+		return
+	}
+	delete(s.applications, req.ApplicationID)
+
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) handleGetApplication(w http.ResponseWriter, r *http.Request) {
+	app, ok := s.Application(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Application not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, app)
+}
+
+func (s *Server) handleLinkAccount(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if _, ok := s.Application(r.PostForm.Get("appId")); !ok {
+		writeError(w, http.StatusNotFound, "Application not found")
+		return
+	}
+
+	authorizationID := randomString()
+	writeJSON(w, &controlpanel.LinkApplicationAccountResponse{
+		URL:             r.PostForm.Get("redirectUrl") + "?code=" + authorizationID,
+		AuthorizationID: authorizationID,
+		PsuIDHash:       randomString(),
+	})
+}
+
+func (s *Server) handleUnlinkAccount(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, ok := s.applications[r.PostForm.Get("appId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Application not found")
+		return
+	}
+
+	identificationHash := r.PostForm.Get("identificationHash")
+	accounts := app.WhiteListedAccounts[:0]
+	for _, account := range app.WhiteListedAccounts {
+		if account.IdentificationHash != identificationHash {
+			accounts = append(accounts, account)
+		}
+	}
+	app.WhiteListedAccounts = accounts
+
+	writeJSON(w, map[string]any{})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	var errResp controlpanel.ErrorResponse
+	errResp.ErrorObj.Code = statusCode
+	errResp.ErrorObj.Message = message
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(&errResp)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
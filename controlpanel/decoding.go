@@ -0,0 +1,93 @@
+package controlpanel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// flexibleInt64 is an int64 decodable from both JSON numbers and numeric strings, since e.g. expiresIn
+// arrives as string or number depending on the backend.
+type flexibleInt64 int64
+
+func (i *flexibleInt64) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+
+		if s == "" {
+			*i = 0
+			return nil
+		}
+	}
+
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*i = flexibleInt64(v)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f > math.MaxInt64 || f < math.MinInt64 {
+		return fmt.Errorf("expected integer, got %s", b)
+	}
+
+	*i = flexibleInt64(f)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting expiresIn as number or numeric string.
+func (r *EmailLinkSigninResponse) UnmarshalJSON(b []byte) error {
+	type emailLinkSigninResponse EmailLinkSigninResponse
+	aux := struct {
+		*emailLinkSigninResponse
+		ExpiresIn flexibleInt64 `json:"expiresIn,omitempty"`
+	}{emailLinkSigninResponse: (*emailLinkSigninResponse)(r)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	r.ExpiresIn = int64(aux.ExpiresIn)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting expiresIn as number or numeric string.
+func (r *VerifyAssertionResponse) UnmarshalJSON(b []byte) error {
+	type verifyAssertionResponse VerifyAssertionResponse
+	aux := struct {
+		*verifyAssertionResponse
+		ExpiresIn flexibleInt64 `json:"expiresIn,omitempty"`
+	}{verifyAssertionResponse: (*verifyAssertionResponse)(r)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	r.ExpiresIn = int64(aux.ExpiresIn)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting expires_in as number or numeric string.
+func (r *RefreshTokenResponse) UnmarshalJSON(b []byte) error {
+	type refreshTokenResponse RefreshTokenResponse
+	aux := struct {
+		*refreshTokenResponse
+		ExpiresIn flexibleInt64 `json:"expires_in"`
+	}{refreshTokenResponse: (*refreshTokenResponse)(r)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	r.ExpiresIn = int64(aux.ExpiresIn)
+	return nil
+}
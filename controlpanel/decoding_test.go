@@ -0,0 +1,35 @@
+package controlpanel
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func FuzzFlexibleInt64(f *testing.F) {
+	for _, seed := range []string{"0", "3600", "-7", "1e3", "1.5", "9223372036854775807", "9223372036854775808", "", "null", "abc"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		// Arbitrary input must not panic.
+		var raw flexibleInt64
+		_ = raw.UnmarshalJSON([]byte(s))
+
+		var n json.Number
+		if json.Unmarshal([]byte(s), &n) != nil || n.String() != s {
+			return
+		}
+
+		var fromNumber, fromString RefreshTokenResponse
+		errNumber := json.Unmarshal([]byte(`{"expires_in":`+s+`}`), &fromNumber)
+		errString := json.Unmarshal([]byte(`{"expires_in":`+strconv.Quote(s)+`}`), &fromString)
+		if (errNumber == nil) != (errString == nil) {
+			t.Fatalf("number %s decoded with error %v, string with error %v", s, errNumber, errString)
+		}
+
+		if fromNumber.ExpiresIn != fromString.ExpiresIn {
+			t.Fatalf("number %s decoded to %d, string to %d", s, fromNumber.ExpiresIn, fromString.ExpiresIn)
+		}
+	})
+}
@@ -1,21 +1,72 @@
 package controlpanel
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
 
 // ErrorResponse represents an error response from the API.
 type ErrorResponse struct {
 	ErrorObj struct {
-		Code    int              `json:"code"`
-		Message string           `json:"message"`
-		Errors  []map[string]any `json:"errors"`
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Status  string         `json:"status,omitempty"`
+		Errors  []*ErrorDetail `json:"errors"`
 	} `json:"error"`
 }
 
+// ErrorDetail represents a Google-style error detail object of an error response.
+type ErrorDetail struct {
+	// Domain is the scope of the error, e.g. global.
+	Domain string `json:"domain,omitempty"`
+
+	// Reason is the reason of the error, e.g. invalid or quotaExceeded.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message describing the error.
+	Message string `json:"message,omitempty"`
+}
+
 // Error implements the error interface for ErrorResponse.
 func (e ErrorResponse) Error() string {
 	return e.ErrorObj.Message
 }
 
+// IsUnauthorized checks whether the error indicates that the request was not authorized, e.g. because
+// the token has expired.
+func (e ErrorResponse) IsUnauthorized() bool {
+	return e.ErrorObj.Message == "Unauthorized" ||
+		e.ErrorObj.Code == http.StatusUnauthorized ||
+		e.ErrorObj.Status == "UNAUTHENTICATED" ||
+		e.hasReason("authError", "unauthorized")
+}
+
+// IsQuotaExceeded checks whether the error indicates that a quota or rate limit has been exceeded.
+func (e ErrorResponse) IsQuotaExceeded() bool {
+	return e.ErrorObj.Code == http.StatusTooManyRequests ||
+		e.ErrorObj.Status == "RESOURCE_EXHAUSTED" ||
+		strings.HasPrefix(e.ErrorObj.Message, "QUOTA_EXCEEDED") ||
+		strings.HasPrefix(e.ErrorObj.Message, "TOO_MANY_ATTEMPTS_TRY_LATER") ||
+		e.hasReason("quotaExceeded", "rateLimitExceeded", "userRateLimitExceeded")
+}
+
+func (e ErrorResponse) hasReason(reasons ...string) bool {
+	for _, detail := range e.ErrorObj.Errors {
+		if detail == nil {
+			continue
+		}
+
+		for _, reason := range reasons {
+			if detail.Reason == reason {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // IsErrorResponse checks if the provided error is of type [ErrorResponse] and
 // returns it along with a boolean indicating the result.
 func IsErrorResponse(err error) (*ErrorResponse, bool) {
@@ -0,0 +1,123 @@
+package controlpanel
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// keepAliveMinInterval is the minimum interval between keep-alive refresh attempts, preventing a tight
+	// loop when the token can't be refreshed.
+	keepAliveMinInterval = 10 * time.Second
+
+	// keepAliveMaxInterval is the maximum interval between keep-alive refresh attempts, used when failed
+	// attempts are backed off and when there's no token to refresh or its expiration is unknown.
+	keepAliveMaxInterval = 10 * time.Minute
+)
+
+// WithTokenKeepAlive configures the client to refresh the token in the background on a schedule derived
+// from the token expiration, so that requests never hit an expired token. The token is refreshed the
+// token refresh margin (see [WithTokenRefreshMargin]) plus a random duration of up to jitter before it
+// expires. Failed refreshes are retried with exponential backoff. [OnTokenRefreshed] and the token store
+// (see [WithTokenStore]) are invoked for every refresh. Call [APIClient.Close] to stop refreshing.
+func WithTokenKeepAlive(jitter time.Duration) ClientOption {
+	return func(c *APIClient) {
+		c.keepAlive = true
+		c.keepAliveJitter = jitter
+	}
+}
+
+// Close stops the background token refresh started by [WithTokenKeepAlive], if any.
+func (c *APIClient) Close() error {
+	if c.stopKeepAlive != nil {
+		c.stopKeepAlive()
+	}
+
+	return nil
+}
+
+func (c *APIClient) startKeepAlive() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopKeepAlive = cancel
+
+	go c.runKeepAlive(ctx)
+}
+
+func (c *APIClient) runKeepAlive(ctx context.Context) {
+	failures := 0
+	for {
+		timer := time.NewTimer(c.nextKeepAliveRefresh(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := c.keepAliveRefresh(ctx)
+		if err == nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		if c.logger != nil && ctx.Err() == nil {
+			c.logger.WarnContext(ctx, "Failed to refresh control panel token", "error", err, "failures", failures)
+		}
+	}
+}
+
+// nextKeepAliveRefresh returns the duration until the token should be refreshed next, backing off
+// exponentially after consecutive failures.
+func (c *APIClient) nextKeepAliveRefresh(failures int) time.Duration {
+	if failures > 0 {
+		return min(keepAliveMinInterval<<min(failures-1, 16), keepAliveMaxInterval)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The token is loaded from the token store by the first refresh attempt.
+	if c.tokenStore != nil && !c.tokenLoaded {
+		return keepAliveMinInterval
+	}
+
+	if c.token == nil || c.token.RefreshToken == "" {
+		return keepAliveMaxInterval
+	}
+
+	c.token.fillExpiresAt(time.Now())
+	if c.token.ExpiresAt.IsZero() {
+		return keepAliveMaxInterval
+	}
+
+	wait := time.Until(c.token.ExpiresAt) - c.tokenRefreshMargin
+	if c.keepAliveJitter > 0 {
+		wait -= rand.N(c.keepAliveJitter)
+	}
+
+	return max(wait, keepAliveMinInterval)
+}
+
+func (c *APIClient) keepAliveRefresh(ctx context.Context) error {
+	// Loads the token from the token store, if not already loaded.
+	_, err := c.currentIDToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == nil || c.token.RefreshToken == "" {
+		return nil
+	}
+
+	// The token may just have been refreshed by a request or by loading it from the token store.
+	if !c.token.ExpiresAt.IsZero() && time.Until(c.token.ExpiresAt) > c.tokenRefreshMargin+c.keepAliveJitter {
+		return nil
+	}
+
+	return c.refreshTokenLocked(ctx)
+}
@@ -1,6 +1,12 @@
 package controlpanel
 
 import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"time"
 
 	"github.com/marefr/enablebankinggo"
@@ -38,6 +44,9 @@ type Application struct {
 	// Services is the list of services associated with the application.
 	Services []enablebankinggo.Service `json:"services"`
 
+	// Countries is the list of countries enabled for the application.
+	Countries []string `json:"countries,omitempty"`
+
 	// Active indicates whether the application is active.
 	Active bool `json:"active"`
 
@@ -58,12 +67,85 @@ type Application struct {
 	PendingActionType any              `json:"pendingActionType"`
 }
 
+// WhiteListedAccount returns the whitelisted account with the provided identification hash and a
+// boolean indicating whether it was found.
+func (a *Application) WhiteListedAccount(identificationHash string) (*WhiteListedAccount, bool) {
+	if a == nil {
+		return nil, false
+	}
+
+	return findWhiteListedAccount(a.WhiteListedAccounts, identificationHash)
+}
+
+// IsWhiteListed returns whether the account with the provided identification hash is whitelisted for
+// production tests of the application.
+func (a *Application) IsWhiteListed(identificationHash string) bool {
+	_, ok := a.WhiteListedAccount(identificationHash)
+	return ok
+}
+
 // Certificate represents a certificate associated with an application.
 type Certificate struct {
 	Source map[string]any `json:"source"`
 	JWK    map[string]any `json:"jwk"`
 }
 
+// PublicKey returns the RSA public key of the certificate, parsed from the JWK.
+func (c *Certificate) PublicKey() (*rsa.PublicKey, error) {
+	if c == nil || c.JWK == nil {
+		return nil, errors.New("certificate has no JWK")
+	}
+
+	if kty, _ := c.JWK["kty"].(string); kty != "RSA" {
+		return nil, fmt.Errorf("unsupported JWK key type %q", kty)
+	}
+
+	n, err := jwkBigInt(c.JWK, "n")
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := jwkBigInt(c.JWK, "e")
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.IsInt64() || e.Int64() > math.MaxInt32 {
+		return nil, errors.New("JWK exponent is too large")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// MatchesPrivateKey checks whether the public key of the certificate matches the provided private key,
+// e.g. the key configured for the Enable Banking API client.
+func (c *Certificate) MatchesPrivateKey(privateKey *rsa.PrivateKey) (bool, error) {
+	if privateKey == nil {
+		return false, errors.New("private key cannot be nil")
+	}
+
+	publicKey, err := c.PublicKey()
+	if err != nil {
+		return false, err
+	}
+
+	return publicKey.Equal(&privateKey.PublicKey), nil
+}
+
+func jwkBigInt(jwk map[string]any, name string) (*big.Int, error) {
+	value, ok := jwk[name].(string)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("JWK parameter %q is missing", name)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWK parameter %q: %w", name, err)
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
 // WhiteListedAccount represents a whitelisted account for an application.
 type WhiteListedAccount struct {
 	// Title is the title of the whitelisted account.
@@ -81,3 +163,13 @@ type WhiteListedAccount struct {
 	// Created is the timestamp when the whitelisted account was created.
 	Created time.Time `json:"created"`
 }
+
+func findWhiteListedAccount(accounts []*WhiteListedAccount, identificationHash string) (*WhiteListedAccount, bool) {
+	for _, account := range accounts {
+		if account != nil && account.IdentificationHash == identificationHash {
+			return account, true
+		}
+	}
+
+	return nil, false
+}
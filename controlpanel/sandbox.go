@@ -0,0 +1,153 @@
+package controlpanel
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// SandboxApplicationDefaultKeySize is the default size in bits of the RSA key generated for sandbox applications.
+	SandboxApplicationDefaultKeySize = 4096
+
+	// SandboxApplicationDefaultCertificateValidity is the default validity of the certificate generated for sandbox applications.
+	SandboxApplicationDefaultCertificateValidity = 365 * 24 * time.Hour
+)
+
+// CreateSandboxApplicationRequest represents the request for creating a sandbox application end-to-end.
+type CreateSandboxApplicationRequest struct {
+	// Name is the name of the application.
+	Name string
+
+	// Description is the description of the application.
+	Description string
+
+	// RedirectUrls is the list of allowed redirect URLs.
+	RedirectUrls []string
+
+	// PrivacyURL is the URL to the privacy policy of the application.
+	PrivacyURL string
+
+	// TermsURL is the URL to the terms and conditions of the application.
+	TermsURL string
+
+	// GDPREmail is the data protection email of the application.
+	GDPREmail string
+
+	// KeySize is the size in bits of the generated RSA key. Defaults to [SandboxApplicationDefaultKeySize].
+	KeySize int
+
+	// CertificateValidity is the validity of the generated certificate. Defaults to
+	// [SandboxApplicationDefaultCertificateValidity].
+	CertificateValidity time.Duration
+}
+
+// SandboxApplication represents a registered sandbox application with ready-to-use credentials.
+type SandboxApplication struct {
+	// ApplicationID is the unique identifier of the registered application.
+	ApplicationID string
+
+	// PrivateKey is the generated private key of the application.
+	PrivateKey *rsa.PrivateKey
+
+	// PrivateKeyPEM is the PEM-encoded (PKCS #8) private key of the application.
+	PrivateKeyPEM []byte
+
+	// CertificatePEM is the PEM-encoded certificate registered for the application.
+	CertificatePEM []byte
+}
+
+// NewClient creates a new Enable Banking API client authenticated as the sandbox application.
+func (a *SandboxApplication) NewClient(options ...enablebankinggo.ClientOption) (*enablebankinggo.APIClient, error) {
+	return enablebankinggo.NewClient(a.ApplicationID, a.PrivateKey, options...)
+}
+
+// CreateSandboxApplication creates a sandbox application end-to-end by generating a key pair and a
+// self-signed certificate, and registering the application with the certificate.
+func (c *APIClient) CreateSandboxApplication(ctx context.Context, req *CreateSandboxApplicationRequest) (*SandboxApplication, error) {
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	if req.Name == "" {
+		return nil, errors.New("req.Name cannot be empty")
+	}
+
+	keySize := req.KeySize
+	if keySize == 0 {
+		keySize = SandboxApplicationDefaultKeySize
+	}
+
+	validity := req.CertificateValidity
+	if validity == 0 {
+		validity = SandboxApplicationDefaultCertificateValidity
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	certificatePEM, err := newSelfSignedCertificatePEM(privateKey, req.Name, validity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	resp, err := c.RegisterApplication(ctx, &RegisterApplicationRequest{
+		Environment:        enablebankinggo.SandboxEnvironment,
+		Name:               req.Name,
+		RedirectUrls:       req.RedirectUrls,
+		Description:        req.Description,
+		PrivacyURL:         req.PrivacyURL,
+		TermsURL:           req.TermsURL,
+		GDPREmail:          req.GDPREmail,
+		CertificateContent: string(certificatePEM),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register application: %w", err)
+	}
+
+	return &SandboxApplication{
+		ApplicationID:  resp.ApplicationID,
+		PrivateKey:     privateKey,
+		PrivateKeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyDER}),
+		CertificatePEM: certificatePEM,
+	}, nil
+}
+
+func newSelfSignedCertificatePEM(privateKey *rsa.PrivateKey, commonName string, validity time.Duration) ([]byte, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER}), nil
+}
@@ -0,0 +1,59 @@
+package controlpanel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// TokenStore represents a persistent store for the authentication token, allowing the token to survive
+// process restarts.
+type TokenStore interface {
+	// LoadToken loads the stored token. Returns nil if no token is stored.
+	LoadToken(ctx context.Context) (*Token, error)
+
+	// SaveToken saves the token.
+	SaveToken(ctx context.Context, token *Token) error
+}
+
+// FileTokenStore is a [TokenStore] persisting the token as JSON in a file.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore creates a new [FileTokenStore] persisting the token in the file at the provided path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// LoadToken loads the token from the file. Returns nil if the file doesn't exist.
+func (s *FileTokenStore) LoadToken(_ context.Context) (*Token, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var token Token
+	err = json.Unmarshal(b, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// SaveToken saves the token to the file, only readable and writable by the current user.
+func (s *FileTokenStore) SaveToken(_ context.Context, token *Token) error {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, b, 0o600)
+}
@@ -0,0 +1,443 @@
+// Package counterparty provides a directory of counterparties built from transaction history, e.g. for
+// payee autocomplete and anomaly detection.
+package counterparty
+
+import (
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// DefaultMaxAmounts is the default number of most recent amounts kept per counterparty.
+const DefaultMaxAmounts = 100
+
+// legalSuffixes is the legal entity suffixes removed from normalized names.
+var legalSuffixes = []string{"ab", "as", "asa", "aps", "bv", "gmbh", "inc", "llc", "ltd", "nv", "oy", "oyj", "plc", "sa", "ag", "ug", "srl"}
+
+// nameTransliterations is the replacements of common Nordic and German characters in normalized names.
+var nameTransliterations = strings.NewReplacer("å", "a", "ä", "a", "æ", "ae", "ö", "o", "ø", "o", "ü", "u", "ß", "ss", "é", "e", "è", "e")
+
+// NormalizeName returns the normalized form of a counterparty name, i.e. lower case letters and digits
+// separated by single spaces, without common legal entity suffixes, e.g. acme for "ACME Oy".
+func NormalizeName(name string) string {
+	name = nameTransliterations.Replace(strings.ToLower(name))
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for len(fields) > 1 && slices.Contains(legalSuffixes, fields[len(fields)-1]) {
+		fields = fields[:len(fields)-1]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// Counterparty represents a counterparty in the directory.
+type Counterparty struct {
+	// Key is the normalized name of the counterparty, or its IBAN if the name isn't known.
+	Key string `json:"key"`
+
+	// Name is the most recent name of the counterparty.
+	Name string `json:"name,omitempty"`
+
+	// IBANs is the IBANs of the counterparty, sorted.
+	IBANs []string `json:"ibans,omitempty"`
+
+	// Transactions is the number of transactions with the counterparty.
+	Transactions int `json:"transactions"`
+
+	// Currency is the currency of the amounts.
+	Currency string `json:"currency,omitempty"`
+
+	// TypicalAmount is the median of the recent signed amounts, negative for payments to the
+	// counterparty.
+	TypicalAmount string `json:"typical_amount"`
+
+	// MinAmount is the smallest recent signed amount.
+	MinAmount string `json:"min_amount"`
+
+	// MaxAmount is the largest recent signed amount.
+	MaxAmount string `json:"max_amount"`
+
+	// FirstSeen is the date of the first transaction with the counterparty.
+	FirstSeen time.Time `json:"first_seen"`
+
+	// LastSeen is the date of the last transaction with the counterparty.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// AnomalyKind represents the kind of an anomaly.
+type AnomalyKind string
+
+const (
+	// NewCounterpartyAnomalyKind is a transaction with a counterparty not in the directory.
+	NewCounterpartyAnomalyKind AnomalyKind = "new_counterparty"
+
+	// UnusualAmountAnomalyKind is a transaction with an amount far from the typical amount of the
+	// counterparty.
+	UnusualAmountAnomalyKind AnomalyKind = "unusual_amount"
+
+	// NewIBANAnomalyKind is a transaction with a known counterparty using an IBAN not seen before.
+	NewIBANAnomalyKind AnomalyKind = "new_iban"
+)
+
+// Anomaly represents an anomaly of a transaction compared with the history of its counterparty.
+type Anomaly struct {
+	// Kind is the kind of anomaly.
+	Kind AnomalyKind
+
+	// Counterparty is the counterparty, nil for NewCounterpartyAnomalyKind.
+	Counterparty *Counterparty
+}
+
+type entry struct {
+	name      string
+	ibans     map[string]bool
+	count     int
+	currency  string
+	amounts   []*big.Rat
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// Directory is a directory of counterparties built from transaction history. Transactions are
+// identified by entry reference or transaction ID, adding the same transaction again has no effect.
+type Directory struct {
+	// MaxAmounts is the number of most recent amounts kept per counterparty. Defaults to
+	// DefaultMaxAmounts.
+	MaxAmounts int
+
+	// UnusualFactor is the factor the absolute amount of a transaction must differ from the typical
+	// amount by to be unusual. Defaults to 3.
+	UnusualFactor int
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	ibans   map[string]string
+	seen    map[string]bool
+}
+
+// NewDirectory creates a new empty directory.
+func NewDirectory() *Directory {
+	return &Directory{
+		entries: map[string]*entry{},
+		ibans:   map[string]string{},
+		seen:    map[string]bool{},
+	}
+}
+
+// Add adds the booked transactions to the directory. Transactions without a counterparty name or IBAN
+// are ignored.
+func (d *Directory) Add(transactions ...*enablebankinggo.Transaction) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, tx := range transactions {
+		if tx == nil || (tx.Status != enablebankinggo.AccountedTransactionStatus && tx.Status != "") {
+			continue
+		}
+
+		if id := transactionID(tx); id != "" {
+			if d.seen[id] {
+				continue
+			}
+			d.seen[id] = true
+		}
+
+		name, iban := counterpartyOf(tx)
+		key := d.keyLocked(name, iban)
+		if key == "" {
+			continue
+		}
+
+		e := d.entries[key]
+		if e == nil {
+			e = &entry{ibans: map[string]bool{}}
+			d.entries[key] = e
+		}
+
+		if name != "" {
+			e.name = name
+		}
+
+		if iban != "" {
+			e.ibans[iban] = true
+			d.ibans[iban] = key
+		}
+
+		e.count++
+		if date := dateOf(tx); !date.IsZero() {
+			if e.firstSeen.IsZero() || date.Before(e.firstSeen) {
+				e.firstSeen = date
+			}
+
+			if date.After(e.lastSeen) {
+				e.lastSeen = date
+			}
+		}
+
+		if amount, ok := signedAmount(tx); ok {
+			e.currency = tx.TransactionAmount.Currency
+			e.amounts = append(e.amounts, amount)
+			if maxAmounts := d.maxAmounts(); len(e.amounts) > maxAmounts {
+				e.amounts = slices.Delete(e.amounts, 0, len(e.amounts)-maxAmounts)
+			}
+		}
+	}
+}
+
+// Get returns the counterparty of a name or IBAN.
+func (d *Directory) Get(nameOrIBAN string) (*Counterparty, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, ok := d.ibans[strings.ToUpper(strings.ReplaceAll(nameOrIBAN, " ", ""))]
+	if !ok {
+		key = NormalizeName(nameOrIBAN)
+	}
+
+	e, ok := d.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	return e.counterparty(key), true
+}
+
+// All returns all counterparties, sorted by number of transactions descending and key.
+func (d *Directory) All() []*Counterparty {
+	return d.Search("", 0)
+}
+
+// Search returns the counterparties with a normalized name or IBAN starting with prefix, or with a
+// word of the normalized name starting with prefix, sorted by number of transactions descending, for
+// payee autocomplete. Returns at most limit counterparties, unless limit is zero.
+func (d *Directory) Search(prefix string, limit int) []*Counterparty {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	normalized := NormalizeName(prefix)
+	ibanPrefix := strings.ToUpper(strings.ReplaceAll(prefix, " ", ""))
+
+	var result []*Counterparty
+	for key, e := range d.entries {
+		if !e.matches(key, normalized, ibanPrefix) {
+			continue
+		}
+
+		result = append(result, e.counterparty(key))
+	}
+
+	slices.SortFunc(result, func(a, b *Counterparty) int {
+		if a.Transactions != b.Transactions {
+			return b.Transactions - a.Transactions
+		}
+
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+// Anomalies returns the anomalies of tx compared with the history of its counterparty, e.g. before
+// adding it to the directory.
+func (d *Directory) Anomalies(tx *enablebankinggo.Transaction) []*Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name, iban := counterpartyOf(tx)
+	key := d.keyLocked(name, iban)
+	if key == "" {
+		return nil
+	}
+
+	e, ok := d.entries[key]
+	if !ok {
+		return []*Anomaly{{Kind: NewCounterpartyAnomalyKind}}
+	}
+
+	var anomalies []*Anomaly
+	if iban != "" && !e.ibans[iban] {
+		anomalies = append(anomalies, &Anomaly{Kind: NewIBANAnomalyKind, Counterparty: e.counterparty(key)})
+	}
+
+	amount, ok := signedAmount(tx)
+	if ok && len(e.amounts) > 0 && (tx.TransactionAmount.Currency == e.currency || e.currency == "") {
+		// Unusual amounts are in the opposite direction of, or a factor larger or smaller than, the
+		// typical amount.
+		typical := median(e.amounts)
+		factor := big.NewRat(int64(d.unusualFactor()), 1)
+		abs := new(big.Rat).Abs(amount)
+		typicalAbs := new(big.Rat).Abs(typical)
+
+		unusual := amount.Sign() != typical.Sign() ||
+			abs.Cmp(new(big.Rat).Mul(typicalAbs, factor)) > 0 ||
+			new(big.Rat).Mul(abs, factor).Cmp(typicalAbs) < 0
+		if unusual {
+			anomalies = append(anomalies, &Anomaly{Kind: UnusualAmountAnomalyKind, Counterparty: e.counterparty(key)})
+		}
+	}
+
+	return anomalies
+}
+
+func (d *Directory) keyLocked(name, iban string) string {
+	if iban != "" {
+		if key, ok := d.ibans[iban]; ok {
+			return key
+		}
+	}
+
+	if key := NormalizeName(name); key != "" {
+		return key
+	}
+
+	return iban
+}
+
+func (d *Directory) maxAmounts() int {
+	if d.MaxAmounts <= 0 {
+		return DefaultMaxAmounts
+	}
+
+	return d.MaxAmounts
+}
+
+func (d *Directory) unusualFactor() int {
+	if d.UnusualFactor <= 0 {
+		return 3
+	}
+
+	return d.UnusualFactor
+}
+
+func (e *entry) matches(key, normalized, ibanPrefix string) bool {
+	if normalized == "" && ibanPrefix == "" {
+		return true
+	}
+
+	if normalized != "" {
+		if strings.HasPrefix(key, normalized) || strings.Contains(key, " "+normalized) {
+			return true
+		}
+	}
+
+	if ibanPrefix != "" {
+		for iban := range e.ibans {
+			if strings.HasPrefix(iban, ibanPrefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (e *entry) counterparty(key string) *Counterparty {
+	c := &Counterparty{
+		Key:          key,
+		Name:         e.name,
+		Transactions: e.count,
+		Currency:     e.currency,
+		FirstSeen:    e.firstSeen,
+		LastSeen:     e.lastSeen,
+	}
+
+	for iban := range e.ibans {
+		c.IBANs = append(c.IBANs, iban)
+	}
+	slices.Sort(c.IBANs)
+
+	if len(e.amounts) > 0 {
+		c.TypicalAmount = median(e.amounts).FloatString(2)
+		c.MinAmount = slices.MinFunc(e.amounts, (*big.Rat).Cmp).FloatString(2)
+		c.MaxAmount = slices.MaxFunc(e.amounts, (*big.Rat).Cmp).FloatString(2)
+	}
+
+	return c
+}
+
+func median(amounts []*big.Rat) *big.Rat {
+	sorted := slices.Clone(amounts)
+	slices.SortFunc(sorted, (*big.Rat).Cmp)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+
+	m := new(big.Rat).Add(sorted[n/2-1], sorted[n/2])
+	return m.Quo(m, big.NewRat(2, 1))
+}
+
+// counterpartyOf returns the name and IBAN of the counterparty of tx, i.e. the creditor of debits and
+// the debtor of credits.
+func counterpartyOf(tx *enablebankinggo.Transaction) (string, string) {
+	party, account := tx.Creditor, tx.CreditorAccount
+	if tx.CreditDebitIndicator == enablebankinggo.CreditCreditDebitIndicator {
+		party, account = tx.Debtor, tx.DebtorAccount
+	}
+
+	var name, iban string
+	if party != nil {
+		name = strings.TrimSpace(party.Name)
+	}
+
+	if account != nil {
+		iban = strings.ToUpper(strings.ReplaceAll(account.IBAN, " ", ""))
+	}
+
+	return name, iban
+}
+
+func transactionID(tx *enablebankinggo.Transaction) string {
+	if tx.EntryReference != "" {
+		return "entry:" + tx.EntryReference
+	}
+
+	if tx.TransactionID != "" {
+		return "id:" + tx.TransactionID
+	}
+
+	return ""
+}
+
+func dateOf(tx *enablebankinggo.Transaction) time.Time {
+	for _, date := range []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate} {
+		if t, err := time.Parse(time.DateOnly, date); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// signedAmount returns the amount of tx, negative for debits.
+func signedAmount(tx *enablebankinggo.Transaction) (*big.Rat, bool) {
+	if tx.TransactionAmount == nil {
+		return nil, false
+	}
+
+	amount, ok := new(big.Rat).SetString(strings.TrimSpace(tx.TransactionAmount.Amount))
+	if !ok {
+		return nil, false
+	}
+
+	switch tx.CreditDebitIndicator {
+	case enablebankinggo.DebitCreditDebitIndicator:
+		amount.Neg(amount.Abs(amount))
+	case enablebankinggo.CreditCreditDebitIndicator:
+		amount.Abs(amount)
+	}
+
+	return amount, true
+}
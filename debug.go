@@ -0,0 +1,183 @@
+package enablebankinggo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxDumpedBodySize is the maximum size of bodies logged by [WithDebugDumps], larger bodies are truncated.
+const maxDumpedBodySize = 64 << 10
+
+// redacted replaces redacted values in dumps.
+const redacted = "[REDACTED]"
+
+// redactedHeaders is the canonical headers redacted in dumps.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Psu-Ip-Address":      true,
+}
+
+// redactedFields is the JSON fields and query parameters, in lower case, whose string values are redacted in
+// dumps.
+var redactedFields = map[string]bool{
+	"access_token":   true,
+	"refresh_token":  true,
+	"id_token":       true,
+	"token":          true,
+	"code":           true,
+	"password":       true,
+	"secret":         true,
+	"client_secret":  true,
+	"private_key":    true,
+	"iban":           true,
+	"bban":           true,
+	"identification": true,
+	"psu_id":         true,
+}
+
+// ibanPattern matches IBANs in values of other fields, e.g. remittance information.
+var ibanPattern = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]){11,30}\b`)
+
+// WithDebugDumps logs full dumps of every request and response using logger at debug level, for troubleshooting
+// ASPSP issues. Authorization headers, credentials, tokens, authorization codes and account identifiers, e.g.
+// IBANs, are redacted, making it safe to enable in staging. Bodies larger than 64 KiB are truncated and non-JSON
+// bodies are omitted. Responses are buffered in memory, also when streamed.
+func WithDebugDumps(logger *slog.Logger) ClientOption {
+	return func(c *APIClient) {
+		c.debugLogger = logger
+	}
+}
+
+// dumpRequest logs a redacted dump of req.
+func (c *APIClient) dumpRequest(req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(r)
+			_ = r.Close()
+		}
+	}
+
+	c.debugLogger.LogAttrs(req.Context(), slog.LevelDebug, "Enable Banking API request dump",
+		slog.String("request_id", req.Header.Get(RequestIDHeader)),
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		redactHeader(req.Header),
+		slog.String("body", redactBody(body)),
+	)
+}
+
+// dumpResponse logs a redacted dump of response, replacing the body with a buffered copy.
+func (c *APIClient) dumpResponse(req *http.Request, response *http.Response) error {
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.debugLogger.LogAttrs(req.Context(), slog.LevelDebug, "Enable Banking API response dump",
+		slog.String("request_id", req.Header.Get(RequestIDHeader)),
+		slog.Int("status", response.StatusCode),
+		redactHeader(response.Header),
+		slog.String("body", redactBody(body)),
+	)
+
+	return nil
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.String()
+	}
+
+	for k := range query {
+		if redactedFields[strings.ToLower(k)] {
+			query.Set(k, redacted)
+		}
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+func redactHeader(header http.Header) slog.Attr {
+	keys := slices.Sorted(maps.Keys(header))
+	attrs := make([]any, 0, len(keys))
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(k)] {
+			value = redacted
+		}
+
+		attrs = append(attrs, slog.String(k, value))
+	}
+
+	return slog.Group("header", attrs...)
+}
+
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Sprintf("<%d bytes non-JSON body omitted>", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes body omitted>", len(body))
+	}
+
+	if len(redacted) > maxDumpedBodySize {
+		return string(redacted[:maxDumpedBodySize]) + fmt.Sprintf("... <%d bytes truncated>", len(redacted)-maxDumpedBodySize)
+	}
+
+	return string(redacted)
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			// Only strings are redacted, e.g. keeping numeric error codes.
+			if _, ok := value.(string); ok && redactedFields[strings.ToLower(k)] {
+				v[k] = redacted
+				continue
+			}
+
+			v[k] = redactValue(value)
+		}
+
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+
+		return v
+	case string:
+		return ibanPattern.ReplaceAllString(v, redacted)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,161 @@
+package enablebankinggo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// WithStrictDecoding enables strict decoding of responses, failing on fields unknown to the models, e.g. to
+// catch model drift in CI. Decoding is lenient by default, ignoring unknown fields for resilience against
+// API additions. Fields of types with custom decoding, e.g. [AmountType], are always decoded leniently. Ignored
+// when a custom codec is set using [WithCodec].
+func WithStrictDecoding() ClientOption {
+	return func(c *APIClient) {
+		c.strictDecoding = true
+	}
+}
+
+// flexibleString is a string decodable from both JSON strings and numbers, since some fields, e.g.
+// amounts, occasionally arrive as numbers depending on the ASPSP.
+type flexibleString string
+
+func (s *flexibleString) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		// Plain ASCII strings, e.g. amounts, are copied directly, avoiding decoding them again.
+		if v, ok := plainString(b); ok {
+			*s = flexibleString(v)
+			return nil
+		}
+
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+
+		*s = flexibleString(v)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("expected string or number, got %s", b)
+	}
+
+	*s = flexibleString(n.String())
+	return nil
+}
+
+// plainString returns the contents of the JSON string b if it's printable ASCII without escapes.
+func plainString(b []byte) (string, bool) {
+	if len(b) < 2 || b[len(b)-1] != '"' {
+		return "", false
+	}
+
+	b = b[1 : len(b)-1]
+	for _, c := range b {
+		if c < 0x20 || c >= 0x80 || c == '"' || c == '\\' {
+			return "", false
+		}
+	}
+
+	return string(b), true
+}
+
+// flexibleInt64 is an int64 decodable from both JSON numbers and numeric strings.
+type flexibleInt64 int64
+
+func (i *flexibleInt64) UnmarshalJSON(b []byte) error {
+	v, err := parseFlexibleInt64(b)
+	if err != nil {
+		return err
+	}
+
+	*i = flexibleInt64(v)
+	return nil
+}
+
+func parseFlexibleInt64(b []byte) (int64, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return 0, nil
+	}
+
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, err
+		}
+
+		if s == "" {
+			return 0, nil
+		}
+	}
+
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f > math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("expected integer, got %s", b)
+	}
+
+	return int64(f), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting the amount as string or number.
+func (a *AmountType) UnmarshalJSON(b []byte) error {
+	type amountType AmountType
+	aux := struct {
+		*amountType
+		Amount flexibleString `json:"amount"`
+	}{amountType: (*amountType)(a)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	a.Amount = string(aux.Amount)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting the exchange rate as string or number.
+func (r *ExchangeRate) UnmarshalJSON(b []byte) error {
+	type exchangeRate ExchangeRate
+	aux := struct {
+		*exchangeRate
+		ExchangeRate flexibleString `json:"exchange_rate,omitempty"`
+	}{exchangeRate: (*exchangeRate)(r)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	r.ExchangeRate = string(aux.ExchangeRate)
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting the maximum consent validity as number or
+// numeric string.
+func (d *ASPSPData) UnmarshalJSON(b []byte) error {
+	type aspspData ASPSPData
+	aux := struct {
+		*aspspData
+		MaximumConsentValidity flexibleInt64 `json:"maximum_consent_validity"`
+	}{aspspData: (*aspspData)(d)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	d.MaximumConsentValidity = int64(aux.MaximumConsentValidity)
+	return nil
+}
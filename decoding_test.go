@@ -0,0 +1,95 @@
+package enablebankinggo
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+// isJSONNumber returns whether s is a JSON number literal.
+func isJSONNumber(s string) bool {
+	var n json.Number
+	return json.Unmarshal([]byte(s), &n) == nil && n.String() == s
+}
+
+func FuzzFlexibleString(f *testing.F) {
+	for _, seed := range []string{"12.34", "-0.5", "1e3", "100", "EUR", "", "null", `"quoted"`, "é", "{}"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		// Arbitrary input must not panic.
+		var raw flexibleString
+		_ = raw.UnmarshalJSON([]byte(s))
+
+		quoted, err := json.Marshal(s)
+		if err != nil {
+			t.Skip()
+		}
+
+		var fromString flexibleString
+		if err := json.Unmarshal(quoted, &fromString); err != nil {
+			t.Fatalf("failed to decode string %s: %v", quoted, err)
+		}
+
+		// Invalid UTF-8 is replaced when marshalling.
+		var want string
+		_ = json.Unmarshal(quoted, &want)
+		if string(fromString) != want {
+			t.Fatalf("expected %q, got %q", want, fromString)
+		}
+
+		if !isJSONNumber(s) {
+			return
+		}
+
+		var fromNumber flexibleString
+		if err := json.Unmarshal([]byte(s), &fromNumber); err != nil {
+			t.Fatalf("failed to decode number %s: %v", s, err)
+		}
+
+		if fromNumber != fromString {
+			t.Fatalf("number %s decoded to %q, string to %q", s, fromNumber, fromString)
+		}
+
+		var amountFromNumber, amountFromString AmountType
+		if err := json.Unmarshal([]byte(`{"currency":"EUR","amount":`+s+`}`), &amountFromNumber); err != nil {
+			t.Fatalf("failed to decode amount %s: %v", s, err)
+		}
+
+		if err := json.Unmarshal([]byte(`{"currency":"EUR","amount":`+string(quoted)+`}`), &amountFromString); err != nil {
+			t.Fatalf("failed to decode amount %s: %v", quoted, err)
+		}
+
+		if amountFromNumber != amountFromString {
+			t.Fatalf("amount %s decoded to %+v, string to %+v", s, amountFromNumber, amountFromString)
+		}
+	})
+}
+
+func FuzzFlexibleInt64(f *testing.F) {
+	for _, seed := range []string{"0", "42", "-7", "1e3", "1.5", "9223372036854775807", "9223372036854775808", "", "null", "abc"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		// Arbitrary input must not panic.
+		var raw flexibleInt64
+		_ = raw.UnmarshalJSON([]byte(s))
+
+		if !isJSONNumber(s) {
+			return
+		}
+
+		var fromNumber, fromString flexibleInt64
+		errNumber := json.Unmarshal([]byte(s), &fromNumber)
+		errString := json.Unmarshal([]byte(strconv.Quote(s)), &fromString)
+		if (errNumber == nil) != (errString == nil) {
+			t.Fatalf("number %s decoded with error %v, string with error %v", s, errNumber, errString)
+		}
+
+		if fromNumber != fromString {
+			t.Fatalf("number %s decoded to %d, string to %d", s, fromNumber, fromString)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package enablebankingtest
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ChaosFault represents a fault injected by a [ChaosTransport].
+type ChaosFault string
+
+const (
+	// TimeoutChaosFault fails the request with a timeout error, after ChaosConfig.TimeoutDelay.
+	TimeoutChaosFault ChaosFault = "timeout"
+
+	// TooManyRequestsChaosFault responds with 429 Too Many Requests and a Retry-After header.
+	TooManyRequestsChaosFault ChaosFault = "too_many_requests"
+
+	// ServerErrorChaosFault responds with 503 Service Unavailable.
+	ServerErrorChaosFault ChaosFault = "server_error"
+
+	// MalformedJSONChaosFault sends the request and truncates the response body, making it invalid JSON.
+	MalformedJSONChaosFault ChaosFault = "malformed_json"
+
+	// ConnectionResetChaosFault fails the request with a connection reset error.
+	ConnectionResetChaosFault ChaosFault = "connection_reset"
+)
+
+// ChaosConfig represents the configuration of a [ChaosTransport]. Probabilities are between 0 and 1, and
+// at most one fault is injected per request.
+type ChaosConfig struct {
+	// TimeoutProbability is the probability of a TimeoutChaosFault.
+	TimeoutProbability float64
+
+	// TooManyRequestsProbability is the probability of a TooManyRequestsChaosFault.
+	TooManyRequestsProbability float64
+
+	// ServerErrorProbability is the probability of a ServerErrorChaosFault.
+	ServerErrorProbability float64
+
+	// MalformedJSONProbability is the probability of a MalformedJSONChaosFault.
+	MalformedJSONProbability float64
+
+	// ConnectionResetProbability is the probability of a ConnectionResetChaosFault.
+	ConnectionResetProbability float64
+
+	// TimeoutDelay is the delay before failing with a timeout, cut short if the request context is done.
+	TimeoutDelay time.Duration
+
+	// RetryAfter is the Retry-After duration of 429 responses. Defaults to 1 second.
+	RetryAfter time.Duration
+
+	// Seed is the seed of the random number generator, making the injected faults deterministic.
+	Seed uint64
+}
+
+// ChaosTransport is a [http.RoundTripper] injecting faults, e.g. timeouts, 429s, malformed JSON and
+// connection resets, with configured probabilities, for validating the resilience of code built on the
+// enablebankinggo package.
+type ChaosTransport struct {
+	next   http.RoundTripper
+	config ChaosConfig
+
+	mu       sync.Mutex
+	rnd      *rand.Rand
+	enabled  bool
+	requests int
+	faults   map[ChaosFault]int
+}
+
+// NewChaosTransport creates a new chaos transport wrapping next. If next is nil, [http.DefaultTransport]
+// is used.
+func NewChaosTransport(next http.RoundTripper, config ChaosConfig) *ChaosTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = time.Second
+	}
+
+	return &ChaosTransport{
+		next:    next,
+		config:  config,
+		rnd:     rand.New(rand.NewPCG(config.Seed, config.Seed)),
+		enabled: true,
+		faults:  map[ChaosFault]int{},
+	}
+}
+
+// SetEnabled enables or disables fault injection.
+func (t *ChaosTransport) SetEnabled(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.enabled = enabled
+}
+
+// Requests returns the number of requests handled.
+func (t *ChaosTransport) Requests() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.requests
+}
+
+// Faults returns the number of injected faults by fault.
+func (t *ChaosTransport) Faults() map[ChaosFault]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	faults := make(map[ChaosFault]int, len(t.faults))
+	for fault, count := range t.faults {
+		faults[fault] = count
+	}
+
+	return faults
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.nextFault()
+
+	switch fault {
+	case TimeoutChaosFault:
+		closeBody(req)
+		if t.config.TimeoutDelay > 0 {
+			timer := time.NewTimer(t.config.TimeoutDelay)
+			defer timer.Stop()
+
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
+		}
+
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: chaosTimeoutError{}}
+	case ConnectionResetChaosFault:
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case TooManyRequestsChaosFault:
+		closeBody(req)
+		resp := chaosErrorResponse(req, http.StatusTooManyRequests, enablebankinggo.ASPSPRateLimitExceededErrorCode, "Too many requests (injected)")
+		resp.Header.Set("Retry-After", strconv.Itoa(int(t.config.RetryAfter.Round(time.Second)/time.Second)))
+		return resp, nil
+	case ServerErrorChaosFault:
+		closeBody(req)
+		return chaosErrorResponse(req, http.StatusServiceUnavailable, "", "Service unavailable (injected)"), nil
+	case MalformedJSONChaosFault:
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		body = body[:len(body)/2]
+		if len(body) == 0 {
+			body = []byte("{")
+		}
+
+		resp.Body = io.NopCloser(strings.NewReader(string(body)))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *ChaosTransport) nextFault() ChaosFault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	if !t.enabled {
+		return ""
+	}
+
+	p := t.rnd.Float64()
+	for _, candidate := range []struct {
+		fault       ChaosFault
+		probability float64
+	}{
+		{fault: TimeoutChaosFault, probability: t.config.TimeoutProbability},
+		{fault: TooManyRequestsChaosFault, probability: t.config.TooManyRequestsProbability},
+		{fault: ServerErrorChaosFault, probability: t.config.ServerErrorProbability},
+		{fault: MalformedJSONChaosFault, probability: t.config.MalformedJSONProbability},
+		{fault: ConnectionResetChaosFault, probability: t.config.ConnectionResetProbability},
+	} {
+		if p < candidate.probability {
+			t.faults[candidate.fault]++
+			return candidate.fault
+		}
+
+		p -= candidate.probability
+	}
+
+	return ""
+}
+
+func chaosErrorResponse(req *http.Request, statusCode int, errorCode enablebankinggo.ErrorCode, message string) *http.Response {
+	body, _ := json.Marshal(&enablebankinggo.ErrorResponse{
+		Code:      statusCode,
+		Message:   message,
+		ErrorCode: errorCode,
+	})
+
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// chaosTimeoutError is an injected timeout error, implementing [net.Error].
+type chaosTimeoutError struct{}
+
+func (chaosTimeoutError) Error() string   { return "i/o timeout (injected)" }
+func (chaosTimeoutError) Timeout() bool   { return true }
+func (chaosTimeoutError) Temporary() bool { return true }
+
+var _ net.Error = chaosTimeoutError{}
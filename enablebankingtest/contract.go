@@ -0,0 +1,411 @@
+package enablebankingtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ContractViolationKind represents the kind of a [ContractViolation].
+type ContractViolationKind string
+
+const (
+	// MissingSchemaViolation indicates that the OpenAPI document has no schema for a model.
+	MissingSchemaViolation ContractViolationKind = "missing_schema"
+
+	// MissingFieldViolation indicates that a model is missing a documented field.
+	MissingFieldViolation ContractViolationKind = "missing_field"
+
+	// UndocumentedFieldViolation indicates that a model has a field that isn't documented.
+	UndocumentedFieldViolation ContractViolationKind = "undocumented_field"
+
+	// TypeMismatchViolation indicates that a model field has a type not matching the documented type.
+	TypeMismatchViolation ContractViolationKind = "type_mismatch"
+
+	// UnknownEnumValueViolation indicates that an enumeration is missing a documented value.
+	UnknownEnumValueViolation ContractViolationKind = "unknown_enum_value"
+
+	// UndocumentedEnumValueViolation indicates that an enumeration has a value that isn't documented.
+	UndocumentedEnumValueViolation ContractViolationKind = "undocumented_enum_value"
+)
+
+// ContractViolation represents a difference between the SDK models and the OpenAPI document.
+type ContractViolation struct {
+	// Schema is the name of the schema.
+	Schema string
+
+	// Field is the JSON name of the field, if any.
+	Field string
+
+	// Kind is the kind of violation.
+	Kind ContractViolationKind
+
+	// Message describes the violation.
+	Message string
+}
+
+func (v *ContractViolation) String() string {
+	if v.Field == "" {
+		return fmt.Sprintf("%s: %s: %s", v.Schema, v.Kind, v.Message)
+	}
+
+	return fmt.Sprintf("%s.%s: %s: %s", v.Schema, v.Field, v.Kind, v.Message)
+}
+
+// ContractModels is the request and response models verified by [VerifyContract], by schema name.
+var ContractModels = map[string]any{
+	"Access":                             enablebankinggo.Access{},
+	"AccountIdentification":              enablebankinggo.AccountIdentification{},
+	"AccountResource":                    enablebankinggo.AccountResource{},
+	"AmountType":                         enablebankinggo.AmountType{},
+	"ASPSP":                              enablebankinggo.ASPSP{},
+	"ASPSPData":                          enablebankinggo.ASPSPData{},
+	"ASPSPGroup":                         enablebankinggo.ASPSPGroup{},
+	"AuthMethod":                         enablebankinggo.AuthMethod{},
+	"AuthorizeSessionRequest":            enablebankinggo.AuthorizeSessionRequest{},
+	"AuthorizeSessionResponse":           enablebankinggo.AuthorizeSessionResponse{},
+	"BalanceResource":                    enablebankinggo.BalanceResource{},
+	"BankTransactionCode":                enablebankinggo.BankTransactionCode{},
+	"Beneficiary":                        enablebankinggo.Beneficiary{},
+	"ClearingSystemMemberIdentification": enablebankinggo.ClearingSystemMemberIdentification{},
+	"ContactDetails":                     enablebankinggo.ContactDetails{},
+	"CreatePaymentRequest":               enablebankinggo.CreatePaymentRequest{},
+	"CreatePaymentResponse":              enablebankinggo.CreatePaymentResponse{},
+	"Credential":                         enablebankinggo.Credential{},
+	"CreditTransferTransaction":          enablebankinggo.CreditTransferTransaction{},
+	"ErrorResponse":                      enablebankinggo.ErrorResponse{},
+	"ExchangeRate":                       enablebankinggo.ExchangeRate{},
+	"FinancialInstitutionIdentification": enablebankinggo.FinancialInstitutionIdentification{},
+	"GenericIdentification":              enablebankinggo.GenericIdentification{},
+	"GetApplicationResponse":             enablebankinggo.GetApplicationResponse{},
+	"GetASPSPsResponse":                  enablebankinggo.GetASPSPsResponse{},
+	"GetPaymentResponse":                 enablebankinggo.GetPaymentResponse{},
+	"GetSessionResponse":                 enablebankinggo.GetSessionResponse{},
+	"HalBalances":                        enablebankinggo.HalBalances{},
+	"HalTransactions":                    enablebankinggo.HalTransactions{},
+	"PartyIdentification":                enablebankinggo.PartyIdentification{},
+	"PaymentIdentification":              enablebankinggo.PaymentIdentification{},
+	"PaymentRequestResource":             enablebankinggo.PaymentRequestResource{},
+	"PaymentTypeInformation":             enablebankinggo.PaymentTypeInformation{},
+	"PostalAddress":                      enablebankinggo.PostalAddress{},
+	"RegulatoryAuthority":                enablebankinggo.RegulatoryAuthority{},
+	"RegulatoryReporting":                enablebankinggo.RegulatoryReporting{},
+	"RegulatoryReportingDetails":         enablebankinggo.RegulatoryReportingDetails{},
+	"SessionAccount":                     enablebankinggo.SessionAccount{},
+	"StartAuthorizationRequest":          enablebankinggo.StartAuthorizationRequest{},
+	"StartAuthorizationResponse":         enablebankinggo.StartAuthorizationResponse{},
+	"SuccessResponse":                    enablebankinggo.SuccessResponse{},
+	"SupportedPayment":                   enablebankinggo.SupportedPayment{},
+	"Transaction":                        enablebankinggo.Transaction{},
+}
+
+// ContractEnumerations is the enumerations verified by [VerifyContract], by schema name.
+var ContractEnumerations = map[string]any{
+	"AddressType":            enablebankinggo.AddressType(""),
+	"AuthenticationApproach": enablebankinggo.AuthenticationApproach(""),
+	"BalanceStatus":          enablebankinggo.BalanceType(""),
+	"CashAccountType":        enablebankinggo.CashAccountType(""),
+	"ChargeBearer":           enablebankinggo.ChargeBearer(""),
+	"CreditDebitIndicator":   enablebankinggo.CreditDebitIndicator(""),
+	"Environment":            enablebankinggo.Environment(""),
+	"ExecutionRule":          enablebankinggo.ExecutionRule(""),
+	"FrequencyCode":          enablebankinggo.FrequencyCode(""),
+	"PaymentStatus":          enablebankinggo.PaymentStatus(""),
+	"PaymentType":            enablebankinggo.PaymentType(""),
+	"PSUType":                enablebankinggo.PSUType(""),
+	"PurposeCode":            enablebankinggo.PurposeCode(""),
+	"RateType":               enablebankinggo.RateType(""),
+	"ReferenceNumberScheme":  enablebankinggo.ReferenceNumberScheme(""),
+	"SchemeName":             enablebankinggo.SchemeName(""),
+	"Service":                enablebankinggo.Service(""),
+	"ServiceLevel":           enablebankinggo.ServiceLevel(""),
+	"SessionStatus":          enablebankinggo.SessionStatus(""),
+	"TransactionStatus":      enablebankinggo.TransactionStatus(""),
+	"Usage":                  enablebankinggo.Usage(""),
+}
+
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Format     string                    `json:"format"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+	AllOf      []*openAPISchema          `json:"allOf"`
+	AnyOf      []*openAPISchema          `json:"anyOf"`
+	OneOf      []*openAPISchema          `json:"oneOf"`
+	Enum       []any                     `json:"enum"`
+}
+
+type validator interface {
+	IsValid() bool
+}
+
+// VerifyContract validates the SDK's request and response models, i.e. [ContractModels] and
+// [ContractEnumerations], against Enable Banking's OpenAPI document (JSON), returning missing and
+// undocumented fields, wrong types and unknown enumeration values. Run it, e.g. in a scheduled CI job,
+// against the latest published document to catch API changes before they're hit in production.
+func VerifyContract(spec []byte) ([]*ContractViolation, error) {
+	var doc openAPIDocument
+	err := json.Unmarshal(spec, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+
+	if len(doc.Components.Schemas) == 0 {
+		return nil, errors.New("OpenAPI document has no component schemas")
+	}
+
+	var violations []*ContractViolation
+	for _, name := range sortedKeys(ContractModels) {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			violations = append(violations, &ContractViolation{Schema: name, Kind: MissingSchemaViolation, Message: "schema not found"})
+			continue
+		}
+
+		violations = append(violations, doc.verifyModel(name, schema, reflect.TypeOf(ContractModels[name]))...)
+	}
+
+	for _, name := range sortedKeys(ContractEnumerations) {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			violations = append(violations, &ContractViolation{Schema: name, Kind: MissingSchemaViolation, Message: "schema not found"})
+			continue
+		}
+
+		violations = append(violations, doc.verifyEnumeration(name, doc.resolve(schema), reflect.TypeOf(ContractEnumerations[name]))...)
+	}
+
+	return violations, nil
+}
+
+func (doc *openAPIDocument) verifyModel(name string, schema *openAPISchema, t reflect.Type) []*ContractViolation {
+	properties := doc.properties(schema)
+	fields := jsonFields(t)
+
+	var violations []*ContractViolation
+	for _, property := range sortedKeys(properties) {
+		field, ok := fields[property]
+		if !ok {
+			violations = append(violations, &ContractViolation{Schema: name, Field: property, Kind: MissingFieldViolation, Message: "documented field not found in " + t.Name()})
+			continue
+		}
+
+		if msg := doc.typeMismatch(properties[property], field.Type); msg != "" {
+			violations = append(violations, &ContractViolation{Schema: name, Field: property, Kind: TypeMismatchViolation, Message: msg})
+		}
+	}
+
+	for _, field := range sortedKeys(fields) {
+		if _, ok := properties[field]; !ok {
+			violations = append(violations, &ContractViolation{Schema: name, Field: field, Kind: UndocumentedFieldViolation, Message: "field of " + t.Name() + " not documented"})
+		}
+	}
+
+	return violations
+}
+
+func (doc *openAPIDocument) verifyEnumeration(name string, schema *openAPISchema, t reflect.Type) []*ContractViolation {
+	var violations []*ContractViolation
+	documented := map[string]bool{}
+	for _, value := range schema.Enum {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		documented[s] = true
+		v, ok := reflect.ValueOf(s).Convert(t).Interface().(validator)
+		if ok && !v.IsValid() {
+			violations = append(violations, &ContractViolation{Schema: name, Kind: UnknownEnumValueViolation, Message: fmt.Sprintf("value %q not known by %s", s, t.Name())})
+		}
+	}
+
+	if known, ok := enumerationValues(t); ok {
+		for _, value := range known {
+			if !documented[value] {
+				violations = append(violations, &ContractViolation{Schema: name, Kind: UndocumentedEnumValueViolation, Message: fmt.Sprintf("value %q of %s not documented", value, t.Name())})
+			}
+		}
+	}
+
+	return violations
+}
+
+// typeMismatch returns a description of the mismatch between the documented type and t, if any.
+func (doc *openAPIDocument) typeMismatch(schema *openAPISchema, t reflect.Type) string {
+	schema = doc.resolve(schema)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schemaType := schema.Type
+	if schemaType == "" {
+		schemaType = doc.variantType(schema)
+	}
+
+	if schemaType == "" || t.Kind() == reflect.Interface {
+		return ""
+	}
+
+	ok := true
+	switch schemaType {
+	case "string":
+		ok = t.Kind() == reflect.String || (t == reflect.TypeOf(time.Time{}) && strings.HasPrefix(schema.Format, "date"))
+	case "integer":
+		ok = slices.Contains([]reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64}, t.Kind())
+	case "number":
+		ok = t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case "boolean":
+		ok = t.Kind() == reflect.Bool
+	case "array":
+		ok = t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+		if ok && schema.Items != nil {
+			return doc.typeMismatch(schema.Items, t.Elem())
+		}
+	case "object":
+		ok = t.Kind() == reflect.Struct || t.Kind() == reflect.Map
+	}
+
+	if ok {
+		return ""
+	}
+
+	return fmt.Sprintf("documented as %s, got %s", schemaType, t)
+}
+
+// variantType returns the type shared by all allOf/anyOf/oneOf variants of schema, if any.
+func (doc *openAPIDocument) variantType(schema *openAPISchema) string {
+	variants := slices.Concat(schema.AllOf, schema.AnyOf, schema.OneOf)
+	schemaType := ""
+	for _, variant := range variants {
+		variant = doc.resolve(variant)
+		if variant.Type == "null" {
+			continue
+		}
+
+		if variant.Type == "" || (schemaType != "" && schemaType != variant.Type) {
+			return ""
+		}
+
+		schemaType = variant.Type
+	}
+
+	return schemaType
+}
+
+func (doc *openAPIDocument) resolve(schema *openAPISchema) *openAPISchema {
+	for range 32 {
+		if schema.Ref == "" {
+			if len(schema.AllOf) == 1 && schema.Type == "" && len(schema.Properties) == 0 {
+				schema = schema.AllOf[0]
+				continue
+			}
+
+			return schema
+		}
+
+		resolved, ok := doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return &openAPISchema{}
+		}
+
+		schema = resolved
+	}
+
+	return schema
+}
+
+// properties returns the properties of schema, including the properties of allOf schemas.
+func (doc *openAPIDocument) properties(schema *openAPISchema) map[string]*openAPISchema {
+	schema = doc.resolve(schema)
+	properties := map[string]*openAPISchema{}
+	for _, part := range schema.AllOf {
+		for name, property := range doc.properties(part) {
+			properties[name] = property
+		}
+	}
+
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+
+	return properties
+}
+
+// jsonFields returns the fields of t by JSON name, including fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = field
+	}
+
+	return fields
+}
+
+// enumerationValues returns the values of an enumeration type t using its Descriptions function, e.g.
+// BalanceTypeDescriptions, if exposed by the enablebankinggo package.
+func enumerationValues(t reflect.Type) ([]string, bool) {
+	values, ok := enumerationKeys[t]
+	if !ok {
+		return nil, false
+	}
+
+	return values(), true
+}
+
+var enumerationKeys = map[reflect.Type]func() []string{
+	reflect.TypeOf(enablebankinggo.BalanceType("")):          descriptionKeys(enablebankinggo.BalanceTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.CreditDebitIndicator("")): descriptionKeys(enablebankinggo.CreditDebitIndicatorDescriptions),
+	reflect.TypeOf(enablebankinggo.PSUType("")):              descriptionKeys(enablebankinggo.PSUTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.RateType("")):             descriptionKeys(enablebankinggo.RateTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.Service("")):              descriptionKeys(enablebankinggo.ServiceDescriptions),
+	reflect.TypeOf(enablebankinggo.TransactionStatus("")):    descriptionKeys(enablebankinggo.TransactionStatusDescriptions),
+}
+
+func descriptionKeys[K ~string](descriptions func() map[K]string) func() []string {
+	return func() []string {
+		keys := make([]string, 0, len(descriptions()))
+		for key := range descriptions() {
+			keys = append(keys, string(key))
+		}
+		slices.Sort(keys)
+
+		return keys
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,95 @@
+package enablebankingtest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openAPIDocumentPath is the vendored copy of Enable Banking's OpenAPI document, as published with the API
+// reference. Update it when the API changes.
+var openAPIDocumentPath = filepath.Join("testdata", "openapi.json")
+
+func TestVerifyContract(t *testing.T) {
+	spec, err := os.ReadFile(openAPIDocumentPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s not vendored, download Enable Banking's OpenAPI document to verify the contract", openAPIDocumentPath)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations, err := VerifyContract(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range violations {
+		t.Error(v)
+	}
+}
+
+func TestVerifyContractReportsViolations(t *testing.T) {
+	spec := []byte(`{
+		"components": {
+			"schemas": {
+				"AmountType": {
+					"type": "object",
+					"properties": {
+						"currency": {"type": "string"},
+						"amount": {"type": "integer"},
+						"rounding": {"type": "string"}
+					}
+				},
+				"ChargeBearer": {
+					"type": "string",
+					"enum": ["CRED", "DEBT", "SHAR", "SLEV", "ALLC"]
+				}
+			}
+		}
+	}`)
+
+	violations, err := VerifyContract(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]bool{}
+	for _, v := range violations {
+		if v.Schema == "AmountType" || v.Schema == "ChargeBearer" {
+			got[v.String()] = true
+		}
+	}
+
+	want := []string{
+		"AmountType.amount: type_mismatch",
+		"AmountType.rounding: missing_field",
+		`ChargeBearer: unknown_enum_value: value "ALLC"`,
+	}
+
+	for _, prefix := range want {
+		found := false
+		for v := range got {
+			if strings.HasPrefix(v, prefix) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("expected violation %q, got %v", prefix, got)
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("expected %d violations, got %v", len(want), got)
+	}
+
+	if _, err := VerifyContract([]byte(`{}`)); err == nil {
+		t.Error("expected an error for a document without schemas")
+	}
+}
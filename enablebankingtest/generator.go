@@ -0,0 +1,366 @@
+package enablebankingtest
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// DefaultGeneratorLocale is the default locale of a [Generator].
+const DefaultGeneratorLocale = "FI"
+
+type generatorLocale struct {
+	currency string
+	// bbanFormat is the format of the BBAN, where 'A' is an uppercase letter and '9' is a digit.
+	bbanFormat string
+	banks      []generatorBank
+	names      []string
+}
+
+type generatorBank struct {
+	name string
+	bic  string
+	// code is the bank code prefixing the BBAN.
+	code string
+}
+
+var generatorLocales = map[string]*generatorLocale{
+	"FI": {
+		currency:   "EUR",
+		bbanFormat: "99999999999999",
+		banks: []generatorBank{
+			{name: "Nordea", bic: "NDEAFIHH", code: "1"},
+			{name: "OP", bic: "OKOYFIHH", code: "5"},
+			{name: "Danske Bank", bic: "DABAFIHH", code: "8"},
+		},
+		names: []string{"Matti Virtanen", "Aino Korhonen", "Juha Mäkinen", "Laura Nieminen"},
+	},
+	"SE": {
+		currency:   "SEK",
+		bbanFormat: "99999999999999999999",
+		banks: []generatorBank{
+			{name: "Swedbank", bic: "SWEDSESS", code: "800"},
+			{name: "Handelsbanken", bic: "HANDSESS", code: "600"},
+			{name: "SEB", bic: "ESSESESS", code: "500"},
+		},
+		names: []string{"Erik Andersson", "Anna Johansson", "Lars Karlsson", "Maria Nilsson"},
+	},
+	"NO": {
+		currency:   "NOK",
+		bbanFormat: "99999999999",
+		banks: []generatorBank{
+			{name: "DNB", bic: "DNBANOKK", code: "1"},
+			{name: "SpareBank 1", bic: "SPRONO22", code: "4"},
+		},
+		names: []string{"Ole Hansen", "Ingrid Johansen", "Per Olsen", "Kari Larsen"},
+	},
+	"DK": {
+		currency:   "DKK",
+		bbanFormat: "99999999999999",
+		banks: []generatorBank{
+			{name: "Danske Bank", bic: "DABADKKK", code: "3"},
+			{name: "Nordea", bic: "NDEADKKK", code: "2"},
+		},
+		names: []string{"Mads Nielsen", "Sofie Jensen", "Rasmus Pedersen", "Emma Christensen"},
+	},
+	"DE": {
+		currency:   "EUR",
+		bbanFormat: "999999999999999999",
+		banks: []generatorBank{
+			{name: "Deutsche Bank", bic: "DEUTDEFF", code: "50070010"},
+			{name: "Commerzbank", bic: "COBADEFF", code: "50040000"},
+		},
+		names: []string{"Lukas Müller", "Hannah Schmidt", "Jonas Schneider", "Lea Fischer"},
+	},
+	"NL": {
+		currency:   "EUR",
+		bbanFormat: "AAAA9999999999",
+		banks: []generatorBank{
+			{name: "ING", bic: "INGBNL2A", code: "INGB"},
+			{name: "ABN AMRO", bic: "ABNANL2A", code: "ABNA"},
+			{name: "Rabobank", bic: "RABONL2U", code: "RABO"},
+		},
+		names: []string{"Daan de Jong", "Emma Jansen", "Sem de Vries", "Julia van den Berg"},
+	},
+	"GB": {
+		currency:   "GBP",
+		bbanFormat: "AAAA99999999999999",
+		banks: []generatorBank{
+			{name: "Barclays", bic: "BARCGB22", code: "BARC"},
+			{name: "HSBC", bic: "HBUKGB4B", code: "HBUK"},
+			{name: "Lloyds", bic: "LOYDGB2L", code: "LOYD"},
+		},
+		names: []string{"Oliver Smith", "Amelia Jones", "Harry Taylor", "Isla Brown"},
+	},
+}
+
+type generatorMerchant struct {
+	name string
+	mcc  string
+	// minCents and maxCents is the range of plausible purchase amounts, in minor units.
+	minCents int64
+	maxCents int64
+}
+
+var generatorMerchants = []generatorMerchant{
+	{name: "Grocery Market", mcc: "5411", minCents: 250, maxCents: 12000},
+	{name: "Corner Café", mcc: "5814", minCents: 300, maxCents: 1800},
+	{name: "City Restaurant", mcc: "5812", minCents: 1500, maxCents: 9000},
+	{name: "Fuel Station", mcc: "5541", minCents: 2000, maxCents: 9000},
+	{name: "Pharmacy", mcc: "5912", minCents: 500, maxCents: 4500},
+	{name: "Public Transport", mcc: "4111", minCents: 250, maxCents: 6000},
+	{name: "Online Bookstore", mcc: "5942", minCents: 900, maxCents: 4500},
+	{name: "Electronics Store", mcc: "5732", minCents: 1500, maxCents: 90000},
+	{name: "Streaming Service", mcc: "4899", minCents: 799, maxCents: 1999},
+	{name: "Clothing Store", mcc: "5651", minCents: 1500, maxCents: 15000},
+}
+
+// Generator generates realistic, deterministic fake account data, e.g. for load testing and demo
+// environments. Generators created using the same seed and locale generate the same data.
+type Generator struct {
+	rnd    *rand.Rand
+	locale string
+	loc    *generatorLocale
+}
+
+// NewGenerator creates a new generator using the provided seed, generating data for the provided
+// locale, i.e. a two-letter country code. Supported locales are DE, DK, FI, GB, NL, NO and SE.
+func NewGenerator(seed uint64, locale string) (*Generator, error) {
+	if locale == "" {
+		locale = DefaultGeneratorLocale
+	}
+
+	loc, ok := generatorLocales[strings.ToUpper(locale)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported locale %q", locale)
+	}
+
+	return &Generator{
+		rnd:    rand.New(rand.NewPCG(seed, seed)),
+		locale: strings.ToUpper(locale),
+		loc:    loc,
+	}, nil
+}
+
+// GeneratorLocales returns the sorted locales supported by [NewGenerator].
+func GeneratorLocales() []string {
+	locales := make([]string, 0, len(generatorLocales))
+	for locale := range generatorLocales {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+
+	return locales
+}
+
+// IBAN generates a valid IBAN, i.e. with correct check digits, of a bank in the locale.
+func (g *Generator) IBAN() string {
+	return g.iban(g.bank())
+}
+
+// Account generates an account resource of a bank in the locale, with the uid and identification hash
+// set.
+func (g *Generator) Account() *enablebankinggo.AccountResource {
+	bank := g.bank()
+	iban := g.iban(bank)
+	name := g.pick(g.loc.names)
+
+	return &enablebankinggo.AccountResource{
+		AccountID: &enablebankinggo.AccountIdentification{IBAN: iban},
+		AccountServicer: &enablebankinggo.FinancialInstitutionIdentification{
+			BICFI: bank.bic,
+			Name:  bank.name,
+		},
+		Name:                 name,
+		Usage:                enablebankinggo.PrivateAccountUsage,
+		CashAccountType:      enablebankinggo.CurrentCashAccountType,
+		Product:              "Current account",
+		Currency:             g.loc.currency,
+		UID:                  g.uuid(),
+		IdentificationHash:   hashOf(iban),
+		IdentificationHashes: []string{hashOf(iban)},
+	}
+}
+
+// Balances generates closing booked and interim available balances of account, with the booked
+// balance being balanceCents in minor units.
+func (g *Generator) Balances(account *enablebankinggo.AccountResource, balanceCents int64, at time.Time) []*enablebankinggo.BalanceResource {
+	pendingCents := g.rnd.Int64N(5000)
+	lastChange := at.UTC().Truncate(time.Second)
+
+	return []*enablebankinggo.BalanceResource{
+		{
+			Name:               "Booked balance",
+			BalanceAmmount:     amountOf(balanceCents, account.Currency),
+			BalanceType:        enablebankinggo.ClosingBookedBalanceType,
+			LastChangeDateTime: &lastChange,
+			ReferenceDate:      at.Format(time.DateOnly),
+		},
+		{
+			Name:               "Available balance",
+			BalanceAmmount:     amountOf(balanceCents-pendingCents, account.Currency),
+			BalanceType:        enablebankinggo.InterimAvailableBalanceType,
+			LastChangeDateTime: &lastChange,
+			ReferenceDate:      at.Format(time.DateOnly),
+		},
+	}
+}
+
+// Transactions generates count booked transactions of account, ordered by booking date descending and
+// ending at until, with the balance after the latest transaction being balanceCents in minor units.
+// Transactions are mostly card purchases with plausible amounts and merchant category codes, and a
+// monthly salary.
+func (g *Generator) Transactions(account *enablebankinggo.AccountResource, count int, until time.Time, balanceCents int64) []*enablebankinggo.Transaction {
+	transactions := make([]*enablebankinggo.Transaction, 0, count)
+	date := until
+	balance := balanceCents
+	lastSalaryMonth := time.Month(0)
+
+	for range count {
+		var tx *enablebankinggo.Transaction
+		var cents int64
+
+		if date.Day() <= 25 && date.Month() != lastSalaryMonth && g.rnd.IntN(4) == 0 {
+			lastSalaryMonth = date.Month()
+			cents = 250000 + g.rnd.Int64N(300000)
+			tx = g.salary(account, cents)
+		} else {
+			merchant := generatorMerchants[g.rnd.IntN(len(generatorMerchants))]
+			cents = merchant.minCents + g.rnd.Int64N(merchant.maxCents-merchant.minCents+1)
+			tx = g.purchase(account, merchant, cents)
+			cents = -cents
+		}
+
+		day := date.Format(time.DateOnly)
+		tx.Status = enablebankinggo.AccountedTransactionStatus
+		tx.BookingDate = day
+		tx.ValueDate = day
+		tx.TransactionDate = day
+		tx.BalanceAfterTransaction = amountOf(balance, account.Currency)
+		tx.EntryReference = strconv.FormatUint(g.rnd.Uint64()%1e12, 10)
+		tx.TransactionID = g.uuid()
+		transactions = append(transactions, tx)
+
+		balance -= cents
+		date = date.Add(-time.Duration(g.rnd.IntN(36)) * time.Hour)
+	}
+
+	return transactions
+}
+
+// AccountFixture generates an account fixture for [Server.AddAccount], with balances and
+// transactionCount transactions ending at until.
+func (g *Generator) AccountFixture(transactionCount int, until time.Time) *Account {
+	account := g.Account()
+	balanceCents := 10000 + g.rnd.Int64N(1000000)
+
+	return &Account{
+		Details:      account,
+		Balances:     g.Balances(account, balanceCents, until),
+		Transactions: g.Transactions(account, transactionCount, until, balanceCents),
+	}
+}
+
+func (g *Generator) purchase(account *enablebankinggo.AccountResource, merchant generatorMerchant, cents int64) *enablebankinggo.Transaction {
+	return &enablebankinggo.Transaction{
+		MerchantCategoryCode: merchant.mcc,
+		TransactionAmount:    amountOf(cents, account.Currency),
+		Creditor:             &enablebankinggo.PartyIdentification{Name: merchant.name},
+		DebtorAccount:        account.AccountID,
+		BankTransactionCode: &enablebankinggo.BankTransactionCode{
+			Description: "Card payment",
+			Code:        "CCRD",
+			SubCode:     "POSD",
+		},
+		CreditDebitIndicator:  enablebankinggo.DebitCreditDebitIndicator,
+		RemittanceInformation: []string{strings.ToUpper(merchant.name)},
+	}
+}
+
+func (g *Generator) salary(account *enablebankinggo.AccountResource, cents int64) *enablebankinggo.Transaction {
+	employer := g.pick([]string{"Acme Oy", "Globex AB", "Initech AS", "Umbrella GmbH", "Hooli BV"})
+
+	return &enablebankinggo.Transaction{
+		TransactionAmount: amountOf(cents, account.Currency),
+		Debtor:            &enablebankinggo.PartyIdentification{Name: employer},
+		DebtorAccount:     &enablebankinggo.AccountIdentification{IBAN: g.IBAN()},
+		CreditorAccount:   account.AccountID,
+		BankTransactionCode: &enablebankinggo.BankTransactionCode{
+			Description: "Salary",
+			Code:        "RCDT",
+			SubCode:     "SALA",
+		},
+		CreditDebitIndicator:  enablebankinggo.CreditCreditDebitIndicator,
+		RemittanceInformation: []string{"SALARY"},
+	}
+}
+
+func (g *Generator) bank() generatorBank {
+	return g.loc.banks[g.rnd.IntN(len(g.loc.banks))]
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rnd.IntN(len(values))]
+}
+
+func (g *Generator) iban(bank generatorBank) string {
+	var bban strings.Builder
+	bban.WriteString(bank.code)
+	for _, c := range g.loc.bbanFormat[len(bank.code):] {
+		if c == 'A' {
+			bban.WriteByte(byte('A' + g.rnd.IntN(26)))
+		} else {
+			bban.WriteByte(byte('0' + g.rnd.IntN(10)))
+		}
+	}
+
+	return g.locale + ibanCheckDigits(g.locale, bban.String()) + bban.String()
+}
+
+func (g *Generator) uuid() string {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = byte(g.rnd.UintN(256))
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// ibanCheckDigits calculates the ISO 13616 check digits of an IBAN.
+func ibanCheckDigits(country, bban string) string {
+	var digits strings.Builder
+	for _, c := range bban + country + "00" {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	checksum := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+
+	return fmt.Sprintf("%02d", checksum)
+}
+
+func amountOf(cents int64, currency string) *enablebankinggo.AmountType {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return &enablebankinggo.AmountType{
+		Amount:   fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100),
+		Currency: currency,
+	}
+}
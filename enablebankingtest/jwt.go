@@ -0,0 +1,194 @@
+package enablebankingtest
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// JWTIssuer is the issuer claim of the JWTs generated by the client.
+	JWTIssuer = "enablebanking.com"
+
+	// JWTAudience is the audience claim of the JWTs generated by the client.
+	JWTAudience = "api.enablebanking.com"
+
+	// MaxJWTTTL is the maximum lifetime of a JWT accepted by the Enable Banking API.
+	MaxJWTTTL = 24 * time.Hour
+)
+
+// JWTHeader represents the header of a JWT generated by the client.
+type JWTHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// JWTClaims represents the claims of a JWT generated by the client.
+type JWTClaims struct {
+	Iss string `json:"iss"`
+	Aud string `json:"aud"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// IssuedAt returns the time the JWT was issued.
+func (c *JWTClaims) IssuedAt() time.Time {
+	return time.Unix(c.Iat, 0)
+}
+
+// ExpiresAt returns the time the JWT expires.
+func (c *JWTClaims) ExpiresAt() time.Time {
+	return time.Unix(c.Exp, 0)
+}
+
+// JWT represents a parsed JWT generated by the client.
+type JWT struct {
+	Header    *JWTHeader
+	Claims    *JWTClaims
+	Signature []byte
+
+	// signingInput is the encoded header and claims the signature is calculated from.
+	signingInput string
+}
+
+// ParseJWT parses a JWT without verifying it.
+func ParseJWT(token string) (*JWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("JWT must consist of 3 parts")
+	}
+
+	var header JWTHeader
+	err := decodeJWTPart(parts[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT header: %w", err)
+	}
+
+	var claims JWTClaims
+	err = decodeJWTPart(parts[1], &claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT claims: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT signature: %w", err)
+	}
+
+	return &JWT{
+		Header:       &header,
+		Claims:       &claims,
+		Signature:    signature,
+		signingInput: parts[0] + "." + parts[1],
+	}, nil
+}
+
+// VerifySignature verifies the RS256 signature of the JWT using the provided public key.
+func (t *JWT) VerifySignature(publicKey *rsa.PublicKey) error {
+	if publicKey == nil {
+		return errors.New("publicKey cannot be nil")
+	}
+
+	if t.Header.Alg != "RS256" {
+		return fmt.Errorf("unexpected JWT algorithm %q", t.Header.Alg)
+	}
+
+	hashed := sha256.Sum256([]byte(t.signingInput))
+	err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], t.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid JWT signature: %w", err)
+	}
+
+	return nil
+}
+
+// Verify verifies the header, claims and signature of the JWT, i.e. that it's signed by the provided
+// public key, issued for applicationID and valid at now, the same way the Enable Banking API does.
+func (t *JWT) Verify(publicKey *rsa.PublicKey, applicationID string, now time.Time) error {
+	if t.Header.Typ != "JWT" {
+		return fmt.Errorf("unexpected JWT type %q", t.Header.Typ)
+	}
+
+	if applicationID != "" && t.Header.Kid != applicationID {
+		return fmt.Errorf("unexpected JWT kid %q, expected %q", t.Header.Kid, applicationID)
+	}
+
+	if t.Claims.Iss != JWTIssuer {
+		return fmt.Errorf("unexpected JWT issuer %q", t.Claims.Iss)
+	}
+
+	if t.Claims.Aud != JWTAudience {
+		return fmt.Errorf("unexpected JWT audience %q", t.Claims.Aud)
+	}
+
+	if t.Claims.Exp <= t.Claims.Iat {
+		return errors.New("JWT expires before it's issued")
+	}
+
+	if t.ExpiresAt().Sub(t.IssuedAt()) > MaxJWTTTL {
+		return fmt.Errorf("JWT lifetime exceeds %s", MaxJWTTTL)
+	}
+
+	if now.Before(t.IssuedAt()) {
+		return errors.New("JWT issued in the future")
+	}
+
+	if !now.Before(t.ExpiresAt()) {
+		return errors.New("JWT expired")
+	}
+
+	return t.VerifySignature(publicKey)
+}
+
+// IssuedAt returns the time the JWT was issued.
+func (t *JWT) IssuedAt() time.Time {
+	return t.Claims.IssuedAt()
+}
+
+// ExpiresAt returns the time the JWT expires.
+func (t *JWT) ExpiresAt() time.Time {
+	return t.Claims.ExpiresAt()
+}
+
+// VerifyJWT parses and verifies a JWT, see [JWT.Verify].
+func VerifyJWT(token string, publicKey *rsa.PublicKey, applicationID string) (*JWT, error) {
+	t, err := ParseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Verify(publicKey, applicationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// VerifyAuthorizationHeader parses and verifies the bearer JWT of the Authorization header of req, see
+// [JWT.Verify].
+func VerifyAuthorizationHeader(req *http.Request, publicKey *rsa.PublicKey, applicationID string) (*JWT, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, errors.New("missing bearer token in Authorization header")
+	}
+
+	return VerifyJWT(token, publicKey, applicationID)
+}
+
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,290 @@
+package enablebankingtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// MockASPSPName is the name of the Mock ASPSP available in the Enable Banking sandbox.
+	MockASPSPName = "Mock ASPSP"
+
+	// MockASPSPCountry is the country of the Mock ASPSP available in the Enable Banking sandbox.
+	MockASPSPCountry = "FI"
+
+	// DefaultMockASPSPMaxSteps is the default maximum number of pages visited while authorizing.
+	DefaultMockASPSPMaxSteps = 20
+)
+
+var (
+	formRegexp      = regexp.MustCompile(`(?is)<form\b([^>]*)>(.*?)</form>`)
+	inputRegexp     = regexp.MustCompile(`(?is)<(input|button)\b([^>]*)>`)
+	attributeRegexp = regexp.MustCompile(`(?is)([a-z_:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// MockASPSPAuthorizer completes the authorization of the Mock ASPSP in the Enable Banking sandbox
+// without user interaction, by following redirects and submitting the sandbox forms, allowing end-to-end
+// AIS tests to run unattended, e.g. in CI.
+//
+// Every form is submitted with its default values, i.e. hidden inputs, checked checkboxes and the first
+// submit button, overridden by Fields. Authorization completes once redirected to the redirect URL.
+type MockASPSPAuthorizer struct {
+	// HTTPClient is the client used for browsing the sandbox. A cookie jar is added if missing. Defaults
+	// to a new client.
+	HTTPClient *http.Client
+
+	// Fields is the form field values by input name, e.g. the sandbox credentials.
+	Fields map[string]string
+
+	// MaxSteps is the maximum number of pages visited. Defaults to DefaultMockASPSPMaxSteps.
+	MaxSteps int
+}
+
+// AuthorizeMockASPSP starts an authorization for the Mock ASPSP, completes it using authorizer and
+// authorizes the session. If req is nil, an authorization for the Mock ASPSP with balances and
+// transactions access valid for one day is started. If authorizer is nil, a default authorizer is used.
+func AuthorizeMockASPSP(ctx context.Context, client enablebankinggo.UserSessionsClient, authorizer *MockASPSPAuthorizer, req *enablebankinggo.StartAuthorizationRequest) (*enablebankinggo.AuthorizeSessionResponse, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	if authorizer == nil {
+		authorizer = &MockASPSPAuthorizer{}
+	}
+
+	if req == nil {
+		req = &enablebankinggo.StartAuthorizationRequest{
+			Access: &enablebankinggo.Access{
+				Balances:     true,
+				Transactions: true,
+				ValidUntil:   time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			},
+			ASPSP: enablebankinggo.ASPSP{
+				Name:    MockASPSPName,
+				Country: MockASPSPCountry,
+			},
+			State:       randomID(),
+			RedirectURL: "https://localhost/callback",
+			PSUType:     enablebankinggo.PersonalPSUType,
+		}
+	}
+
+	authResp, err := client.StartAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := authorizer.Authorize(ctx, authResp.URL, req.RedirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AuthorizeSession(ctx, &enablebankinggo.AuthorizeSessionRequest{Code: code})
+}
+
+// Authorize browses from authURL, i.e. the URL returned by StartAuthorization, until redirected to
+// redirectURL and returns the authorization code.
+func (a *MockASPSPAuthorizer) Authorize(ctx context.Context, authURL, redirectURL string) (string, error) {
+	redirect, err := a.browse(ctx, authURL, redirectURL)
+	if err != nil {
+		return "", err
+	}
+
+	return authorizationCode(redirect)
+}
+
+// browse browses from authURL until redirected to redirectURL and returns the redirect URL.
+func (a *MockASPSPAuthorizer) browse(ctx context.Context, authURL, redirectURL string) (*url.URL, error) {
+	if authURL == "" {
+		return nil, errors.New("authURL cannot be empty")
+	}
+
+	if redirectURL == "" {
+		return nil, errors.New("redirectURL cannot be empty")
+	}
+
+	client, err := a.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	maxSteps := a.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMockASPSPMaxSteps
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for range maxSteps {
+		if strings.HasPrefix(req.URL.String(), redirectURL) {
+			return req.URL, nil
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest {
+			location, err := resp.Location()
+			if err != nil {
+				return nil, err
+			}
+
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, req.URL)
+		}
+
+		req, err = a.nextFormRequest(ctx, req.URL, string(body))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("authorization not completed within %d steps", maxSteps)
+}
+
+func (a *MockASPSPAuthorizer) httpClient() (*http.Client, error) {
+	client := &http.Client{}
+	if a.HTTPClient != nil {
+		c := *a.HTTPClient
+		client = &c
+	}
+
+	if client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		client.Jar = jar
+	}
+
+	// Redirects are followed manually, since the redirect URL usually isn't reachable.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return client, nil
+}
+
+// nextFormRequest creates the request submitting the first form of page.
+func (a *MockASPSPAuthorizer) nextFormRequest(ctx context.Context, pageURL *url.URL, page string) (*http.Request, error) {
+	form := formRegexp.FindStringSubmatch(page)
+	if form == nil {
+		return nil, fmt.Errorf("no form found at %s", pageURL)
+	}
+
+	formAttrs := parseAttributes(form[1])
+	action, err := pageURL.Parse(formAttrs["action"])
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	submitted := false
+	for _, input := range inputRegexp.FindAllStringSubmatch(form[2], -1) {
+		attrs := parseAttributes(input[2])
+		name := attrs["name"]
+		if name == "" {
+			continue
+		}
+
+		inputType := strings.ToLower(attrs["type"])
+		if input[1] == "button" && inputType == "" {
+			inputType = "submit"
+		}
+
+		switch inputType {
+		case "submit":
+			if !submitted {
+				values.Set(name, attrs["value"])
+				submitted = true
+			}
+		case "checkbox", "radio":
+			if _, checked := attrs["checked"]; checked && !values.Has(name) {
+				values.Set(name, attrs["value"])
+			}
+		default:
+			values.Set(name, attrs["value"])
+		}
+	}
+
+	for name, value := range a.Fields {
+		values.Set(name, value)
+	}
+
+	if strings.EqualFold(formAttrs["method"], http.MethodPost) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.String(), strings.NewReader(values.Encode()))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return req, nil
+	}
+
+	action.RawQuery = values.Encode()
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, action.String(), nil)
+}
+
+func parseAttributes(s string) map[string]string {
+	attrs := map[string]string{}
+	for _, match := range attributeRegexp.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(match[1])] = html.UnescapeString(match[2] + match[3] + match[4])
+	}
+
+	// Boolean attributes without value, e.g. checked.
+	for _, field := range strings.Fields(attributeRegexp.ReplaceAllString(s, "")) {
+		field = strings.ToLower(strings.Trim(field, "/"))
+		if field != "" {
+			attrs[field] = ""
+		}
+	}
+
+	return attrs
+}
+
+func authorizationCode(u *url.URL) (string, error) {
+	query := u.Query()
+	if errorCode := query.Get("error"); errorCode != "" {
+		return "", fmt.Errorf("authorization failed: %s: %s", errorCode, query.Get("error_description"))
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("redirect URL is missing the authorization code")
+	}
+
+	return code, nil
+}
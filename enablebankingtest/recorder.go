@@ -0,0 +1,374 @@
+package enablebankingtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RedactedValue is the value replacing redacted secrets in cassettes.
+const RedactedValue = "REDACTED"
+
+// RecorderMode represents the mode of a [Recorder].
+type RecorderMode int
+
+const (
+	// ReplayMode replays interactions from an existing cassette and fails requests not found in it.
+	ReplayMode RecorderMode = iota
+
+	// RecordMode sends requests to the API and records the interactions, overwriting any existing cassette.
+	RecordMode
+
+	// ReplayOrRecordMode replays interactions if the cassette exists, otherwise records them.
+	ReplayOrRecordMode
+)
+
+// DefaultRedactedHeaders is the request and response headers redacted by default.
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Psu-Ip-Address"}
+
+// DefaultRedactedJSONFields is the JSON fields of request and response bodies redacted by default.
+var DefaultRedactedJSONFields = []string{"code", "iban", "bban", "access_token", "refresh_token", "private_key"}
+
+// Cassette represents recorded API interactions.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction represents a recorded request and its response.
+type Interaction struct {
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+// RecordedRequest represents a recorded request.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse represents a recorded response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// RequestMatcher reports whether a request matches a recorded request.
+type RequestMatcher func(req *http.Request, recorded *RecordedRequest) bool
+
+// RecorderOption represents an option for configuring a [Recorder].
+type RecorderOption func(*Recorder)
+
+// WithRecorderTransport sets the transport used for sending requests in record mode. Defaults to
+// [http.DefaultTransport].
+func WithRecorderTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithRedactedHeaders sets the headers to redact, replacing [DefaultRedactedHeaders].
+func WithRedactedHeaders(headers ...string) RecorderOption {
+	return func(r *Recorder) {
+		r.redactedHeaders = headers
+	}
+}
+
+// WithRedactedQueryParams sets the query parameters to redact. None are redacted by default.
+func WithRedactedQueryParams(params ...string) RecorderOption {
+	return func(r *Recorder) {
+		r.redactedQueryParams = params
+	}
+}
+
+// WithRedactedJSONFields sets the JSON fields to redact, at any depth, replacing [DefaultRedactedJSONFields].
+func WithRedactedJSONFields(fields ...string) RecorderOption {
+	return func(r *Recorder) {
+		r.redactedJSONFields = fields
+	}
+}
+
+// WithRequestMatcher sets the matcher used for finding the recorded interaction of a request in replay
+// mode. Defaults to matching method and (redacted) URL.
+func WithRequestMatcher(matcher RequestMatcher) RecorderOption {
+	return func(r *Recorder) {
+		r.matcher = matcher
+	}
+}
+
+// Recorder is a [http.RoundTripper] recording real API interactions to a cassette file, with secrets
+// redacted, and replaying them, e.g. in CI, allowing tests against realistic ASPSP responses without
+// live credentials.
+//
+// Recorded interactions are replayed in order, each at most once. Call [Recorder.Stop] to save the
+// cassette after recording.
+//
+//	recorder, err := enablebankingtest.NewRecorder("testdata/nordea.json", enablebankingtest.ReplayOrRecordMode)
+//	...
+//	defer recorder.Stop()
+//	client, err := enablebankinggo.NewClient(appID, privateKey, enablebankinggo.WithHTTPTransport(recorder))
+type Recorder struct {
+	path                string
+	mode                RecorderMode
+	transport           http.RoundTripper
+	redactedHeaders     []string
+	redactedQueryParams []string
+	redactedJSONFields  []string
+	matcher             RequestMatcher
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewRecorder creates a new recorder using the cassette file at path in the provided mode.
+func NewRecorder(path string, mode RecorderMode, options ...RecorderOption) (*Recorder, error) {
+	if path == "" {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	r := &Recorder{
+		path:               path,
+		mode:               mode,
+		transport:          http.DefaultTransport,
+		redactedHeaders:    DefaultRedactedHeaders,
+		redactedJSONFields: DefaultRedactedJSONFields,
+		cassette:           &Cassette{},
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	if r.matcher == nil {
+		r.matcher = r.matchMethodAndURL
+	}
+
+	if r.mode == ReplayOrRecordMode {
+		r.mode = RecordMode
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ReplayMode
+		}
+	}
+
+	if r.mode == ReplayMode {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+
+		err = json.Unmarshal(b, r.cassette)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode cassette: %w", err)
+		}
+
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode of the recorder, i.e. [RecordMode] or [ReplayMode].
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ReplayMode {
+		return r.replay(req)
+	}
+
+	return r.record(req)
+}
+
+// Stop saves the cassette when recording. It's a no-op when replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != RecordMode {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(r.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, b, 0o644)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: &RecordedRequest{
+			Method:  req.Method,
+			URL:     r.redactURL(req.URL),
+			Headers: r.redactHeaders(req.Header),
+			Body:    r.redactBody(reqBody),
+		},
+		Response: &RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redactHeaders(resp.Header),
+			Body:       r.redactBody(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matcher(req, interaction.Request) {
+			continue
+		}
+
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s in cassette %s", req.Method, r.redactURL(req.URL), r.path)
+}
+
+func (r *Recorder) matchMethodAndURL(req *http.Request, recorded *RecordedRequest) bool {
+	return req.Method == recorded.Method && r.redactURL(req.URL) == recorded.URL
+}
+
+func (r *Recorder) redactURL(u *url.URL) string {
+	if len(r.redactedQueryParams) == 0 || u.RawQuery == "" {
+		return u.String()
+	}
+
+	redacted := *u
+	query := redacted.Query()
+	for _, param := range r.redactedQueryParams {
+		if query.Has(param) {
+			query.Set(param, RedactedValue)
+		}
+	}
+
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+func (r *Recorder) redactHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	redacted := headers.Clone()
+	for _, header := range r.redactedHeaders {
+		if redacted.Get(header) != "" {
+			redacted.Set(header, RedactedValue)
+		}
+	}
+
+	return redacted
+}
+
+// redactBody redacts the configured fields of JSON bodies. Other bodies are returned as is.
+func (r *Recorder) redactBody(body []byte) string {
+	if len(body) == 0 || len(r.redactedJSONFields) == 0 {
+		return string(body)
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	if !r.redactJSONValue(v) {
+		return string(body)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+// redactJSONValue redacts the configured fields of v in place and reports whether anything was redacted.
+func (r *Recorder) redactJSONValue(v any) bool {
+	redacted := false
+
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if value != nil && slices.Contains(r.redactedJSONFields, key) {
+				v[key] = RedactedValue
+				redacted = true
+				continue
+			}
+
+			redacted = r.redactJSONValue(value) || redacted
+		}
+	case []any:
+		for _, value := range v {
+			redacted = r.redactJSONValue(value) || redacted
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,128 @@
+package enablebankingtest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// SandboxCreditorName is the name of the canned sandbox creditors.
+	SandboxCreditorName = "Sandbox Creditor"
+
+	// SandboxRedirectURL is the redirect URL used by the sandbox helpers unless set.
+	SandboxRedirectURL = "https://localhost/callback"
+)
+
+// SandboxCreditorIBANs is the IBANs, with valid check digits, of the canned sandbox creditor accounts,
+// by country.
+var SandboxCreditorIBANs = map[string]string{
+	"DE": "DE89370400440532013000",
+	"FI": "FI2112345600000785",
+	"GB": "GB29NWBK60161331926819",
+	"SE": "SE4550000000058398257466",
+}
+
+// SandboxCreditor returns a canned sandbox creditor account of country, or nil if there's none.
+func SandboxCreditor(country string) *enablebankinggo.Beneficiary {
+	iban, ok := SandboxCreditorIBANs[country]
+	if !ok {
+		return nil
+	}
+
+	return &enablebankinggo.Beneficiary{
+		Creditor:        &enablebankinggo.PartyIdentification{Name: SandboxCreditorName},
+		CreditorAccount: enablebankinggo.NewIBANAccountIdentification(iban),
+	}
+}
+
+// NewSandboxPayment returns a request for a SEPA payment of amount euros from the Mock ASPSP to the
+// Finnish sandbox creditor.
+func NewSandboxPayment(amount string) *enablebankinggo.CreatePaymentRequest {
+	return &enablebankinggo.CreatePaymentRequest{
+		PaymentType: enablebankinggo.SepaPaymentType,
+		PaymentRequest: &enablebankinggo.PaymentRequestResource{
+			CreditTransferTransaction: []*enablebankinggo.CreditTransferTransaction{{
+				InstructedAmount:      &enablebankinggo.AmountType{Currency: "EUR", Amount: amount},
+				Beneficiary:           SandboxCreditor(MockASPSPCountry),
+				RemittanceInformation: []string{"Sandbox payment"},
+			}},
+		},
+		ASPSP: enablebankinggo.ASPSP{
+			Name:    MockASPSPName,
+			Country: MockASPSPCountry,
+		},
+		RedirectURL: SandboxRedirectURL,
+		PSUType:     enablebankinggo.PersonalPSUType,
+	}
+}
+
+// AuthorizePayment browses from authURL, i.e. the URL returned by CreatePayment, until redirected to
+// redirectURL and returns the query parameters of the redirect, see
+// [enablebankinggo.ParsePaymentCallback].
+func (a *MockASPSPAuthorizer) AuthorizePayment(ctx context.Context, authURL, redirectURL string) (url.Values, error) {
+	redirect, err := a.browse(ctx, authURL, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return redirect.Query(), nil
+}
+
+// AuthorizeMockASPSPPayment creates a payment, completes its authorization using authorizer and waits
+// until the payment is accepted, rejected or cancelled. If req is nil, a payment of 1.00 EUR to the
+// Finnish sandbox creditor is created. If authorizer is nil, a default authorizer is used.
+func AuthorizeMockASPSPPayment(ctx context.Context, client enablebankinggo.PaymentsClient, authorizer *MockASPSPAuthorizer, req *enablebankinggo.CreatePaymentRequest) (*enablebankinggo.GetPaymentResponse, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	if authorizer == nil {
+		authorizer = &MockASPSPAuthorizer{}
+	}
+
+	if req == nil {
+		req = NewSandboxPayment("1.00")
+	}
+
+	flow := &enablebankinggo.PaymentAuthFlow{
+		Client:      client,
+		RedirectURL: SandboxRedirectURL,
+		Wait: &enablebankinggo.WaitForPaymentStatusParams{
+			Interval: time.Second,
+			Until:    sandboxPaymentDone,
+		},
+	}
+
+	auth, err := flow.Start(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectURL := req.RedirectURL
+	if redirectURL == "" {
+		redirectURL = flow.RedirectURL
+	}
+
+	query, err := authorizer.AuthorizePayment(ctx, auth.URL, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return flow.Complete(ctx, auth, query)
+}
+
+// sandboxPaymentDone returns whether the sandbox payment is no longer awaiting processing, since the
+// sandbox may not settle payments.
+func sandboxPaymentDone(status enablebankinggo.PaymentStatus) bool {
+	switch status {
+	case enablebankinggo.ReceivedPaymentStatus, enablebankinggo.PendingPaymentStatus,
+		enablebankinggo.PartiallyAcceptedTechnicalCorrectPaymentStatus, "":
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,589 @@
+// Package enablebankingtest provides utilities for testing code built on the enablebankinggo package,
+// such as a fake Enable Banking API server, allowing integration tests to run offline and deterministically.
+package enablebankingtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// DefaultTransactionsPageSize is the default number of transactions returned per page.
+const DefaultTransactionsPageSize = 50
+
+// Account represents an account fixture served by the fake server.
+type Account struct {
+	// Details is the account details returned by GET /accounts/{account_id}/details.
+	Details *enablebankinggo.AccountResource
+
+	// Balances is the balances returned by GET /accounts/{account_id}/balances.
+	Balances []*enablebankinggo.BalanceResource
+
+	// Transactions is the transactions returned by GET /accounts/{account_id}/transactions.
+	Transactions []*enablebankinggo.Transaction
+}
+
+// InjectedError represents an error returned by the fake server for matching requests.
+type InjectedError struct {
+	// Method is the HTTP method to match. Matches any method if empty.
+	Method string
+
+	// PathPrefix is the URL path prefix to match, e.g. /accounts/. Matches any path if empty.
+	PathPrefix string
+
+	// StatusCode is the HTTP status code of the error response.
+	StatusCode int
+
+	// Response is the error response body.
+	Response *enablebankinggo.ErrorResponse
+
+	// Times is the number of matching requests to fail. Fails all matching requests if zero.
+	Times int
+}
+
+type session struct {
+	response *enablebankinggo.GetSessionResponse
+}
+
+type authorization struct {
+	request *enablebankinggo.StartAuthorizationRequest
+}
+
+// Server is a fake Enable Banking API server implementing the /application, /aspsps, /auth, /sessions
+// and /accounts endpoints with configurable fixtures, latency and error injection.
+//
+// Authorizations started using POST /auth are approved automatically, i.e. the returned URL points
+// directly at the redirect URL with an authorization code and the state.
+type Server struct {
+	*httptest.Server
+
+	mu                   sync.Mutex
+	latency              time.Duration
+	transactionsPageSize int
+	application          *enablebankinggo.GetApplicationResponse
+	aspsps               []*enablebankinggo.ASPSPData
+	accounts             map[string]*Account
+	accountOrder         []string
+	authorizations       map[string]*authorization
+	sessions             map[string]*session
+	injectedErrors       []*InjectedError
+}
+
+// NewServer starts and returns a new fake Enable Banking API server. The caller should call Close when
+// finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		transactionsPageSize: DefaultTransactionsPageSize,
+		application: &enablebankinggo.GetApplicationResponse{
+			Name:        "enablebankingtest",
+			KID:         "enablebankingtest",
+			Environment: enablebankinggo.SandboxEnvironment,
+			Active:      true,
+			Services:    []enablebankinggo.Service{enablebankinggo.AccountInformationService},
+		},
+		accounts:       map[string]*Account{},
+		authorizations: map[string]*authorization{},
+		sessions:       map[string]*session{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /application", s.handleGetApplication)
+	mux.HandleFunc("GET /aspsps", s.handleGetASPSPs)
+	mux.HandleFunc("POST /auth", s.handleStartAuthorization)
+	mux.HandleFunc("POST /sessions", s.handleAuthorizeSession)
+	mux.HandleFunc("GET /sessions/{session_id}", s.handleGetSession)
+	mux.HandleFunc("DELETE /sessions/{session_id}", s.handleDeleteSession)
+	mux.HandleFunc("GET /accounts/{account_id}/details", s.handleGetAccountDetails)
+	mux.HandleFunc("GET /accounts/{account_id}/balances", s.handleGetAccountBalances)
+	mux.HandleFunc("GET /accounts/{account_id}/transactions", s.handleGetAccountTransactions)
+	mux.HandleFunc("GET /accounts/{account_id}/transactions/{transaction_id}", s.handleGetTransactionDetails)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// APIClient creates a new Enable Banking API client targeting the fake server, using a newly generated
+// private key.
+func (s *Server) APIClient(options ...enablebankinggo.ClientOption) (*enablebankinggo.APIClient, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []enablebankinggo.ClientOption{
+		enablebankinggo.WithBaseURL(s.URL),
+		enablebankinggo.WithHTTPClient(s.Client()),
+	}
+
+	return enablebankinggo.NewClient(s.application.KID, privateKey, append(opts, options...)...)
+}
+
+// SetLatency sets the latency added to every response.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// SetTransactionsPageSize sets the number of transactions returned per page.
+func (s *Server) SetTransactionsPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transactionsPageSize = pageSize
+}
+
+// SetApplication sets the application returned by GET /application.
+func (s *Server) SetApplication(app *enablebankinggo.GetApplicationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.application = app
+}
+
+// AddASPSP adds an ASPSP returned by GET /aspsps.
+func (s *Server) AddASPSP(aspsp *enablebankinggo.ASPSPData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.aspsps = append(s.aspsps, aspsp)
+}
+
+// AddAccount adds an account identified by the provided UID. Accounts are included in every session
+// authorized after the account has been added.
+func (s *Server) AddAccount(uid string, account *Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if account.Details == nil {
+		account.Details = &enablebankinggo.AccountResource{}
+	}
+	account.Details.UID = uid
+
+	if _, ok := s.accounts[uid]; !ok {
+		s.accountOrder = append(s.accountOrder, uid)
+	}
+	s.accounts[uid] = account
+}
+
+// InjectError makes the fake server respond with an error to matching requests.
+func (s *Server) InjectError(injectedError *InjectedError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injectedErrors = append(s.injectedErrors, injectedError)
+}
+
+// ClearInjectedErrors removes all injected errors.
+func (s *Server) ClearInjectedErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injectedErrors = nil
+}
+
+// SetSessionStatus sets the status of a session, e.g. to simulate expired or revoked sessions.
+func (s *Server) SetSessionStatus(sessionID string, status enablebankinggo.SessionStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[sessionID]
+	if ok {
+		sess.response.Status = status
+	}
+
+	return ok
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		latency := s.latency
+		injectedError := s.matchInjectedErrorLocked(r)
+		s.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeError(w, http.StatusUnauthorized, enablebankinggo.AuthorizationNotProvidedErrorCode, "Authorization header is not provided")
+			return
+		}
+
+		if injectedError != nil {
+			resp := injectedError.Response
+			if resp == nil {
+				resp = &enablebankinggo.ErrorResponse{Message: http.StatusText(injectedError.StatusCode), Code: injectedError.StatusCode}
+			}
+			writeJSON(w, injectedError.StatusCode, resp)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) matchInjectedErrorLocked(r *http.Request) *InjectedError {
+	for i, injectedError := range s.injectedErrors {
+		if injectedError.Method != "" && injectedError.Method != r.Method {
+			continue
+		}
+
+		if !strings.HasPrefix(r.URL.Path, injectedError.PathPrefix) {
+			continue
+		}
+
+		if injectedError.Times > 0 {
+			injectedError.Times--
+			if injectedError.Times == 0 {
+				s.injectedErrors = append(s.injectedErrors[:i], s.injectedErrors[i+1:]...)
+			}
+		}
+
+		return injectedError
+	}
+
+	return nil
+}
+
+func (s *Server) handleGetApplication(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.application)
+}
+
+func (s *Server) handleGetASPSPs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	country := r.URL.Query().Get("country")
+	psuType := enablebankinggo.PSUType(r.URL.Query().Get("psu_type"))
+
+	resp := &enablebankinggo.GetASPSPsResponse{ASPSPs: []*enablebankinggo.ASPSPData{}}
+	for _, aspsp := range s.aspsps {
+		if country != "" && aspsp.Country != country {
+			continue
+		}
+
+		if psuType != "" && len(aspsp.PSUTypes) > 0 && !slices.Contains(aspsp.PSUTypes, psuType) {
+			continue
+		}
+
+		resp.ASPSPs = append(resp.ASPSPs, aspsp)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleStartAuthorization(w http.ResponseWriter, r *http.Request) {
+	var req enablebankinggo.StartAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, enablebankinggo.WrongRequestParametersErrorCode, "Invalid request body")
+		return
+	}
+
+	redirectURL, err := url.Parse(req.RedirectURL)
+	if err != nil || req.RedirectURL == "" {
+		writeError(w, http.StatusBadRequest, enablebankinggo.RedirectURINotAllowedErrorCode, "Redirect URI not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	authorizationID := randomID()
+	s.authorizations[authorizationID] = &authorization{request: &req}
+	s.mu.Unlock()
+
+	query := redirectURL.Query()
+	query.Set("code", authorizationID)
+	query.Set("state", req.State)
+	redirectURL.RawQuery = query.Encode()
+
+	writeJSON(w, http.StatusOK, &enablebankinggo.StartAuthorizationResponse{
+		URL:             redirectURL.String(),
+		AuthorizationID: authorizationID,
+		PSUIDHash:       hashOf(req.PSUID),
+	})
+}
+
+func (s *Server) handleAuthorizeSession(w http.ResponseWriter, r *http.Request) {
+	var req enablebankinggo.AuthorizeSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, enablebankinggo.WrongRequestParametersErrorCode, "Invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth, ok := s.authorizations[req.Code]
+	if !ok {
+		writeError(w, http.StatusBadRequest, enablebankinggo.WrongAuthorizationCodeErrorCode, "Wrong authorization code provided")
+		return
+	}
+	delete(s.authorizations, req.Code)
+
+	now := time.Now().UTC()
+	sessionID := randomID()
+	resp := &enablebankinggo.AuthorizeSessionResponse{
+		SessionID: sessionID,
+		Accounts:  []*enablebankinggo.AccountResource{},
+		ASPSP:     &auth.request.ASPSP,
+		PSUType:   auth.request.PSUType,
+		Access:    auth.request.Access,
+	}
+	sessionResp := &enablebankinggo.GetSessionResponse{
+		Status:       enablebankinggo.AuthorizedSessionStatus,
+		Accounts:     []string{},
+		AccountsData: []*enablebankinggo.SessionAccount{},
+		ASPSP:        &auth.request.ASPSP,
+		PSUType:      auth.request.PSUType,
+		PSUIDHash:    hashOf(auth.request.PSUID),
+		Access:       auth.request.Access,
+		Created:      now,
+		Authorized:   &now,
+	}
+
+	for _, uid := range s.accountOrder {
+		account := s.accounts[uid]
+		resp.Accounts = append(resp.Accounts, account.Details)
+		sessionResp.Accounts = append(sessionResp.Accounts, uid)
+		sessionResp.AccountsData = append(sessionResp.AccountsData, &enablebankinggo.SessionAccount{
+			UID:                  uid,
+			IdentificationHash:   account.Details.IdentificationHash,
+			IdentificationHashes: account.Details.IdentificationHashes,
+		})
+	}
+
+	s.sessions[sessionID] = &session{response: sessionResp}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[r.PathValue("session_id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, enablebankinggo.SessionDoesNotExistErrorCode, "No session found matching provided id")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sess.response)
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[r.PathValue("session_id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, enablebankinggo.SessionDoesNotExistErrorCode, "No session found matching provided id")
+		return
+	}
+
+	now := time.Now().UTC()
+	sess.response.Status = enablebankinggo.ClosedSessionStatus
+	sess.response.Closed = &now
+
+	writeJSON(w, http.StatusOK, &enablebankinggo.SuccessResponse{Message: "OK"})
+}
+
+// accountLocked returns the account with the provided UID if it belongs to an authorized session,
+// writing an error response otherwise.
+func (s *Server) accountLocked(w http.ResponseWriter, uid string) (*Account, bool) {
+	account, ok := s.accounts[uid]
+	if !ok {
+		writeError(w, http.StatusNotFound, enablebankinggo.AccountDoesNotExistErrorCode, "No account found matching provided id")
+		return nil, false
+	}
+
+	for _, sess := range s.sessions {
+		if !slices.Contains(sess.response.Accounts, uid) {
+			continue
+		}
+
+		switch sess.response.Status {
+		case enablebankinggo.AuthorizedSessionStatus:
+			return account, true
+		case enablebankinggo.ExpiredSessionStatus:
+			writeError(w, http.StatusUnauthorized, enablebankinggo.ExpiredSessionErrorCode, "Session is expired")
+			return nil, false
+		case enablebankinggo.RevokedSessionStatus:
+			writeError(w, http.StatusUnauthorized, enablebankinggo.RevokedSessionErrorCode, "Session is revoked")
+			return nil, false
+		case enablebankinggo.ClosedSessionStatus:
+			writeError(w, http.StatusUnauthorized, enablebankinggo.ClosedSessionErrorCode, "Session is closed")
+			return nil, false
+		}
+	}
+
+	writeError(w, http.StatusNotFound, enablebankinggo.AccountDoesNotExistErrorCode, "No account found matching provided id")
+	return nil, false
+}
+
+func (s *Server) handleGetAccountDetails(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accountLocked(w, r.PathValue("account_id"))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, account.Details)
+}
+
+func (s *Server) handleGetAccountBalances(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accountLocked(w, r.PathValue("account_id"))
+	if !ok {
+		return
+	}
+
+	balances := account.Balances
+	if balances == nil {
+		balances = []*enablebankinggo.BalanceResource{}
+	}
+
+	writeJSON(w, http.StatusOK, &enablebankinggo.HalBalances{Balances: balances})
+}
+
+func (s *Server) handleGetAccountTransactions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accountLocked(w, r.PathValue("account_id"))
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	dateFrom := query.Get("date_from")
+	dateTo := query.Get("date_to")
+	status := enablebankinggo.TransactionStatus(query.Get("transaction_status"))
+
+	if dateFrom == "" && dateTo != "" {
+		writeError(w, http.StatusUnprocessableEntity, enablebankinggo.DateToWithoutDateFromErrorCode, "date_from must be provided if date_to provided")
+		return
+	}
+
+	if dateFrom != "" && dateTo != "" && dateFrom > dateTo {
+		writeError(w, http.StatusUnprocessableEntity, enablebankinggo.WrongDateIntervalErrorCode, "date_from should be less than or equal date_to")
+		return
+	}
+
+	offset := 0
+	if continuationKey := query.Get("continuation_key"); continuationKey != "" {
+		var err error
+		offset, err = strconv.Atoi(continuationKey)
+		if err != nil || offset < 0 {
+			writeError(w, http.StatusUnprocessableEntity, enablebankinggo.WrongContinuationKeyErrorCode, "Wrong continuation key provided")
+			return
+		}
+	}
+
+	var filtered []*enablebankinggo.Transaction
+	for _, transaction := range account.Transactions {
+		date := transaction.BookingDate
+		if date == "" {
+			date = transaction.ValueDate
+		}
+
+		if dateFrom != "" && date != "" && date < dateFrom {
+			continue
+		}
+
+		if dateTo != "" && date != "" && date > dateTo {
+			continue
+		}
+
+		if status != "" && transaction.Status != status {
+			continue
+		}
+
+		filtered = append(filtered, transaction)
+	}
+
+	resp := &enablebankinggo.HalTransactions{Transactions: []*enablebankinggo.Transaction{}}
+	if offset < len(filtered) {
+		end := min(offset+s.transactionsPageSize, len(filtered))
+		resp.Transactions = filtered[offset:end]
+		if end < len(filtered) {
+			resp.ContinuationKey = strconv.Itoa(end)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetTransactionDetails(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accountLocked(w, r.PathValue("account_id"))
+	if !ok {
+		return
+	}
+
+	transactionID := r.PathValue("transaction_id")
+	for _, transaction := range account.Transactions {
+		if transaction.TransactionID == transactionID {
+			writeJSON(w, http.StatusOK, transaction)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, enablebankinggo.TransactionDoesNotExistErrorCode, "No transaction found matching provided id")
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, errorCode enablebankinggo.ErrorCode, message string) {
+	writeJSON(w, statusCode, &enablebankinggo.ErrorResponse{
+		Message:   message,
+		Code:      statusCode,
+		ErrorCode: errorCode,
+	})
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func hashOf(value string) string {
+	if value == "" {
+		return randomID()
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+package enablebankingtest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo/webhooks"
+)
+
+type webhookKey struct {
+	id         string
+	privateKey *ecdsa.PrivateKey
+}
+
+// WebhookSigner is a fake webhook sender, signing webhook events with locally generated ES256 keys and
+// serving the public keys as a JWKS, allowing receivers using [webhooks.Verifier] to be tested without
+// real deliveries.
+type WebhookSigner struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	now  func() time.Time
+	keys []*webhookKey
+	seq  int
+}
+
+// NewWebhookSigner starts and returns a new webhook signer with a newly generated key, serving the
+// JWKS at its URL. The caller should call Close when finished, to shut it down.
+func NewWebhookSigner() (*WebhookSigner, error) {
+	s := &WebhookSigner{now: time.Now}
+	if err := s.Rotate(); err != nil {
+		return nil, err
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.JWKS())
+	}))
+
+	return s, nil
+}
+
+// Verifier creates a new webhook verifier fetching the JWKS of the signer. The key set is refreshed on
+// every unknown key, unless config.MinRefreshInterval is set, so rotated keys are picked up immediately.
+func (s *WebhookSigner) Verifier(config webhooks.Config) (*webhooks.Verifier, error) {
+	config.JWKSURL = s.URL
+	config.HTTPClient = s.Client()
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = time.Nanosecond
+	}
+
+	return webhooks.NewVerifier(config)
+}
+
+// SetNow sets the function returning the time signatures are issued at, e.g. to test expired
+// signatures. Defaults to time.Now.
+func (s *WebhookSigner) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// Rotate generates a new key used for signing from now on, simulating key rollover. Previous keys are
+// still published in the JWKS, unless removed using RemovePreviousKeys.
+func (s *WebhookSigner) Rotate() error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = append(s.keys, &webhookKey{
+		id:         fmt.Sprintf("enablebankingtest-%d", len(s.keys)+1),
+		privateKey: privateKey,
+	})
+
+	return nil
+}
+
+// RemovePreviousKeys removes all keys but the current signing key from the JWKS.
+func (s *WebhookSigner) RemovePreviousKeys() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = s.keys[len(s.keys)-1:]
+}
+
+// KeyID returns the ID of the current signing key.
+func (s *WebhookSigner) KeyID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.keys[len(s.keys)-1].id
+}
+
+// JWKS returns the published key set.
+func (s *WebhookSigner) JWKS() *webhooks.JWKS {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jwks := &webhooks.JWKS{}
+	for _, key := range s.keys {
+		pub := key.privateKey.PublicKey
+		jwks.Keys = append(jwks.Keys, &webhooks.JWK{
+			Kty: "EC",
+			Kid: key.id,
+			Use: "sig",
+			Alg: "ES256",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+
+	return jwks
+}
+
+// Sign returns the detached compact JWS signature of body, signed with the current key.
+func (s *WebhookSigner) Sign(body []byte) (string, error) {
+	s.mu.Lock()
+	key := s.keys[len(s.keys)-1]
+	now := s.now()
+	s.mu.Unlock()
+
+	header, err := json.Marshal(map[string]any{
+		"alg": "ES256",
+		"kid": key.id,
+		"iat": now.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	hashed := sha256.Sum256([]byte(encodedHeader + "." + base64.RawURLEncoding.EncodeToString(body)))
+	r, sig, err := ecdsa.Sign(rand.Reader, key.privateKey, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := append(r.FillBytes(make([]byte, 32)), sig.FillBytes(make([]byte, 32))...)
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// NewEvent creates a new event of type t with data encoded as JSON, e.g. a
+// [webhooks.SessionEventData], and a unique ID.
+func (s *WebhookSigner) NewEvent(t webhooks.EventType, data any) (*webhooks.Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	return &webhooks.Event{
+		ID:        fmt.Sprintf("evt-%d", s.seq),
+		Type:      t,
+		CreatedAt: s.now().UTC().Truncate(time.Second),
+		Data:      encoded,
+	}, nil
+}
+
+// NewRequest creates a signed webhook POST request of event to url, e.g. for an
+// [httptest.ResponseRecorder] or a running receiver.
+func (s *WebhookSigner) NewRequest(url string, event *webhooks.Event) (*http.Request, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.Sign(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.SignatureHeader, signature)
+
+	return req, nil
+}
+
+// Deliver sends a signed webhook request of event to url, returning the response status code.
+func (s *WebhookSigner) Deliver(url string, event *webhooks.Event) (int, error) {
+	req, err := s.NewRequest(url, event)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -1,5 +1,7 @@
 package enablebankinggo
 
+//go:generate go run ./internal/enumgen -output enumerations_gen.go
+
 // BalanceType represents the type of balance.
 type BalanceType string
 
@@ -46,12 +48,6 @@ func (bt BalanceType) IsEmpty() bool {
 	return bt == ""
 }
 
-// IsValid checks if the BalanceType is valid.
-func (bt BalanceType) IsValid() bool {
-	_, ok := balanceTypeDescriptions[bt]
-	return ok
-}
-
 // Description returns the description of the BalanceType.
 func (bt BalanceType) Description() string {
 	if desc, ok := balanceTypeDescriptions[bt]; ok {
@@ -102,12 +98,6 @@ func (cdi CreditDebitIndicator) IsEmpty() bool {
 	return cdi == ""
 }
 
-// IsValid checks if the CreditDebitIndicator is valid.
-func (cdi CreditDebitIndicator) IsValid() bool {
-	_, ok := creditDebitIndicatorDescriptions[cdi]
-	return ok
-}
-
 // Description returns the description of the CreditDebitIndicator.
 func (cdi CreditDebitIndicator) Description() string {
 	if desc, ok := creditDebitIndicatorDescriptions[cdi]; ok {
@@ -142,12 +132,6 @@ func (pt PSUType) IsEmpty() bool {
 	return pt == ""
 }
 
-// IsValid checks if the PSUType is valid.
-func (pt PSUType) IsValid() bool {
-	_, ok := psuTypeDescriptions[pt]
-	return ok
-}
-
 // Description returns the description of the PSUType.
 func (pt PSUType) Description() string {
 	if desc, ok := psuTypeDescriptions[pt]; ok {
@@ -171,6 +155,29 @@ func PSUTypeDescriptions() map[PSUType]string {
 	return psuTypeDescriptions
 }
 
+// Country represents a two-letter ISO 3166-1 alpha-2 country code.
+type Country string
+
+// IsEmpty checks if the Country is empty.
+func (c Country) IsEmpty() bool {
+	return c == ""
+}
+
+// IsValid checks if the Country is formatted as a two-letter uppercase ISO 3166-1 alpha-2 country code.
+func (c Country) IsValid() bool {
+	if len(c) != 2 {
+		return false
+	}
+
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // RateType represents the type of exchange rate.
 type RateType string
 
@@ -196,12 +203,6 @@ func (rt RateType) IsEmpty() bool {
 	return rt == ""
 }
 
-// IsValid checks if the RateType is valid.
-func (rt RateType) IsValid() bool {
-	_, ok := rateTypeDescriptions[rt]
-	return ok
-}
-
 // Description returns the description of the RateType.
 func (rt RateType) Description() string {
 	if desc, ok := rateTypeDescriptions[rt]; ok {
@@ -261,12 +262,6 @@ func (hk HeaderKey) IsEmpty() bool {
 	return hk == ""
 }
 
-// IsValid checks if the HeaderKey is valid.
-func (hk HeaderKey) IsValid() bool {
-	_, ok := headerKeyDescriptions[hk]
-	return ok
-}
-
 // Description returns the description of the HeaderKey.
 func (hk HeaderKey) Description() string {
 	if desc, ok := headerKeyDescriptions[hk]; ok {
@@ -321,12 +316,6 @@ func (s Service) IsEmpty() bool {
 	return s == ""
 }
 
-// IsValid checks if the Service is valid.
-func (s Service) IsValid() bool {
-	_, ok := serviceDescriptions[s]
-	return ok
-}
-
 // Description returns the description of the Service.
 func (s Service) Description() string {
 	if desc, ok := serviceDescriptions[s]; ok {
@@ -651,12 +640,6 @@ func (ts TransactionStatus) IsEmpty() bool {
 	return ts == ""
 }
 
-// IsValid checks if the TransactionStatus is valid.
-func (ts TransactionStatus) IsValid() bool {
-	_, ok := transactionStatusDescriptions[ts]
-	return ok
-}
-
 // Description returns the description of the TransactionStatus.
 func (ts TransactionStatus) Description() string {
 	if desc, ok := transactionStatusDescriptions[ts]; ok {
@@ -679,3 +662,433 @@ func TransactionStatusKeys() []string {
 	}
 	return keys
 }
+
+// PaymentStatus represents the status of a payment (ISO 20022 payment status codes).
+type PaymentStatus string
+
+const (
+	// AcceptedSettlementCompletedCreditorPaymentStatus indicates settlement on the creditor's account has been
+	// completed.
+	AcceptedSettlementCompletedCreditorPaymentStatus PaymentStatus = "ACCC"
+
+	// AcceptedCustomerProfilePaymentStatus indicates the preceding check of technical validation was successful
+	// and the customer profile check was also successful.
+	AcceptedCustomerProfilePaymentStatus PaymentStatus = "ACCP"
+
+	// AcceptedFundsCheckedPaymentStatus indicates the preceding check of technical validation and customer
+	// profile was successful and an automatic funds check was positive.
+	AcceptedFundsCheckedPaymentStatus PaymentStatus = "ACFC"
+
+	// AcceptedSettlementCompletedPaymentStatus indicates settlement on the debtor's account has been completed.
+	AcceptedSettlementCompletedPaymentStatus PaymentStatus = "ACSC"
+
+	// AcceptedSettlementInProcessPaymentStatus indicates all preceding checks were successful and the payment
+	// initiation has been accepted for execution.
+	AcceptedSettlementInProcessPaymentStatus PaymentStatus = "ACSP"
+
+	// AcceptedTechnicalValidationPaymentStatus indicates authentication and syntactical and semantical
+	// validation are successful.
+	AcceptedTechnicalValidationPaymentStatus PaymentStatus = "ACTC"
+
+	// AcceptedWithChangePaymentStatus indicates the instruction is accepted but a change will be made, such as
+	// the date or remittance not sent.
+	AcceptedWithChangePaymentStatus PaymentStatus = "ACWC"
+
+	// AcceptedWithoutPostingPaymentStatus indicates the payment instruction included in the credit transfer is
+	// accepted without being posted to the creditor customer's account.
+	AcceptedWithoutPostingPaymentStatus PaymentStatus = "ACWP"
+
+	// CancelledPaymentStatus indicates the payment initiation has been cancelled before execution.
+	CancelledPaymentStatus PaymentStatus = "CANC"
+
+	// PartiallyAcceptedTechnicalCorrectPaymentStatus indicates the payment initiation needs multiple
+	// authentications, where some but not yet all have been performed.
+	PartiallyAcceptedTechnicalCorrectPaymentStatus PaymentStatus = "PATC"
+
+	// PartiallyAcceptedPaymentStatus indicates a number of transactions have been accepted, whereas another
+	// number of transactions have not yet achieved accepted status.
+	PartiallyAcceptedPaymentStatus PaymentStatus = "PART"
+
+	// PendingPaymentStatus indicates the payment initiation or individual transaction included in the payment
+	// initiation is pending. Further checks and status update will be performed.
+	PendingPaymentStatus PaymentStatus = "PDNG"
+
+	// ReceivedPaymentStatus indicates the payment initiation has been received by the receiving agent.
+	ReceivedPaymentStatus PaymentStatus = "RCVD"
+
+	// RejectedPaymentStatus indicates the payment initiation or individual transaction included in the payment
+	// initiation has been rejected.
+	RejectedPaymentStatus PaymentStatus = "RJCT"
+)
+
+var paymentStatusDescriptions = map[PaymentStatus]string{
+	AcceptedSettlementCompletedCreditorPaymentStatus: "Accepted settlement completed (creditor)",
+	AcceptedCustomerProfilePaymentStatus:             "Accepted customer profile",
+	AcceptedFundsCheckedPaymentStatus:                "Accepted funds checked",
+	AcceptedSettlementCompletedPaymentStatus:         "Accepted settlement completed",
+	AcceptedSettlementInProcessPaymentStatus:         "Accepted settlement in process",
+	AcceptedTechnicalValidationPaymentStatus:         "Accepted technical validation",
+	AcceptedWithChangePaymentStatus:                  "Accepted with change",
+	AcceptedWithoutPostingPaymentStatus:              "Accepted without posting",
+	CancelledPaymentStatus:                           "Cancelled",
+	PartiallyAcceptedTechnicalCorrectPaymentStatus:   "Partially accepted technical correct",
+	PartiallyAcceptedPaymentStatus:                   "Partially accepted",
+	PendingPaymentStatus:                             "Pending",
+	ReceivedPaymentStatus:                            "Received",
+	RejectedPaymentStatus:                            "Rejected",
+}
+
+// IsEmpty checks if the PaymentStatus is empty.
+func (ps PaymentStatus) IsEmpty() bool {
+	return ps == ""
+}
+
+// Description returns the description of the PaymentStatus.
+func (ps PaymentStatus) Description() string {
+	if desc, ok := paymentStatusDescriptions[ps]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// IsFinal checks if the PaymentStatus is final, i.e. the payment has been settled, rejected or cancelled
+// and the status won't change.
+func (ps PaymentStatus) IsFinal() bool {
+	switch ps {
+	case AcceptedSettlementCompletedCreditorPaymentStatus, AcceptedSettlementCompletedPaymentStatus,
+		RejectedPaymentStatus, CancelledPaymentStatus:
+		return true
+	}
+
+	return false
+}
+
+// PaymentStatusDescriptions returns a map of PaymentStatus to their descriptions.
+func PaymentStatusDescriptions() map[PaymentStatus]string {
+	return paymentStatusDescriptions
+}
+
+// PaymentStatusKeys returns a slice of PaymentStatus as strings.
+func PaymentStatusKeys() []string {
+	keys := make([]string, 0, len(paymentStatusDescriptions))
+	for k := range paymentStatusDescriptions {
+		keys = append(keys, string(k))
+	}
+	return keys
+}
+
+// FrequencyCode represents the frequency of periodic payments.
+type FrequencyCode string
+
+const (
+	// DailyFrequencyCode indicates the payment is executed every day.
+	DailyFrequencyCode FrequencyCode = "DAIL"
+
+	// WeeklyFrequencyCode indicates the payment is executed every week.
+	WeeklyFrequencyCode FrequencyCode = "WEEK"
+
+	// EveryTwoWeeksFrequencyCode indicates the payment is executed every two weeks.
+	EveryTwoWeeksFrequencyCode FrequencyCode = "TOWK"
+
+	// MonthlyFrequencyCode indicates the payment is executed every month.
+	MonthlyFrequencyCode FrequencyCode = "MNTH"
+
+	// EveryTwoMonthsFrequencyCode indicates the payment is executed every two months.
+	EveryTwoMonthsFrequencyCode FrequencyCode = "TOMN"
+
+	// QuarterlyFrequencyCode indicates the payment is executed every three months.
+	QuarterlyFrequencyCode FrequencyCode = "QUTR"
+
+	// SemiAnnualFrequencyCode indicates the payment is executed every six months.
+	SemiAnnualFrequencyCode FrequencyCode = "SEMI"
+
+	// AnnualFrequencyCode indicates the payment is executed every year.
+	AnnualFrequencyCode FrequencyCode = "YEAR"
+)
+
+var frequencyCodeDescriptions = map[FrequencyCode]string{
+	DailyFrequencyCode:          "Daily",
+	WeeklyFrequencyCode:         "Weekly",
+	EveryTwoWeeksFrequencyCode:  "Every two weeks",
+	MonthlyFrequencyCode:        "Monthly",
+	EveryTwoMonthsFrequencyCode: "Every two months",
+	QuarterlyFrequencyCode:      "Quarterly",
+	SemiAnnualFrequencyCode:     "Semi-annual",
+	AnnualFrequencyCode:         "Annual",
+}
+
+// IsEmpty checks if the FrequencyCode is empty.
+func (fc FrequencyCode) IsEmpty() bool {
+	return fc == ""
+}
+
+// Description returns the description of the FrequencyCode.
+func (fc FrequencyCode) Description() string {
+	if desc, ok := frequencyCodeDescriptions[fc]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// FrequencyCodeDescriptions returns a map of FrequencyCode to their descriptions.
+func FrequencyCodeDescriptions() map[FrequencyCode]string {
+	return frequencyCodeDescriptions
+}
+
+// FrequencyCodeKeys returns a slice of FrequencyCode as strings.
+func FrequencyCodeKeys() []string {
+	keys := make([]string, 0, len(frequencyCodeDescriptions))
+	for k := range frequencyCodeDescriptions {
+		keys = append(keys, string(k))
+	}
+	return keys
+}
+
+// ExecutionRule represents the rule for executing periodic payments falling on a non-banking day.
+type ExecutionRule string
+
+const (
+	// FollowingExecutionRule indicates the payment is executed on the following banking day.
+	FollowingExecutionRule ExecutionRule = "FWNG"
+
+	// PrecedingExecutionRule indicates the payment is executed on the preceding banking day.
+	PrecedingExecutionRule ExecutionRule = "PREC"
+)
+
+var executionRuleDescriptions = map[ExecutionRule]string{
+	FollowingExecutionRule: "Following banking day",
+	PrecedingExecutionRule: "Preceding banking day",
+}
+
+// IsEmpty checks if the ExecutionRule is empty.
+func (er ExecutionRule) IsEmpty() bool {
+	return er == ""
+}
+
+// Description returns the description of the ExecutionRule.
+func (er ExecutionRule) Description() string {
+	if desc, ok := executionRuleDescriptions[er]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// ExecutionRuleDescriptions returns a map of ExecutionRule to their descriptions.
+func ExecutionRuleDescriptions() map[ExecutionRule]string {
+	return executionRuleDescriptions
+}
+
+// ChargeBearer represents which party bears the charges of a payment.
+type ChargeBearer string
+
+const (
+	// DebtorChargeBearer indicates all charges are borne by the debtor.
+	DebtorChargeBearer ChargeBearer = "DEBT"
+
+	// CreditorChargeBearer indicates all charges are borne by the creditor.
+	CreditorChargeBearer ChargeBearer = "CRED"
+
+	// SharedChargeBearer indicates the charges of the debtor agent are borne by the debtor and the charges
+	// of the creditor agent are borne by the creditor.
+	SharedChargeBearer ChargeBearer = "SHAR"
+
+	// ServiceLevelChargeBearer indicates charges are applied following the rules agreed in the service
+	// level and/or scheme, e.g. SEPA payments.
+	ServiceLevelChargeBearer ChargeBearer = "SLEV"
+)
+
+var chargeBearerDescriptions = map[ChargeBearer]string{
+	DebtorChargeBearer:       "Borne by debtor",
+	CreditorChargeBearer:     "Borne by creditor",
+	SharedChargeBearer:       "Shared",
+	ServiceLevelChargeBearer: "Following service level",
+}
+
+// IsEmpty checks if the ChargeBearer is empty.
+func (cb ChargeBearer) IsEmpty() bool {
+	return cb == ""
+}
+
+// Description returns the description of the ChargeBearer.
+func (cb ChargeBearer) Description() string {
+	if desc, ok := chargeBearerDescriptions[cb]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// ChargeBearerDescriptions returns a map of ChargeBearer to their descriptions.
+func ChargeBearerDescriptions() map[ChargeBearer]string {
+	return chargeBearerDescriptions
+}
+
+// ServiceLevel represents the service level of a payment (ISO 20022 external service level codes).
+type ServiceLevel string
+
+const (
+	// SEPAServiceLevel indicates the payment must be executed following the Single Euro Payments Area
+	// scheme.
+	SEPAServiceLevel ServiceLevel = "SEPA"
+
+	// SameDayValueServiceLevel indicates the payment must be executed with same day value to the creditor.
+	SameDayValueServiceLevel ServiceLevel = "SDVA"
+
+	// UrgentServiceLevel indicates the payment must be executed as an urgent transaction cleared through a
+	// real-time gross settlement system.
+	UrgentServiceLevel ServiceLevel = "URGP"
+
+	// NonUrgentServiceLevel indicates the payment must be executed as a non-urgent transaction, e.g. cleared
+	// through an automated clearing house.
+	NonUrgentServiceLevel ServiceLevel = "NURG"
+
+	// PriorityServiceLevel indicates the payment must be handled as a priority payment.
+	PriorityServiceLevel ServiceLevel = "PRPT"
+)
+
+var serviceLevelDescriptions = map[ServiceLevel]string{
+	SEPAServiceLevel:         "SEPA",
+	SameDayValueServiceLevel: "Same day value",
+	UrgentServiceLevel:       "Urgent payment",
+	NonUrgentServiceLevel:    "Non-urgent payment",
+	PriorityServiceLevel:     "Priority payment",
+}
+
+// IsEmpty checks if the ServiceLevel is empty.
+func (sl ServiceLevel) IsEmpty() bool {
+	return sl == ""
+}
+
+// Description returns the description of the ServiceLevel.
+func (sl ServiceLevel) Description() string {
+	if desc, ok := serviceLevelDescriptions[sl]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// ServiceLevelDescriptions returns a map of ServiceLevel to their descriptions.
+func ServiceLevelDescriptions() map[ServiceLevel]string {
+	return serviceLevelDescriptions
+}
+
+// PurposeCode represents the underlying reason of a payment (ISO 20022 external purpose codes).
+type PurposeCode string
+
+const (
+	// BenefitPurposeCode indicates an unemployment or disability benefit payment.
+	BenefitPurposeCode PurposeCode = "BENE"
+
+	// CashManagementPurposeCode indicates a cash management transfer.
+	CashManagementPurposeCode PurposeCode = "CASH"
+
+	// CharityPurposeCode indicates a payment for charity reasons.
+	CharityPurposeCode PurposeCode = "CHAR"
+
+	// DividendPurposeCode indicates a payment of dividends.
+	DividendPurposeCode PurposeCode = "DIVD"
+
+	// GoodsPurposeCode indicates a payment for the purchase of goods.
+	GoodsPurposeCode PurposeCode = "GDDS"
+
+	// GovernmentPurposeCode indicates a payment to or from a government department.
+	GovernmentPurposeCode PurposeCode = "GOVT"
+
+	// InterestPurposeCode indicates a payment of interest.
+	InterestPurposeCode PurposeCode = "INTE"
+
+	// IntraCompanyPurposeCode indicates an intra-company payment.
+	IntraCompanyPurposeCode PurposeCode = "INTC"
+
+	// InvestmentPurposeCode indicates a payment for investments.
+	InvestmentPurposeCode PurposeCode = "INVS"
+
+	// LoanPurposeCode indicates a transfer of a loan to a borrower.
+	LoanPurposeCode PurposeCode = "LOAN"
+
+	// OtherPurposeCode indicates other payment purposes.
+	OtherPurposeCode PurposeCode = "OTHR"
+
+	// PensionPurposeCode indicates a payment of pension.
+	PensionPurposeCode PurposeCode = "PENS"
+
+	// RentPurposeCode indicates a payment of rent.
+	RentPurposeCode PurposeCode = "RENT"
+
+	// SalaryPurposeCode indicates a payment of salaries.
+	SalaryPurposeCode PurposeCode = "SALA"
+
+	// ServicesPurposeCode indicates a payment for the purchase of services.
+	ServicesPurposeCode PurposeCode = "SCVE"
+
+	// SupplierPurposeCode indicates a payment to a supplier.
+	SupplierPurposeCode PurposeCode = "SUPP"
+
+	// TaxPurposeCode indicates a payment of taxes.
+	TaxPurposeCode PurposeCode = "TAXS"
+
+	// TradePurposeCode indicates a payment of trade services.
+	TradePurposeCode PurposeCode = "TRAD"
+
+	// ValueAddedTaxPurposeCode indicates a payment of value added tax.
+	ValueAddedTaxPurposeCode PurposeCode = "VATX"
+)
+
+var purposeCodeDescriptions = map[PurposeCode]string{
+	BenefitPurposeCode:        "Benefit payment",
+	CashManagementPurposeCode: "Cash management transfer",
+	CharityPurposeCode:        "Charity payment",
+	DividendPurposeCode:       "Dividend",
+	GoodsPurposeCode:          "Purchase of goods",
+	GovernmentPurposeCode:     "Government payment",
+	InterestPurposeCode:       "Interest",
+	IntraCompanyPurposeCode:   "Intra-company payment",
+	InvestmentPurposeCode:     "Investment",
+	LoanPurposeCode:           "Loan",
+	OtherPurposeCode:          "Other",
+	PensionPurposeCode:        "Pension",
+	RentPurposeCode:           "Rent",
+	SalaryPurposeCode:         "Salary",
+	ServicesPurposeCode:       "Purchase of services",
+	SupplierPurposeCode:       "Supplier payment",
+	TaxPurposeCode:            "Tax payment",
+	TradePurposeCode:          "Trade services",
+	ValueAddedTaxPurposeCode:  "Value added tax",
+}
+
+// IsEmpty checks if the PurposeCode is empty.
+func (pc PurposeCode) IsEmpty() bool {
+	return pc == ""
+}
+
+// Description returns the description of the PurposeCode.
+func (pc PurposeCode) Description() string {
+	if desc, ok := purposeCodeDescriptions[pc]; ok {
+		return desc
+	}
+
+	return ""
+}
+
+// PurposeCodeDescriptions returns a map of PurposeCode to their descriptions.
+func PurposeCodeDescriptions() map[PurposeCode]string {
+	return purposeCodeDescriptions
+}
+
+// RegulatoryReportingIndicator represents to which side of a payment regulatory reporting applies.
+type RegulatoryReportingIndicator string
+
+const (
+	// CreditRegulatoryReportingIndicator indicates the reporting applies to the credit side.
+	CreditRegulatoryReportingIndicator RegulatoryReportingIndicator = "CRED"
+
+	// DebitRegulatoryReportingIndicator indicates the reporting applies to the debit side.
+	DebitRegulatoryReportingIndicator RegulatoryReportingIndicator = "DEBT"
+
+	// BothRegulatoryReportingIndicator indicates the reporting applies to both the credit and debit sides.
+	BothRegulatoryReportingIndicator RegulatoryReportingIndicator = "BOTH"
+)
@@ -0,0 +1,208 @@
+// Code generated by enumgen. DO NOT EDIT.
+
+package enablebankinggo
+
+// IsValid checks if the BalanceType is valid.
+func (bt BalanceType) IsValid() bool {
+	switch bt {
+	case ClosingAvailableBalanceType,
+		ClosingBookedBalanceType,
+		ForwardAvailableBalanceType,
+		InformationBalanceType,
+		InterimAvailableBalanceType,
+		InterimBookedBalanceType,
+		OpeningAvailableBalanceType,
+		OpeningBookedBalanceType,
+		OtherBalanceType,
+		PreviouslyClosedBookedBalanceType,
+		ValueDateBalanceType,
+		ExpectedBalanceType:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the ChargeBearer is valid.
+func (cb ChargeBearer) IsValid() bool {
+	switch cb {
+	case DebtorChargeBearer,
+		CreditorChargeBearer,
+		SharedChargeBearer,
+		ServiceLevelChargeBearer:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the CreditDebitIndicator is valid.
+func (cdi CreditDebitIndicator) IsValid() bool {
+	switch cdi {
+	case CreditCreditDebitIndicator,
+		DebitCreditDebitIndicator:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the ExecutionRule is valid.
+func (er ExecutionRule) IsValid() bool {
+	switch er {
+	case FollowingExecutionRule,
+		PrecedingExecutionRule:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the FrequencyCode is valid.
+func (fc FrequencyCode) IsValid() bool {
+	switch fc {
+	case DailyFrequencyCode,
+		WeeklyFrequencyCode,
+		EveryTwoWeeksFrequencyCode,
+		MonthlyFrequencyCode,
+		EveryTwoMonthsFrequencyCode,
+		QuarterlyFrequencyCode,
+		SemiAnnualFrequencyCode,
+		AnnualFrequencyCode:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the HeaderKey is valid.
+func (hk HeaderKey) IsValid() bool {
+	switch hk {
+	case PSUIPAddressHeaderKey,
+		PSUUserAgentHeaderKey,
+		PSURefererHeaderKey,
+		PSUAcceptHeaderKey,
+		PSUAcceptCharsetHeaderKey,
+		PSUAcceptEncodingHeaderKey,
+		PSUAcceptLanguageHeaderKey,
+		PSUGeoLocationHeaderKey:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the PSUType is valid.
+func (pt PSUType) IsValid() bool {
+	switch pt {
+	case BusinessPSUType,
+		PersonalPSUType:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the PaymentStatus is valid.
+func (ps PaymentStatus) IsValid() bool {
+	switch ps {
+	case AcceptedSettlementCompletedCreditorPaymentStatus,
+		AcceptedCustomerProfilePaymentStatus,
+		AcceptedFundsCheckedPaymentStatus,
+		AcceptedSettlementCompletedPaymentStatus,
+		AcceptedSettlementInProcessPaymentStatus,
+		AcceptedTechnicalValidationPaymentStatus,
+		AcceptedWithChangePaymentStatus,
+		AcceptedWithoutPostingPaymentStatus,
+		CancelledPaymentStatus,
+		PartiallyAcceptedTechnicalCorrectPaymentStatus,
+		PartiallyAcceptedPaymentStatus,
+		PendingPaymentStatus,
+		ReceivedPaymentStatus,
+		RejectedPaymentStatus:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the PurposeCode is valid.
+func (pc PurposeCode) IsValid() bool {
+	switch pc {
+	case BenefitPurposeCode,
+		CashManagementPurposeCode,
+		CharityPurposeCode,
+		DividendPurposeCode,
+		GoodsPurposeCode,
+		GovernmentPurposeCode,
+		InterestPurposeCode,
+		IntraCompanyPurposeCode,
+		InvestmentPurposeCode,
+		LoanPurposeCode,
+		OtherPurposeCode,
+		PensionPurposeCode,
+		RentPurposeCode,
+		SalaryPurposeCode,
+		ServicesPurposeCode,
+		SupplierPurposeCode,
+		TaxPurposeCode,
+		TradePurposeCode,
+		ValueAddedTaxPurposeCode:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the RateType is valid.
+func (rt RateType) IsValid() bool {
+	switch rt {
+	case AGRDRateType,
+		SALERateType,
+		SPOTRateType:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the Service is valid.
+func (s Service) IsValid() bool {
+	switch s {
+	case AccountInformationService,
+		PaymentInitiationService:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the ServiceLevel is valid.
+func (sl ServiceLevel) IsValid() bool {
+	switch sl {
+	case SEPAServiceLevel,
+		SameDayValueServiceLevel,
+		UrgentServiceLevel,
+		NonUrgentServiceLevel,
+		PriorityServiceLevel:
+		return true
+	}
+
+	return false
+}
+
+// IsValid checks if the TransactionStatus is valid.
+func (ts TransactionStatus) IsValid() bool {
+	switch ts {
+	case AccountedTransactionStatus,
+		CancelledTransactionStatus,
+		HoldTransactionStatus,
+		OtherTransactionStatus,
+		InstantBalanceTransactionStatus,
+		RejectedTransactionStatus,
+		ScheduledTransactionStatus:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package enablebankinggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrEnvironmentMismatch is returned by [APIClient.VerifyEnvironment] when the application isn't of the
+// environment of the client.
+var ErrEnvironmentMismatch = errors.New("application environment mismatch")
+
+// WithEnvironment sets the environment of the client, i.e. [ProductionEnvironment] or [SandboxEnvironment]. Enable
+// Banking serves both environments from the same base URL, the environment is determined by the application. Use
+// [APIClient.VerifyEnvironment] to verify the application is of the environment, e.g. guarding against using
+// production keys in staging. [NewClient] returns an error if the environment is unknown.
+func WithEnvironment(environment Environment) ClientOption {
+	return func(c *APIClient) {
+		c.environment = environment
+	}
+}
+
+// validateEnvironment returns an error if the environment set using [WithEnvironment] is unknown.
+func (c *APIClient) validateEnvironment() error {
+	switch c.environment {
+	case "", ProductionEnvironment, SandboxEnvironment:
+		return nil
+	default:
+		return fmt.Errorf("unknown environment %q", c.environment)
+	}
+}
+
+// Environment returns the environment set using [WithEnvironment], or an empty string if none.
+func (c *APIClient) Environment() Environment {
+	return c.environment
+}
+
+// VerifyEnvironment retrieves the application and returns [ErrEnvironmentMismatch] if it isn't of the environment
+// set using [WithEnvironment]. Returns nil if no environment is set.
+func (c *APIClient) VerifyEnvironment(ctx context.Context) error {
+	if c.environment == "" {
+		return nil
+	}
+
+	app, err := c.GetApplication(ctx)
+	if err != nil {
+		return err
+	}
+
+	if app.Environment != c.environment {
+		return fmt.Errorf("%w: client is %s, application is %s", ErrEnvironmentMismatch, c.environment, app.Environment)
+	}
+
+	return nil
+}
@@ -19,6 +19,9 @@ type (
 
 		// Detail provides detailed explanation of an error, if available.
 		Detail []map[string]any `json:"detail,omitempty"`
+
+		// RequestID is the [RequestIDHeader] of the failed request, if available.
+		RequestID string `json:"-"`
 	}
 )
 
@@ -147,6 +150,10 @@ const (
 )
 
 func (e ErrorResponse) Error() string {
+	if e.RequestID != "" {
+		return e.Message + " (request ID " + e.RequestID + ")"
+	}
+
 	return e.Message
 }
 
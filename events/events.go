@@ -0,0 +1,204 @@
+// Package events provides a domain event bus, allowing applications to react uniformly to session,
+// account data and payment events, regardless of whether they were triggered by polling or a webhook.
+// Events are published by the consent manager, the scheduler and the payment status receiver of webhooks,
+// when configured with a [Publisher].
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// Type represents the type of an event.
+type Type string
+
+const (
+	// SessionAuthorizedType is published when a session is authorized by the PSU.
+	SessionAuthorizedType Type = "session.authorized"
+
+	// SessionExpiredType is published when the consent of a session expires.
+	SessionExpiredType Type = "session.expired"
+
+	// SessionRevokedType is published when the consent of a session is revoked by the PSU or ASPSP.
+	SessionRevokedType Type = "session.revoked"
+
+	// SessionClosedType is published when a session is closed, cancelled or invalid.
+	SessionClosedType Type = "session.closed"
+
+	// TransactionsAddedType is published when new transactions of an account are retrieved.
+	TransactionsAddedType Type = "transactions.added"
+
+	// PaymentFinalizedType is published when a payment reaches a final status.
+	PaymentFinalizedType Type = "payment.finalized"
+)
+
+// Source represents what triggered an event.
+type Source string
+
+const (
+	// PollingSource is an event triggered by polling the API, or evaluating stored state.
+	PollingSource Source = "polling"
+
+	// WebhookSource is an event triggered by a webhook notification.
+	WebhookSource Source = "webhook"
+)
+
+// Event represents a domain event.
+type Event struct {
+	// Type is the type of event.
+	Type Type
+
+	// Source is what triggered the event.
+	Source Source
+
+	// SessionID is the session ID, if any.
+	SessionID string
+
+	// AccountID is the account ID of TransactionsAddedType events.
+	AccountID string
+
+	// PaymentID is the payment ID of PaymentFinalizedType events.
+	PaymentID string
+
+	// Status is the session or payment status, if any.
+	Status string
+
+	// Transactions is the added transactions of TransactionsAddedType events.
+	Transactions []*enablebankinggo.Transaction
+
+	// OccurredAt is the time the event occurred.
+	OccurredAt time.Time
+}
+
+// Publisher publishes events.
+type Publisher interface {
+	// Publish publishes an event to the subscribers.
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Handler handles an event.
+type Handler func(ctx context.Context, event *Event) error
+
+type subscription struct {
+	types   []Type
+	handler Handler
+}
+
+func (s *subscription) matches(t Type) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, t)
+}
+
+// Bus is an in-process event bus dispatching events to handler and channel subscribers.
+type Bus struct {
+	mu            sync.RWMutex
+	nextID        int
+	subscriptions map[int]*subscription
+}
+
+// NewBus creates a new event bus.
+func NewBus() *Bus {
+	return &Bus{subscriptions: map[int]*subscription{}}
+}
+
+// Subscribe subscribes handler to events of the provided types, or all events if none, returning a
+// function to unsubscribe. Handlers are called synchronously by Publish.
+func (b *Bus) Subscribe(handler Handler, types ...Type) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscriptions[id] = &subscription{types: types, handler: handler}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscriptions, id)
+	}
+}
+
+// Channel subscribes a channel with the provided buffer size to events of the provided types, or all
+// events if none, returning the channel and a function to unsubscribe and close it. Publish blocks
+// until the event is sent to the channel, the channel is unsubscribed or the publish context is done.
+func (b *Bus) Channel(size int, types ...Type) (<-chan *Event, func()) {
+	ch := make(chan *Event, size)
+	done := make(chan struct{})
+
+	// mu guards sending to ch from closing it, done unblocks pending sends.
+	var mu sync.RWMutex
+	closed := false
+
+	unsubscribe := b.Subscribe(func(ctx context.Context, event *Event) error {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if closed {
+			return nil
+		}
+
+		select {
+		case ch <- event:
+			return nil
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, types...)
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			closed = true
+			close(ch)
+		})
+	}
+}
+
+// Publish publishes an event to the matching subscribers in subscription order, returning the errors
+// of the handlers joined. OccurredAt defaults to now.
+func (b *Bus) Publish(ctx context.Context, event *Event) error {
+	if event == nil {
+		return errors.New("event cannot be nil")
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	ids := make([]int, 0, len(b.subscriptions))
+	for id, s := range b.subscriptions {
+		if s.matches(event.Type) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	handlers := make([]Handler, 0, len(ids))
+	for _, id := range ids {
+		handlers = append(handlers, b.subscriptions[id].handler)
+	}
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
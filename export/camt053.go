@@ -0,0 +1,495 @@
+package export
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// CAMT053Namespace is the XML namespace of the camt.053 version produced by [WriteCAMT053].
+const CAMT053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+// camt053BalanceCodes is the balance types with an ISO 20022 balance type code.
+var camt053BalanceCodes = []enablebankinggo.BalanceType{
+	enablebankinggo.ClosingAvailableBalanceType,
+	enablebankinggo.ClosingBookedBalanceType,
+	enablebankinggo.ForwardAvailableBalanceType,
+	enablebankinggo.InformationBalanceType,
+	enablebankinggo.InterimAvailableBalanceType,
+	enablebankinggo.InterimBookedBalanceType,
+	enablebankinggo.OpeningAvailableBalanceType,
+	enablebankinggo.OpeningBookedBalanceType,
+	enablebankinggo.PreviouslyClosedBookedBalanceType,
+	enablebankinggo.ExpectedBalanceType,
+}
+
+type camt053Document struct {
+	XMLName xml.Name          `xml:"Document"`
+	Xmlns   string            `xml:"xmlns,attr"`
+	Message camt053BkToCstmrs `xml:"BkToCstmrStmt"`
+}
+
+type camt053BkToCstmrs struct {
+	GroupHeader camt053GrpHdr  `xml:"GrpHdr"`
+	Statements  []*camt053Stmt `xml:"Stmt"`
+}
+
+type camt053GrpHdr struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type camt053Stmt struct {
+	ID        string             `xml:"Id"`
+	CreatedAt string             `xml:"CreDtTm"`
+	Period    *camt053FromToDate `xml:"FrToDt,omitempty"`
+	Account   camt053Account     `xml:"Acct"`
+	Balances  []*camt053Balance  `xml:"Bal"`
+	Summary   *camt053TxsSummary `xml:"TxsSummry,omitempty"`
+	Entries   []*camt053Entry    `xml:"Ntry"`
+}
+
+type camt053FromToDate struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type camt053Account struct {
+	ID       camt053AccountID       `xml:"Id"`
+	Currency string                 `xml:"Ccy,omitempty"`
+	Name     string                 `xml:"Nm,omitempty"`
+	Servicer *camt053FinInstnIDWrap `xml:"Svcr,omitempty"`
+}
+
+type camt053AccountID struct {
+	IBAN  string            `xml:"IBAN,omitempty"`
+	Other *camt053GenericID `xml:"Othr,omitempty"`
+}
+
+type camt053GenericID struct {
+	ID string `xml:"Id"`
+}
+
+type camt053FinInstnIDWrap struct {
+	FinancialInstitution camt053FinInstnID `xml:"FinInstnId"`
+}
+
+type camt053FinInstnID struct {
+	BICFI string `xml:"BICFI,omitempty"`
+	Name  string `xml:"Nm,omitempty"`
+}
+
+type camt053Balance struct {
+	Type                 camt053BalanceType `xml:"Tp"`
+	Amount               camt053Amount      `xml:"Amt"`
+	CreditDebitIndicator string             `xml:"CdtDbtInd"`
+	Date                 camt053Date        `xml:"Dt"`
+}
+
+type camt053BalanceType struct {
+	CodeOrProprietary camt053CodeOrProprietary `xml:"CdOrPrtry"`
+}
+
+type camt053CodeOrProprietary struct {
+	Code        string `xml:"Cd,omitempty"`
+	Proprietary string `xml:"Prtry,omitempty"`
+}
+
+type camt053Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camt053Date struct {
+	Date string `xml:"Dt"`
+}
+
+type camt053TxsSummary struct {
+	Total   camt053NumberOfEntries `xml:"TtlNtries"`
+	Credits *camt053NumberAndSum   `xml:"TtlCdtNtries,omitempty"`
+	Debits  *camt053NumberAndSum   `xml:"TtlDbtNtries,omitempty"`
+}
+
+type camt053NumberOfEntries struct {
+	Number int `xml:"NbOfNtries"`
+}
+
+type camt053NumberAndSum struct {
+	Number int    `xml:"NbOfNtries"`
+	Sum    string `xml:"Sum"`
+}
+
+type camt053Entry struct {
+	Reference            string                   `xml:"NtryRef,omitempty"`
+	Amount               camt053Amount            `xml:"Amt"`
+	CreditDebitIndicator string                   `xml:"CdtDbtInd"`
+	Status               camt053CodeOrProprietary `xml:"Sts"`
+	BookingDate          *camt053Date             `xml:"BookgDt,omitempty"`
+	ValueDate            *camt053Date             `xml:"ValDt,omitempty"`
+	AccountServicerRef   string                   `xml:"AcctSvcrRef,omitempty"`
+	BankTransactionCode  camt053BankTxCode        `xml:"BkTxCd"`
+	Details              *camt053EntryDetails     `xml:"NtryDtls,omitempty"`
+	AdditionalEntryInfo  string                   `xml:"AddtlNtryInf,omitempty"`
+}
+
+type camt053BankTxCode struct {
+	Proprietary *camt053ProprietaryCode `xml:"Prtry,omitempty"`
+}
+
+type camt053ProprietaryCode struct {
+	Code   string `xml:"Cd"`
+	Issuer string `xml:"Issr,omitempty"`
+}
+
+type camt053EntryDetails struct {
+	Transaction camt053TxDetails `xml:"TxDtls"`
+}
+
+type camt053TxDetails struct {
+	References     *camt053References     `xml:"Refs,omitempty"`
+	AmountDetails  *camt053AmountDetails  `xml:"AmtDtls,omitempty"`
+	RelatedParties *camt053RelatedParties `xml:"RltdPties,omitempty"`
+	RemittanceInfo *camt053RemittanceInfo `xml:"RmtInf,omitempty"`
+}
+
+type camt053References struct {
+	AccountServicerReference string `xml:"AcctSvcrRef,omitempty"`
+	TransactionID            string `xml:"TxId,omitempty"`
+}
+
+type camt053AmountDetails struct {
+	Instructed camt053InstructedAmount `xml:"InstdAmt"`
+}
+
+type camt053InstructedAmount struct {
+	Amount       camt053Amount        `xml:"Amt"`
+	ExchangeInfo *camt053ExchangeInfo `xml:"CcyXchg,omitempty"`
+}
+
+type camt053ExchangeInfo struct {
+	SourceCurrency string `xml:"SrcCcy"`
+	TargetCurrency string `xml:"TrgtCcy,omitempty"`
+	UnitCurrency   string `xml:"UnitCcy,omitempty"`
+	ExchangeRate   string `xml:"XchgRate"`
+	ContractID     string `xml:"CtrctId,omitempty"`
+}
+
+type camt053RelatedParties struct {
+	Debtor          *camt053PartyWrap   `xml:"Dbtr,omitempty"`
+	DebtorAccount   *camt053AccountWrap `xml:"DbtrAcct,omitempty"`
+	Creditor        *camt053PartyWrap   `xml:"Cdtr,omitempty"`
+	CreditorAccount *camt053AccountWrap `xml:"CdtrAcct,omitempty"`
+}
+
+type camt053PartyWrap struct {
+	Party camt053Party `xml:"Pty"`
+}
+
+type camt053Party struct {
+	Name string `xml:"Nm"`
+}
+
+type camt053AccountWrap struct {
+	ID camt053AccountID `xml:"Id"`
+}
+
+type camt053RemittanceInfo struct {
+	Unstructured []string              `xml:"Ustrd,omitempty"`
+	Structured   *camt053StructuredRmt `xml:"Strd,omitempty"`
+}
+
+type camt053StructuredRmt struct {
+	CreditorReference camt053CreditorRef `xml:"CdtrRefInf"`
+}
+
+type camt053CreditorRef struct {
+	Reference string `xml:"Ref"`
+}
+
+// WriteCAMT053 writes an ISO 20022 camt.053 bank-to-customer statement message (version 001.08) with the
+// provided statements to w, allowing accounting systems ingesting CAMT to consume Enable Banking data.
+//
+// Only booked transactions are exported. The opening and closing booked balances, if any, are exported
+// as balances, falling back to all balances with an ISO 20022 balance type code.
+func WriteCAMT053(w io.Writer, statements ...*Statement) error {
+	if len(statements) == 0 {
+		return errors.New("statements cannot be empty")
+	}
+
+	doc := &camt053Document{Xmlns: CAMT053Namespace}
+	createdAt := time.Time{}
+	for _, statement := range statements {
+		err := statement.validate()
+		if err != nil {
+			return err
+		}
+
+		stmt := newCAMT053Statement(statement)
+		doc.Message.Statements = append(doc.Message.Statements, stmt)
+		createdAt = statement.createdAt()
+	}
+
+	doc.Message.GroupHeader = camt053GrpHdr{
+		MessageID: truncate(doc.Message.Statements[0].ID, 35),
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newCAMT053Statement(s *Statement) *camt053Stmt {
+	currency := s.currency()
+	stmt := &camt053Stmt{
+		ID:        s.id(),
+		CreatedAt: s.createdAt().Format(time.RFC3339),
+		Account: camt053Account{
+			ID:       camt053AccountIDOf(s.Account.AccountID),
+			Currency: currency,
+			Name:     s.Account.Name,
+		},
+	}
+
+	if stmt.Account.ID.IBAN == "" && stmt.Account.ID.Other == nil {
+		stmt.Account.ID.Other = &camt053GenericID{ID: accountIdentifier(s.Account)}
+	}
+
+	if servicer := s.Account.AccountServicer; servicer != nil && (servicer.BICFI != "" || servicer.Name != "") {
+		stmt.Account.Servicer = &camt053FinInstnIDWrap{
+			FinancialInstitution: camt053FinInstnID{BICFI: servicer.BICFI, Name: servicer.Name},
+		}
+	}
+
+	if !s.From.IsZero() && !s.To.IsZero() {
+		stmt.Period = &camt053FromToDate{
+			From: startOfDay(s.From).Format(time.RFC3339),
+			To:   startOfDay(s.To).Add(24*time.Hour - time.Second).Format(time.RFC3339),
+		}
+	}
+
+	for _, balance := range camt053Balances(s) {
+		amount, negative := splitAmount(balance.BalanceAmmount.Amount)
+		date := balance.ReferenceDate
+		if date == "" && balance.LastChangeDateTime != nil {
+			date = balance.LastChangeDateTime.Format(time.DateOnly)
+		}
+
+		if date == "" {
+			date = s.To.Format(time.DateOnly)
+		}
+
+		b := &camt053Balance{
+			Amount:               camt053Amount{Currency: balance.BalanceAmmount.Currency, Value: amount},
+			CreditDebitIndicator: camt053Indicator(negative),
+			Date:                 camt053Date{Date: date},
+		}
+
+		if slices.Contains(camt053BalanceCodes, balance.BalanceType) {
+			b.Type.CodeOrProprietary.Code = string(balance.BalanceType)
+		} else {
+			b.Type.CodeOrProprietary.Proprietary = string(balance.BalanceType)
+		}
+
+		stmt.Balances = append(stmt.Balances, b)
+	}
+
+	var credits, debits camt053NumberAndSum
+	var creditAmounts, debitAmounts []string
+	for _, tx := range s.bookedTransactions() {
+		entry := newCAMT053Entry(tx, currency)
+		stmt.Entries = append(stmt.Entries, entry)
+
+		if entry.CreditDebitIndicator == string(enablebankinggo.CreditCreditDebitIndicator) {
+			creditAmounts = append(creditAmounts, entry.Amount.Value)
+		} else {
+			debitAmounts = append(debitAmounts, entry.Amount.Value)
+		}
+	}
+
+	stmt.Summary = &camt053TxsSummary{Total: camt053NumberOfEntries{Number: len(creditAmounts) + len(debitAmounts)}}
+	if len(creditAmounts) > 0 {
+		credits.Number = len(creditAmounts)
+		credits.Sum = sumAmounts(creditAmounts)
+		stmt.Summary.Credits = &credits
+	}
+
+	if len(debitAmounts) > 0 {
+		debits.Number = len(debitAmounts)
+		debits.Sum = sumAmounts(debitAmounts)
+		stmt.Summary.Debits = &debits
+	}
+
+	return stmt
+}
+
+func camt053Balances(s *Statement) []*enablebankinggo.BalanceResource {
+	var balances []*enablebankinggo.BalanceResource
+	if opening := s.balance(enablebankinggo.OpeningBookedBalanceType, enablebankinggo.PreviouslyClosedBookedBalanceType); opening != nil {
+		balances = append(balances, opening)
+	}
+
+	if closing := s.balance(enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType); closing != nil {
+		balances = append(balances, closing)
+	}
+
+	if len(balances) > 0 {
+		return balances
+	}
+
+	for _, balance := range s.Balances {
+		if balance != nil && balance.BalanceAmmount != nil && slices.Contains(camt053BalanceCodes, balance.BalanceType) {
+			balances = append(balances, balance)
+		}
+	}
+
+	return balances
+}
+
+func newCAMT053Entry(tx *enablebankinggo.Transaction, currency string) *camt053Entry {
+	amount, negative := "", false
+	txCurrency := currency
+	if tx.TransactionAmount != nil {
+		amount, negative = splitAmount(tx.TransactionAmount.Amount)
+		txCurrency = tx.TransactionAmount.Currency
+	}
+
+	indicator := string(tx.CreditDebitIndicator)
+	if indicator == "" {
+		indicator = camt053Indicator(negative)
+	}
+
+	entry := &camt053Entry{
+		Reference:            truncate(tx.EntryReference, 35),
+		Amount:               camt053Amount{Currency: txCurrency, Value: amount},
+		CreditDebitIndicator: indicator,
+		Status:               camt053CodeOrProprietary{Code: "BOOK"},
+		AccountServicerRef:   truncate(tx.EntryReference, 35),
+	}
+
+	if tx.BookingDate != "" {
+		entry.BookingDate = &camt053Date{Date: tx.BookingDate}
+	}
+
+	if tx.ValueDate != "" {
+		entry.ValueDate = &camt053Date{Date: tx.ValueDate}
+	}
+
+	if code := tx.BankTransactionCode; code != nil && code.Code != "" {
+		entry.BankTransactionCode.Proprietary = &camt053ProprietaryCode{Code: strings.Trim(code.Code+"/"+code.SubCode, "/")}
+	} else {
+		entry.BankTransactionCode.Proprietary = &camt053ProprietaryCode{Code: "NOTPROVIDED"}
+	}
+
+	details := &camt053TxDetails{}
+	if tx.EntryReference != "" || tx.TransactionID != "" {
+		details.References = &camt053References{
+			AccountServicerReference: truncate(tx.EntryReference, 35),
+			TransactionID:            truncate(tx.TransactionID, 35),
+		}
+	}
+
+	if rate := tx.ExchangeRate; rate != nil && rate.ExchangeRate != "" && rate.InstructedAmount != nil {
+		instructed, _ := splitAmount(rate.InstructedAmount.Amount)
+		details.AmountDetails = &camt053AmountDetails{
+			Instructed: camt053InstructedAmount{
+				Amount: camt053Amount{Currency: rate.InstructedAmount.Currency, Value: instructed},
+				ExchangeInfo: &camt053ExchangeInfo{
+					SourceCurrency: rate.InstructedAmount.Currency,
+					TargetCurrency: txCurrency,
+					UnitCurrency:   rate.UnitCurrency,
+					ExchangeRate:   rate.ExchangeRate,
+					ContractID:     rate.ContractIdentification,
+				},
+			},
+		}
+	}
+
+	parties := &camt053RelatedParties{}
+	if tx.Debtor != nil && tx.Debtor.Name != "" {
+		parties.Debtor = &camt053PartyWrap{Party: camt053Party{Name: truncate(tx.Debtor.Name, 140)}}
+	}
+
+	if id := camt053AccountIDOf(tx.DebtorAccount); id.IBAN != "" || id.Other != nil {
+		parties.DebtorAccount = &camt053AccountWrap{ID: id}
+	}
+
+	if tx.Creditor != nil && tx.Creditor.Name != "" {
+		parties.Creditor = &camt053PartyWrap{Party: camt053Party{Name: truncate(tx.Creditor.Name, 140)}}
+	}
+
+	if id := camt053AccountIDOf(tx.CreditorAccount); id.IBAN != "" || id.Other != nil {
+		parties.CreditorAccount = &camt053AccountWrap{ID: id}
+	}
+
+	if *parties != (camt053RelatedParties{}) {
+		details.RelatedParties = parties
+	}
+
+	if len(tx.RemittanceInformation) > 0 || tx.ReferenceNumber != "" {
+		details.RemittanceInfo = &camt053RemittanceInfo{}
+		for _, line := range tx.RemittanceInformation {
+			details.RemittanceInfo.Unstructured = append(details.RemittanceInfo.Unstructured, truncate(line, 140))
+		}
+
+		if tx.ReferenceNumber != "" {
+			details.RemittanceInfo.Structured = &camt053StructuredRmt{
+				CreditorReference: camt053CreditorRef{Reference: truncate(tx.ReferenceNumber, 35)},
+			}
+		}
+	}
+
+	if *details != (camt053TxDetails{}) {
+		entry.Details = &camt053EntryDetails{Transaction: *details}
+	}
+
+	if tx.Note != "" {
+		entry.AdditionalEntryInfo = truncate(tx.Note, 500)
+	}
+
+	return entry
+}
+
+func camt053AccountIDOf(id *enablebankinggo.AccountIdentification) camt053AccountID {
+	if id == nil {
+		return camt053AccountID{}
+	}
+
+	if id.IBAN != "" {
+		return camt053AccountID{IBAN: id.IBAN}
+	}
+
+	if id.Other != nil && id.Other.Identification != "" {
+		return camt053AccountID{Other: &camt053GenericID{ID: id.Other.Identification}}
+	}
+
+	return camt053AccountID{}
+}
+
+func camt053Indicator(negative bool) string {
+	if negative {
+		return string(enablebankinggo.DebitCreditDebitIndicator)
+	}
+
+	return string(enablebankinggo.CreditCreditDebitIndicator)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,301 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// LedgerFormat represents a plain text accounting file format.
+type LedgerFormat string
+
+const (
+	// LedgerLedgerFormat is the Ledger (and hledger) journal format.
+	LedgerLedgerFormat LedgerFormat = "ledger"
+
+	// BeancountLedgerFormat is the Beancount format.
+	BeancountLedgerFormat LedgerFormat = "beancount"
+)
+
+const (
+	// DefaultLedgerAccount is the default account of the exported statements.
+	DefaultLedgerAccount = "Assets:Bank"
+
+	// DefaultLedgerExpenseAccount is the default account of uncategorized debits.
+	DefaultLedgerExpenseAccount = "Expenses:Uncategorized"
+
+	// DefaultLedgerIncomeAccount is the default account of uncategorized credits.
+	DefaultLedgerIncomeAccount = "Income:Uncategorized"
+)
+
+// LedgerConfig represents the configuration of a plain text accounting export.
+type LedgerConfig struct {
+	// Format is the file format. Defaults to [LedgerLedgerFormat].
+	Format LedgerFormat
+
+	// Account is the account of the exported statements, e.g. Assets:Bank:Checking. Defaults to
+	// [DefaultLedgerAccount].
+	Account string
+
+	// Accounts is the account of the statement account identifiers, i.e. IBAN or other
+	// identification, overriding Account when exporting statements of multiple accounts.
+	Accounts map[string]string
+
+	// Categorize returns the category of a transaction, e.g. groceries. Defaults to the bank
+	// transaction code description.
+	Categorize func(tx *enablebankinggo.Transaction) string
+
+	// CategoryAccounts is the counter account of categories, e.g. groceries mapped to
+	// Expenses:Food:Groceries.
+	CategoryAccounts map[string]string
+
+	// ExpenseAccount is the counter account of debits without a mapped category. Defaults to
+	// [DefaultLedgerExpenseAccount].
+	ExpenseAccount string
+
+	// IncomeAccount is the counter account of credits without a mapped category. Defaults to
+	// [DefaultLedgerIncomeAccount].
+	IncomeAccount string
+
+	// OmitOpenDirectives disables writing Beancount open directives of the used accounts, e.g. when
+	// appending to an existing file.
+	OmitOpenDirectives bool
+}
+
+type ledgerEntry struct {
+	date      string
+	payee     string
+	narration string
+	reference string
+	account   string
+	counter   string
+	amount    string
+	currency  string
+}
+
+// WriteLedger writes the booked transactions of the provided statements as plain text accounting
+// entries to w, sorted by date, in the format of config. Each transaction is posted to the statement
+// account and the counter account of its category.
+//
+// Beancount files include open directives of the used accounts and balance assertions of the closing
+// booked balances of the statements.
+func WriteLedger(w io.Writer, config LedgerConfig, statements ...*Statement) error {
+	if len(statements) == 0 {
+		return errors.New("statements cannot be empty")
+	}
+
+	config = config.withDefaults()
+	if config.Format != LedgerLedgerFormat && config.Format != BeancountLedgerFormat {
+		return fmt.Errorf("unsupported ledger format %q", config.Format)
+	}
+
+	var entries []*ledgerEntry
+	var assertions []string
+	opened := map[string]string{}
+	open := func(account, date string) {
+		if current, ok := opened[account]; !ok || date < current {
+			opened[account] = date
+		}
+	}
+
+	for _, s := range statements {
+		err := s.validate()
+		if err != nil {
+			return err
+		}
+
+		account := config.account(s.Account)
+		currency := s.currency()
+		for _, tx := range s.bookedTransactions() {
+			entry := config.entry(account, currency, tx)
+			entries = append(entries, entry)
+			open(entry.account, entry.date)
+			open(entry.counter, entry.date)
+		}
+
+		if config.Format == BeancountLedgerFormat {
+			if assertion, ok := beancountBalance(account, s); ok {
+				assertions = append(assertions, assertion)
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b *ledgerEntry) int {
+		return strings.Compare(a.date, b.date)
+	})
+
+	bw := bufio.NewWriter(w)
+	if config.Format == BeancountLedgerFormat && !config.OmitOpenDirectives {
+		accounts := make([]string, 0, len(opened))
+		for account := range opened {
+			accounts = append(accounts, account)
+		}
+		slices.Sort(accounts)
+
+		for _, account := range accounts {
+			fmt.Fprintf(bw, "%s open %s\n", opened[account], account)
+		}
+
+		if len(accounts) > 0 {
+			bw.WriteString("\n")
+		}
+	}
+
+	for _, entry := range entries {
+		if config.Format == BeancountLedgerFormat {
+			writeBeancountEntry(bw, entry)
+		} else {
+			writeLedgerEntry(bw, entry)
+		}
+	}
+
+	slices.Sort(assertions)
+	for _, assertion := range assertions {
+		fmt.Fprintf(bw, "%s\n", assertion)
+	}
+
+	return bw.Flush()
+}
+
+func (c LedgerConfig) withDefaults() LedgerConfig {
+	if c.Format == "" {
+		c.Format = LedgerLedgerFormat
+	}
+
+	if c.Account == "" {
+		c.Account = DefaultLedgerAccount
+	}
+
+	if c.ExpenseAccount == "" {
+		c.ExpenseAccount = DefaultLedgerExpenseAccount
+	}
+
+	if c.IncomeAccount == "" {
+		c.IncomeAccount = DefaultLedgerIncomeAccount
+	}
+
+	if c.Categorize == nil {
+		c.Categorize = func(tx *enablebankinggo.Transaction) string {
+			if tx.BankTransactionCode != nil {
+				return tx.BankTransactionCode.Description
+			}
+
+			return ""
+		}
+	}
+
+	return c
+}
+
+func (c LedgerConfig) account(account *enablebankinggo.AccountResource) string {
+	if mapped, ok := c.Accounts[accountIdentifier(account)]; ok {
+		return mapped
+	}
+
+	return c.Account
+}
+
+func (c LedgerConfig) entry(account, currency string, tx *enablebankinggo.Transaction) *ledgerEntry {
+	amount := signedAmount(tx)
+	entry := &ledgerEntry{
+		date:      transactionDate(tx),
+		narration: strings.Join(tx.RemittanceInformation, " "),
+		reference: tx.EntryReference,
+		account:   account,
+		amount:    amount.FloatString(ledgerDecimals(tx)),
+		currency:  currency,
+	}
+
+	if tx.TransactionAmount != nil && tx.TransactionAmount.Currency != "" {
+		entry.currency = tx.TransactionAmount.Currency
+	}
+
+	if party, _ := counterparty(tx); party != nil {
+		entry.payee = party.Name
+	}
+
+	entry.counter = c.CategoryAccounts[c.Categorize(tx)]
+	if entry.counter == "" {
+		entry.counter = c.IncomeAccount
+		if amount.Sign() < 0 {
+			entry.counter = c.ExpenseAccount
+		}
+	}
+
+	return entry
+}
+
+// ledgerDecimals returns the number of decimals of the amount of tx, at least two.
+func ledgerDecimals(tx *enablebankinggo.Transaction) int {
+	if tx.TransactionAmount == nil {
+		return 2
+	}
+
+	_, fraction, _ := strings.Cut(tx.TransactionAmount.Amount, ".")
+	return max(2, len(strings.TrimSpace(fraction)))
+}
+
+func writeLedgerEntry(w *bufio.Writer, entry *ledgerEntry) {
+	description := entry.payee
+	if description == "" {
+		description = entry.narration
+	} else if entry.narration != "" {
+		description += " | " + entry.narration
+	}
+
+	fmt.Fprintf(w, "%s * %s\n", strings.ReplaceAll(entry.date, "-", "/"), ledgerText(description))
+	if entry.reference != "" {
+		fmt.Fprintf(w, "    ; entry_reference: %s\n", ledgerText(entry.reference))
+	}
+
+	fmt.Fprintf(w, "    %s  %s %s\n", entry.account, entry.amount, entry.currency)
+	fmt.Fprintf(w, "    %s\n\n", entry.counter)
+}
+
+func writeBeancountEntry(w *bufio.Writer, entry *ledgerEntry) {
+	fmt.Fprintf(w, "%s * %s %s\n", entry.date, beancountString(entry.payee), beancountString(entry.narration))
+	if entry.reference != "" {
+		fmt.Fprintf(w, "  entry_reference: %s\n", beancountString(entry.reference))
+	}
+
+	fmt.Fprintf(w, "  %s  %s %s\n", entry.account, entry.amount, entry.currency)
+	fmt.Fprintf(w, "  %s\n\n", entry.counter)
+}
+
+// beancountBalance returns a balance assertion of the closing booked balance of the statement, dated
+// the day after the balance as Beancount asserts balances at the beginning of the day.
+func beancountBalance(account string, s *Statement) (string, bool) {
+	balance := s.balance(enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType)
+	amount, ok := balanceAmount(balance)
+	if !ok {
+		return "", false
+	}
+
+	date, err := time.Parse(time.DateOnly, balance.ReferenceDate)
+	if err != nil {
+		if s.To.IsZero() {
+			return "", false
+		}
+
+		date = s.To
+	}
+
+	return fmt.Sprintf("%s balance %s  %s %s", date.AddDate(0, 0, 1).Format(time.DateOnly), account, amount.FloatString(2), balance.BalanceAmmount.Currency), true
+}
+
+// ledgerText returns s on a single line.
+func ledgerText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// beancountString returns s as a quoted Beancount string.
+func beancountString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(ledgerText(s))
+	return `"` + s + `"`
+}
@@ -0,0 +1,331 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// mt940Transliterations is the replacements of common characters outside the SWIFT X character set.
+var mt940Transliterations = strings.NewReplacer(
+	"å", "a", "ä", "a", "à", "a", "á", "a", "â", "a", "ã", "a", "æ", "ae",
+	"Å", "A", "Ä", "A", "À", "A", "Á", "A", "Â", "A", "Ã", "A", "Æ", "AE",
+	"ç", "c", "Ç", "C", "č", "c", "Č", "C",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"í", "i", "ì", "i", "î", "i", "ï", "i", "Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"ñ", "n", "Ñ", "N",
+	"ö", "o", "ø", "o", "ò", "o", "ó", "o", "ô", "o", "õ", "o",
+	"Ö", "O", "Ø", "O", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O",
+	"š", "s", "Š", "S", "ß", "ss",
+	"ü", "u", "ù", "u", "ú", "u", "û", "u", "Ü", "U", "Ù", "U", "Ú", "U", "Û", "U",
+	"ž", "z", "Ž", "Z",
+	"&", "+", "_", "-", "\"", "'", "*", ".", "@", "(at)", "#", "", ";", ",",
+)
+
+// DailyStatements splits the statement into one statement per booking date with booked transactions,
+// with opening and closing booked balances derived from the opening booked balance of the statement, or
+// the closing booked balance if there's no opening balance.
+func DailyStatements(s *Statement) ([]*Statement, error) {
+	err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := map[string][]*enablebankinggo.Transaction{}
+	total := new(big.Rat)
+	for _, tx := range s.bookedTransactions() {
+		date := transactionDate(tx)
+		byDate[date] = append(byDate[date], tx)
+		total.Add(total, signedAmount(tx))
+	}
+
+	running, ok := balanceAmount(s.balance(enablebankinggo.OpeningBookedBalanceType, enablebankinggo.PreviouslyClosedBookedBalanceType))
+	if !ok {
+		closing, ok := balanceAmount(s.balance(enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType))
+		if !ok {
+			return nil, errors.New("statement has no booked balance")
+		}
+
+		running = new(big.Rat).Sub(closing, total)
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+
+	currency := s.currency()
+	statements := make([]*Statement, 0, len(dates))
+	for _, date := range dates {
+		day, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid booking date %q: %w", date, err)
+		}
+
+		opening := new(big.Rat).Set(running)
+		for _, tx := range byDate[date] {
+			running.Add(running, signedAmount(tx))
+		}
+
+		statements = append(statements, &Statement{
+			Account: s.Account,
+			Balances: []*enablebankinggo.BalanceResource{
+				{
+					BalanceAmmount: &enablebankinggo.AmountType{Amount: opening.FloatString(2), Currency: currency},
+					BalanceType:    enablebankinggo.OpeningBookedBalanceType,
+					ReferenceDate:  date,
+				},
+				{
+					BalanceAmmount: &enablebankinggo.AmountType{Amount: running.FloatString(2), Currency: currency},
+					BalanceType:    enablebankinggo.ClosingBookedBalanceType,
+					ReferenceDate:  date,
+				},
+			},
+			Transactions: byDate[date],
+			From:         day,
+			To:           day,
+			CreatedAt:    s.CreatedAt,
+		})
+	}
+
+	return statements, nil
+}
+
+// WriteMT940 writes the provided statements as SWIFT MT940 customer statements to w, numbered
+// sequentially, for legacy ERP and treasury systems only importing MT940. Use [DailyStatements] to
+// write one statement per account and day.
+//
+// Only booked transactions are exported. The opening balance is the opening booked balance of the
+// statement, or derived from the closing booked balance and the transactions, and vice versa. Text is
+// transliterated to the SWIFT character set.
+func WriteMT940(w io.Writer, statements ...*Statement) error {
+	if len(statements) == 0 {
+		return errors.New("statements cannot be empty")
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, s := range statements {
+		err := writeMT940Statement(bw, i+1, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeMT940Statement(w *bufio.Writer, number int, s *Statement) error {
+	err := s.validate()
+	if err != nil {
+		return err
+	}
+
+	transactions := s.bookedTransactions()
+	total := new(big.Rat)
+	for _, tx := range transactions {
+		total.Add(total, signedAmount(tx))
+	}
+
+	openingBalance := s.balance(enablebankinggo.OpeningBookedBalanceType, enablebankinggo.PreviouslyClosedBookedBalanceType)
+	closingBalance := s.balance(enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType)
+	opening, hasOpening := balanceAmount(openingBalance)
+	closing, hasClosing := balanceAmount(closingBalance)
+
+	switch {
+	case hasOpening && !hasClosing:
+		closing = new(big.Rat).Add(opening, total)
+	case !hasOpening && hasClosing:
+		opening = new(big.Rat).Sub(closing, total)
+	case !hasOpening && !hasClosing:
+		return errors.New("statement has no booked balance")
+	}
+
+	currency := s.currency()
+	openingDate := mt940BalanceDate(openingBalance, s.From, transactions, false)
+	closingDate := mt940BalanceDate(closingBalance, s.To, transactions, true)
+
+	reference := s.ID
+	if reference == "" {
+		reference = fmt.Sprintf("%s%05d", closingDate.Format("060102"), number%100000)
+	}
+
+	fmt.Fprintf(w, ":20:%s\r\n", truncate(mt940Text(reference), 16))
+	fmt.Fprintf(w, ":25:%s\r\n", truncate(mt940Text(accountIdentifier(s.Account)), 35))
+	fmt.Fprintf(w, ":28C:%d/1\r\n", number%100000)
+	fmt.Fprintf(w, ":60F:%s\r\n", mt940Balance(opening, openingDate, currency))
+
+	for _, tx := range transactions {
+		writeMT940Transaction(w, tx)
+	}
+
+	fmt.Fprintf(w, ":62F:%s\r\n", mt940Balance(closing, closingDate, currency))
+
+	if available, ok := balanceAmount(s.balance(enablebankinggo.ClosingAvailableBalanceType, enablebankinggo.InterimAvailableBalanceType)); ok {
+		fmt.Fprintf(w, ":64:%s\r\n", mt940Balance(available, closingDate, currency))
+	}
+
+	_, err = w.WriteString("-\r\n")
+	return err
+}
+
+func writeMT940Transaction(w *bufio.Writer, tx *enablebankinggo.Transaction) {
+	amount := signedAmount(tx)
+	mark := "C"
+	if amount.Sign() < 0 {
+		mark = "D"
+	}
+
+	bookingDate := mt940ParseDate(transactionDate(tx))
+	valueDate := mt940ParseDate(tx.ValueDate)
+	if valueDate.IsZero() {
+		valueDate = bookingDate
+	}
+
+	party, account := counterparty(tx)
+	code := "MSC"
+	if account != nil {
+		code = "TRF"
+	}
+
+	reference := "NONREF"
+	if tx.ReferenceNumber != "" {
+		reference = truncate(mt940Text(tx.ReferenceNumber), 16)
+	}
+
+	line := fmt.Sprintf(":61:%s%s%s%s%s%s", valueDate.Format("060102"), bookingDate.Format("0102"), mark, mt940Amount(amount), "N"+code, reference)
+	if tx.EntryReference != "" {
+		line += "//" + truncate(mt940Text(tx.EntryReference), 16)
+	}
+
+	fmt.Fprintf(w, "%s\r\n", line)
+
+	var details []string
+	if party != nil && party.Name != "" {
+		details = append(details, party.Name)
+	}
+
+	if account != nil && account.IBAN != "" {
+		details = append(details, account.IBAN)
+	}
+
+	details = append(details, tx.RemittanceInformation...)
+	if text := mt940Text(strings.Join(details, " ")); text != "" {
+		fmt.Fprintf(w, ":86:%s\r\n", strings.Join(mt940Wrap(text, 65, 6), "\r\n"))
+	}
+}
+
+// mt940BalanceDate returns the date of balance, falling back to date and the first or last transaction
+// date.
+func mt940BalanceDate(balance *enablebankinggo.BalanceResource, date time.Time, transactions []*enablebankinggo.Transaction, last bool) time.Time {
+	if balance != nil {
+		if d := mt940ParseDate(balance.ReferenceDate); !d.IsZero() {
+			return d
+		}
+	}
+
+	if !date.IsZero() {
+		return date
+	}
+
+	var dates []string
+	for _, tx := range transactions {
+		if d := transactionDate(tx); d != "" {
+			dates = append(dates, d)
+		}
+	}
+
+	if len(dates) > 0 {
+		if last {
+			return mt940ParseDate(slices.Max(dates))
+		}
+
+		return mt940ParseDate(slices.Min(dates))
+	}
+
+	if balance != nil && balance.LastChangeDateTime != nil {
+		return *balance.LastChangeDateTime
+	}
+
+	return time.Now().UTC()
+}
+
+func mt940ParseDate(date string) time.Time {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// mt940Balance formats a balance, e.g. C250131EUR1234,56.
+func mt940Balance(amount *big.Rat, date time.Time, currency string) string {
+	mark := "C"
+	if amount.Sign() < 0 {
+		mark = "D"
+	}
+
+	return mark + date.Format("060102") + currency + mt940Amount(amount)
+}
+
+// mt940Amount formats the absolute value of amount using a decimal comma, e.g. 1234,56.
+func mt940Amount(amount *big.Rat) string {
+	return strings.Replace(new(big.Rat).Abs(amount).FloatString(2), ".", ",", 1)
+}
+
+// mt940Text transliterates s to the SWIFT X character set, replacing other characters with a space.
+func mt940Text(s string) string {
+	s = mt940Transliterations.Replace(s)
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?:().,'+ ", r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// mt940Wrap splits s into at most maxLines lines of at most width characters. Continuation lines never
+// start with ':' or '-', which parsers read as the start of a tag or the end of the message, by splitting
+// earlier or, if s has no other split point, prefixing the line with a space.
+func mt940Wrap(s string, width, maxLines int) []string {
+	var lines []string
+	for len(s) > 0 && len(lines) < maxLines {
+		if len(lines) > 0 && mt940ReservedStart(s[0]) {
+			s = " " + s
+		}
+
+		n := min(width, len(s))
+		split := n
+		for split > 1 && split < len(s) && mt940ReservedStart(s[split]) {
+			split--
+		}
+
+		if split > 1 {
+			n = split
+		}
+
+		lines = append(lines, s[:n])
+		s = s[n:]
+	}
+
+	return lines
+}
+
+// mt940ReservedStart reports whether a continuation line can't start with c.
+func mt940ReservedStart(c byte) bool {
+	return c == ':' || c == '-'
+}
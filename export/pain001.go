@@ -0,0 +1,533 @@
+package export
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// PAIN001Namespace is the XML namespace of the pain.001 version produced by [WritePAIN001].
+const PAIN001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// pain001NotProvided is the identification used where a mandatory identification is unknown.
+const pain001NotProvided = "NOTPROVIDED"
+
+// pain001AmountPattern matches the decimal numbers of amounts, e.g. "100.00".
+var pain001AmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// PAIN001Config represents the configuration of a pain.001 message.
+type PAIN001Config struct {
+	// MessageID is the identification of the message. Defaults to an ID derived from CreatedAt.
+	MessageID string
+
+	// InitiatingParty is the name of the party initiating the payments, e.g. the company. Defaults to the
+	// name of the debtor of the first payment.
+	InitiatingParty string
+
+	// CreatedAt is the creation time of the message. Defaults to now.
+	CreatedAt time.Time
+}
+
+type pain001Document struct {
+	XMLName xml.Name              `xml:"Document"`
+	Xmlns   string                `xml:"xmlns,attr"`
+	Message pain001CstmrCdtTrfIni `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001CstmrCdtTrfIni struct {
+	GroupHeader pain001GrpHdr    `xml:"GrpHdr"`
+	Payments    []*pain001PmtInf `xml:"PmtInf"`
+}
+
+type pain001GrpHdr struct {
+	MessageID       string       `xml:"MsgId"`
+	CreatedAt       string       `xml:"CreDtTm"`
+	NumberOfTxs     int          `xml:"NbOfTxs"`
+	ControlSum      string       `xml:"CtrlSum"`
+	InitiatingParty pain001Party `xml:"InitgPty"`
+}
+
+type pain001PmtInf struct {
+	ID                     string                `xml:"PmtInfId"`
+	Method                 string                `xml:"PmtMtd"`
+	NumberOfTxs            int                   `xml:"NbOfTxs"`
+	ControlSum             string                `xml:"CtrlSum"`
+	PaymentTypeInformation *pain001PmtTpInf      `xml:"PmtTpInf,omitempty"`
+	RequestedExecutionDate pain001Date           `xml:"ReqdExctnDt"`
+	Debtor                 pain001Party          `xml:"Dbtr"`
+	DebtorAccount          pain001Account        `xml:"DbtrAcct"`
+	DebtorAgent            pain001Agent          `xml:"DbtrAgt"`
+	ChargeBearer           string                `xml:"ChrgBr,omitempty"`
+	Transactions           []*pain001CdtTrfTxInf `xml:"CdtTrfTxInf"`
+}
+
+type pain001PmtTpInf struct {
+	InstructionPriority string       `xml:"InstrPrty,omitempty"`
+	ServiceLevel        *pain001Code `xml:"SvcLvl,omitempty"`
+	LocalInstrument     *pain001Code `xml:"LclInstrm,omitempty"`
+	CategoryPurpose     *pain001Code `xml:"CtgyPurp,omitempty"`
+}
+
+type pain001Code struct {
+	Code string `xml:"Cd"`
+}
+
+type pain001Date struct {
+	Date string `xml:"Dt"`
+}
+
+type pain001Party struct {
+	Name          string             `xml:"Nm,omitempty"`
+	PostalAddress *pain001PostalAddr `xml:"PstlAdr,omitempty"`
+}
+
+type pain001PostalAddr struct {
+	Department         string   `xml:"Dept,omitempty"`
+	SubDepartment      string   `xml:"SubDept,omitempty"`
+	StreetName         string   `xml:"StrtNm,omitempty"`
+	BuildingNumber     string   `xml:"BldgNb,omitempty"`
+	PostCode           string   `xml:"PstCd,omitempty"`
+	TownName           string   `xml:"TwnNm,omitempty"`
+	CountrySubDivision string   `xml:"CtrySubDvsn,omitempty"`
+	Country            string   `xml:"Ctry,omitempty"`
+	AddressLines       []string `xml:"AdrLine,omitempty"`
+}
+
+type pain001Account struct {
+	ID       pain001AccountID `xml:"Id"`
+	Currency string           `xml:"Ccy,omitempty"`
+}
+
+type pain001AccountID struct {
+	IBAN  string            `xml:"IBAN,omitempty"`
+	Other *pain001GenericID `xml:"Othr,omitempty"`
+}
+
+type pain001GenericID struct {
+	ID         string       `xml:"Id"`
+	SchemeName *pain001Code `xml:"SchmeNm,omitempty"`
+}
+
+type pain001Agent struct {
+	FinancialInstitution pain001FinInstnID `xml:"FinInstnId"`
+}
+
+type pain001FinInstnID struct {
+	BICFI string            `xml:"BICFI,omitempty"`
+	Name  string            `xml:"Nm,omitempty"`
+	Other *pain001GenericID `xml:"Othr,omitempty"`
+}
+
+type pain001CdtTrfTxInf struct {
+	PaymentID           pain001PmtID            `xml:"PmtId"`
+	Amount              pain001InstructedAmount `xml:"Amt"`
+	UltimateDebtor      *pain001Party           `xml:"UltmtDbtr,omitempty"`
+	CreditorAgent       *pain001Agent           `xml:"CdtrAgt,omitempty"`
+	Creditor            pain001Party            `xml:"Cdtr"`
+	CreditorAccount     pain001Account          `xml:"CdtrAcct"`
+	UltimateCreditor    *pain001Party           `xml:"UltmtCdtr,omitempty"`
+	Purpose             *pain001Code            `xml:"Purp,omitempty"`
+	RegulatoryReporting []*pain001RgltryRptg    `xml:"RgltryRptg,omitempty"`
+	RemittanceInfo      *pain001RmtInf          `xml:"RmtInf,omitempty"`
+}
+
+type pain001PmtID struct {
+	InstructionID string `xml:"InstrId,omitempty"`
+	EndToEndID    string `xml:"EndToEndId"`
+}
+
+type pain001InstructedAmount struct {
+	Amount camt053Amount `xml:"InstdAmt"`
+}
+
+type pain001RgltryRptg struct {
+	Indicator string                  `xml:"DbtCdtRptgInd,omitempty"`
+	Authority *pain001Authority       `xml:"Authrty,omitempty"`
+	Details   []*pain001RgltryDetails `xml:"Dtls,omitempty"`
+}
+
+type pain001Authority struct {
+	Name    string `xml:"Nm,omitempty"`
+	Country string `xml:"Ctry,omitempty"`
+}
+
+type pain001RgltryDetails struct {
+	Type        string         `xml:"Tp,omitempty"`
+	Date        string         `xml:"Dt,omitempty"`
+	Country     string         `xml:"Ctry,omitempty"`
+	Code        string         `xml:"Cd,omitempty"`
+	Amount      *camt053Amount `xml:"Amt,omitempty"`
+	Information []string       `xml:"Inf,omitempty"`
+}
+
+type pain001RmtInf struct {
+	Unstructured []string              `xml:"Ustrd,omitempty"`
+	Structured   *pain001StructuredRmt `xml:"Strd,omitempty"`
+}
+
+type pain001StructuredRmt struct {
+	CreditorReference pain001CreditorRef `xml:"CdtrRefInf"`
+}
+
+type pain001CreditorRef struct {
+	Type      *pain001CreditorRefType `xml:"Tp,omitempty"`
+	Reference string                  `xml:"Ref"`
+}
+
+type pain001CreditorRefType struct {
+	CodeOrProprietary camt053CodeOrProprietary `xml:"CdOrPrtry"`
+}
+
+// WritePAIN001 writes an ISO 20022 pain.001 customer credit transfer initiation message (version 001.09)
+// with the provided payments to w, allowing the payment models to be reused for file-based payment
+// submission to banks.
+//
+// Every payment is exported as a payment information block per requested execution date, defaulting to
+// the creation date. The debtor account of every payment is required. Periodic payments aren't
+// supported.
+func WritePAIN001(w io.Writer, config PAIN001Config, payments ...*enablebankinggo.PaymentRequestResource) error {
+	if len(payments) == 0 {
+		return errors.New("payments cannot be empty")
+	}
+
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = time.Now()
+	}
+
+	if config.MessageID == "" {
+		config.MessageID = "MSG" + config.CreatedAt.UTC().Format("20060102150405")
+	}
+
+	if config.InitiatingParty == "" && payments[0] != nil && payments[0].Debtor != nil {
+		config.InitiatingParty = payments[0].Debtor.Name
+	}
+
+	doc := &pain001Document{Xmlns: PAIN001Namespace}
+	var amounts []string
+	for i, payment := range payments {
+		blocks, err := newPAIN001Payments(config, i, payment)
+		if err != nil {
+			return fmt.Errorf("payment %d: %w", i, err)
+		}
+
+		for _, block := range blocks {
+			for _, tx := range block.Transactions {
+				amounts = append(amounts, tx.Amount.Amount.Value)
+			}
+		}
+
+		doc.Message.Payments = append(doc.Message.Payments, blocks...)
+	}
+
+	doc.Message.GroupHeader = pain001GrpHdr{
+		MessageID:       truncate(config.MessageID, 35),
+		CreatedAt:       config.CreatedAt.Format(time.RFC3339),
+		NumberOfTxs:     len(amounts),
+		ControlSum:      sumAmounts(amounts),
+		InitiatingParty: pain001Party{Name: truncate(config.InitiatingParty, 140)},
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// newPAIN001Payments returns the payment information blocks of payment, one per requested execution date.
+func newPAIN001Payments(config PAIN001Config, index int, payment *enablebankinggo.PaymentRequestResource) ([]*pain001PmtInf, error) {
+	if payment == nil || len(payment.CreditTransferTransaction) == 0 {
+		return nil, errors.New("credit transfers cannot be empty")
+	}
+
+	if payment.DebtorAccount == nil {
+		return nil, errors.New("debtor account cannot be empty")
+	}
+
+	debtorName := config.InitiatingParty
+	if payment.Debtor != nil && payment.Debtor.Name != "" {
+		debtorName = payment.Debtor.Name
+	}
+
+	debtorAgent := pain001Agent{FinancialInstitution: pain001FinInstnID{Other: &pain001GenericID{ID: pain001NotProvided}}}
+	if agent := payment.DebtorAgent; agent != nil && agent.BICFI != "" {
+		debtorAgent.FinancialInstitution = pain001FinInstnID{BICFI: agent.BICFI}
+	}
+
+	var blocks []*pain001PmtInf
+	byDate := map[string]*pain001PmtInf{}
+	for i, tx := range payment.CreditTransferTransaction {
+		if tx == nil {
+			return nil, fmt.Errorf("credit transfer %d cannot be nil", i)
+		}
+
+		if tx.Frequency != "" {
+			return nil, fmt.Errorf("credit transfer %d: periodic payments aren't supported", i)
+		}
+
+		entry, err := newPAIN001Transaction(tx)
+		if err != nil {
+			return nil, fmt.Errorf("credit transfer %d: %w", i, err)
+		}
+
+		date := tx.RequestedExecutionDate
+		if date == "" {
+			date = config.CreatedAt.Format(time.DateOnly)
+		}
+
+		block, ok := byDate[date]
+		if !ok {
+			block = &pain001PmtInf{
+				ID:                     truncate(fmt.Sprintf("%s-%d-%d", config.MessageID, index+1, len(blocks)+1), 35),
+				Method:                 "TRF",
+				PaymentTypeInformation: pain001PaymentTypeInformation(payment.PaymentTypeInformation),
+				RequestedExecutionDate: pain001Date{Date: date},
+				Debtor:                 pain001PartyOf(&enablebankinggo.PartyIdentification{Name: debtorName}),
+				DebtorAccount: pain001Account{
+					ID:       pain001AccountIDOf(payment.DebtorAccount),
+					Currency: payment.DebtorCurrency,
+				},
+				DebtorAgent:  debtorAgent,
+				ChargeBearer: string(payment.ChargeBearer),
+			}
+
+			if payment.Debtor != nil {
+				block.Debtor.PostalAddress = pain001PostalAddressOf(payment.Debtor.PostalAddress)
+			}
+
+			byDate[date] = block
+			blocks = append(blocks, block)
+		}
+
+		block.Transactions = append(block.Transactions, entry)
+	}
+
+	for _, block := range blocks {
+		amounts := make([]string, 0, len(block.Transactions))
+		for _, tx := range block.Transactions {
+			amounts = append(amounts, tx.Amount.Amount.Value)
+		}
+
+		block.NumberOfTxs = len(block.Transactions)
+		block.ControlSum = sumAmounts(amounts)
+	}
+
+	return blocks, nil
+}
+
+func newPAIN001Transaction(tx *enablebankinggo.CreditTransferTransaction) (*pain001CdtTrfTxInf, error) {
+	if tx.InstructedAmount == nil || tx.InstructedAmount.Amount == "" || tx.InstructedAmount.Currency == "" {
+		return nil, errors.New("instructed amount cannot be empty")
+	}
+
+	if tx.Beneficiary == nil || tx.Beneficiary.CreditorAccount == nil {
+		return nil, errors.New("creditor account cannot be empty")
+	}
+
+	amount, err := pain001Amount(tx.InstructedAmount.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("instructed amount: %w", err)
+	}
+
+	entry := &pain001CdtTrfTxInf{
+		PaymentID: pain001PmtID{EndToEndID: pain001NotProvided},
+		Amount: pain001InstructedAmount{
+			Amount: camt053Amount{Currency: tx.InstructedAmount.Currency, Value: amount},
+		},
+		UltimateDebtor:   pain001OptionalParty(tx.UltimateDebtor),
+		Creditor:         pain001PartyOf(tx.Beneficiary.Creditor),
+		CreditorAccount:  pain001Account{ID: pain001AccountIDOf(tx.Beneficiary.CreditorAccount)},
+		UltimateCreditor: pain001OptionalParty(tx.UltimateCreditor),
+	}
+
+	if id := tx.PaymentID; id != nil {
+		entry.PaymentID.InstructionID = truncate(id.InstructionID, 35)
+		if id.EndToEndID != "" {
+			entry.PaymentID.EndToEndID = truncate(id.EndToEndID, 35)
+		}
+	}
+
+	if agent := tx.Beneficiary.CreditorAgent; agent != nil && (agent.BICFI != "" || agent.Name != "") {
+		entry.CreditorAgent = &pain001Agent{FinancialInstitution: pain001FinInstnID{BICFI: agent.BICFI, Name: agent.Name}}
+	}
+
+	if tx.Purpose != "" {
+		entry.Purpose = &pain001Code{Code: string(tx.Purpose)}
+	}
+
+	for _, reporting := range tx.RegulatoryReporting {
+		if reporting == nil {
+			continue
+		}
+
+		rgltryRptg, err := pain001RegulatoryReportingOf(reporting)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.RegulatoryReporting = append(entry.RegulatoryReporting, rgltryRptg)
+	}
+
+	if len(tx.RemittanceInformation) > 0 || tx.ReferenceNumber != "" {
+		entry.RemittanceInfo = &pain001RmtInf{Unstructured: tx.RemittanceInformation}
+		if tx.ReferenceNumber != "" {
+			ref := pain001CreditorRef{Reference: truncate(tx.ReferenceNumber, 35)}
+			switch tx.ReferenceNumberSchema {
+			case enablebankinggo.InternationalReferenceNumberScheme:
+				ref.Type = &pain001CreditorRefType{CodeOrProprietary: camt053CodeOrProprietary{Code: "SCOR"}}
+			case "":
+			default:
+				ref.Type = &pain001CreditorRefType{CodeOrProprietary: camt053CodeOrProprietary{Proprietary: string(tx.ReferenceNumberSchema)}}
+			}
+
+			entry.RemittanceInfo.Structured = &pain001StructuredRmt{CreditorReference: ref}
+		}
+	}
+
+	return entry, nil
+}
+
+func pain001PaymentTypeInformation(info *enablebankinggo.PaymentTypeInformation) *pain001PmtTpInf {
+	if info == nil {
+		return nil
+	}
+
+	result := &pain001PmtTpInf{InstructionPriority: info.InstructionPriority}
+	if info.ServiceLevel != "" {
+		result.ServiceLevel = &pain001Code{Code: string(info.ServiceLevel)}
+	}
+
+	if info.LocalInstrument != "" {
+		result.LocalInstrument = &pain001Code{Code: info.LocalInstrument}
+	}
+
+	if info.CategoryPurpose != "" {
+		result.CategoryPurpose = &pain001Code{Code: info.CategoryPurpose}
+	}
+
+	if *result == (pain001PmtTpInf{}) {
+		return nil
+	}
+
+	return result
+}
+
+func pain001PartyOf(party *enablebankinggo.PartyIdentification) pain001Party {
+	if party == nil {
+		return pain001Party{}
+	}
+
+	return pain001Party{
+		Name:          truncate(party.Name, 140),
+		PostalAddress: pain001PostalAddressOf(party.PostalAddress),
+	}
+}
+
+func pain001OptionalParty(party *enablebankinggo.PartyIdentification) *pain001Party {
+	if party == nil {
+		return nil
+	}
+
+	p := pain001PartyOf(party)
+	return &p
+}
+
+func pain001PostalAddressOf(address *enablebankinggo.PostalAddress) *pain001PostalAddr {
+	if address == nil {
+		return nil
+	}
+
+	return &pain001PostalAddr{
+		Department:         address.Department,
+		SubDepartment:      address.SubDepartment,
+		StreetName:         address.StreetName,
+		BuildingNumber:     address.BuildingNumber,
+		PostCode:           address.PostCode,
+		TownName:           address.TownName,
+		CountrySubDivision: address.CountrySubDivision,
+		Country:            address.Country,
+		AddressLines:       address.AddressLines,
+	}
+}
+
+func pain001AccountIDOf(id *enablebankinggo.AccountIdentification) pain001AccountID {
+	if id.IBAN != "" {
+		return pain001AccountID{IBAN: id.IBAN}
+	}
+
+	if id.Other != nil && id.Other.Identification != "" {
+		other := &pain001GenericID{ID: id.Other.Identification}
+		if id.Other.SchemeName != "" {
+			other.SchemeName = &pain001Code{Code: id.Other.SchemeName}
+		}
+
+		return pain001AccountID{Other: other}
+	}
+
+	return pain001AccountID{Other: &pain001GenericID{ID: pain001NotProvided}}
+}
+
+func pain001RegulatoryReportingOf(reporting *enablebankinggo.RegulatoryReporting) (*pain001RgltryRptg, error) {
+	result := &pain001RgltryRptg{Indicator: string(reporting.DebitCreditReportingIndicator)}
+	if authority := reporting.Authority; authority != nil {
+		result.Authority = &pain001Authority{Name: authority.Name, Country: authority.Country}
+	}
+
+	for _, details := range reporting.Details {
+		if details == nil {
+			continue
+		}
+
+		d := &pain001RgltryDetails{
+			Type:        details.Type,
+			Date:        details.Date,
+			Country:     details.Country,
+			Code:        details.Code,
+			Information: details.Information,
+		}
+
+		if details.Amount != nil {
+			amount, err := pain001Amount(details.Amount.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("regulatory reporting amount: %w", err)
+			}
+
+			d.Amount = &camt053Amount{Currency: details.Amount.Currency, Value: amount}
+		}
+
+		result.Details = append(result.Details, d)
+	}
+
+	return result, nil
+}
+
+// pain001Amount returns amount if it's a positive decimal number, e.g. "100.00". Signed amounts, fractions
+// and exponent notation are rejected, since a credit transfer can't represent the sign of an amount.
+func pain001Amount(amount string) (string, error) {
+	amount = strings.TrimSpace(amount)
+	if !pain001AmountPattern.MatchString(amount) {
+		return "", fmt.Errorf("%q isn't a positive decimal number", amount)
+	}
+
+	if r, ok := new(big.Rat).SetString(amount); !ok || r.Sign() <= 0 {
+		return "", fmt.Errorf("%q isn't a positive decimal number", amount)
+	}
+
+	return amount, nil
+}
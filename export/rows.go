@@ -0,0 +1,280 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// TransactionColumns is the column names of [TransactionRow], in the order of
+// [TransactionRow.Values]. Column names are stable, new columns are only appended.
+var TransactionColumns = []string{
+	"account",
+	"transaction_id",
+	"entry_reference",
+	"status",
+	"booking_date",
+	"value_date",
+	"transaction_date",
+	"credit_debit_indicator",
+	"amount",
+	"signed_amount",
+	"currency",
+	"creditor_name",
+	"creditor_iban",
+	"creditor_account_other",
+	"creditor_agent_bic",
+	"debtor_name",
+	"debtor_iban",
+	"debtor_account_other",
+	"debtor_agent_bic",
+	"counterparty_name",
+	"counterparty_iban",
+	"remittance_information",
+	"reference_number",
+	"reference_number_schema",
+	"bank_transaction_code",
+	"bank_transaction_sub_code",
+	"bank_transaction_description",
+	"merchant_category_code",
+	"exchange_rate",
+	"exchange_rate_unit_currency",
+	"exchange_rate_type",
+	"exchange_rate_contract",
+	"instructed_amount",
+	"instructed_currency",
+	"balance_after_transaction",
+	"balance_after_transaction_currency",
+	"note",
+}
+
+// TransactionRow represents a transaction flattened to a single row of text values, e.g. for CSV
+// files, spreadsheets and reporting tools.
+type TransactionRow struct {
+	Account                         string
+	TransactionID                   string
+	EntryReference                  string
+	Status                          string
+	BookingDate                     string
+	ValueDate                       string
+	TransactionDate                 string
+	CreditDebitIndicator            string
+	Amount                          string
+	SignedAmount                    string
+	Currency                        string
+	CreditorName                    string
+	CreditorIBAN                    string
+	CreditorAccountOther            string
+	CreditorAgentBIC                string
+	DebtorName                      string
+	DebtorIBAN                      string
+	DebtorAccountOther              string
+	DebtorAgentBIC                  string
+	CounterpartyName                string
+	CounterpartyIBAN                string
+	RemittanceInformation           string
+	ReferenceNumber                 string
+	ReferenceNumberSchema           string
+	BankTransactionCode             string
+	BankTransactionSubCode          string
+	BankTransactionDescription      string
+	MerchantCategoryCode            string
+	ExchangeRate                    string
+	ExchangeRateUnitCurrency        string
+	ExchangeRateType                string
+	ExchangeRateContract            string
+	InstructedAmount                string
+	InstructedCurrency              string
+	BalanceAfterTransaction         string
+	BalanceAfterTransactionCurrency string
+	Note                            string
+}
+
+// FlattenTransaction flattens tx of the account with the provided identifier, e.g. IBAN, to a row.
+// Remittance information lines are joined by a space. The signed amount is negative for debits.
+func FlattenTransaction(account string, tx *enablebankinggo.Transaction) *TransactionRow {
+	if tx == nil {
+		return nil
+	}
+
+	row := &TransactionRow{
+		Account:               account,
+		TransactionID:         tx.TransactionID,
+		EntryReference:        tx.EntryReference,
+		Status:                string(tx.Status),
+		BookingDate:           tx.BookingDate,
+		ValueDate:             tx.ValueDate,
+		TransactionDate:       tx.TransactionDate,
+		CreditDebitIndicator:  string(tx.CreditDebitIndicator),
+		RemittanceInformation: strings.Join(tx.RemittanceInformation, " "),
+		ReferenceNumber:       tx.ReferenceNumber,
+		ReferenceNumberSchema: string(tx.ReferenceNumberSchema),
+		MerchantCategoryCode:  tx.MerchantCategoryCode,
+		Note:                  tx.Note,
+	}
+
+	if tx.TransactionAmount != nil {
+		row.Amount, _ = splitAmount(tx.TransactionAmount.Amount)
+		row.SignedAmount = signedAmount(tx).FloatString(ledgerDecimals(tx))
+		row.Currency = tx.TransactionAmount.Currency
+	}
+
+	if tx.Creditor != nil {
+		row.CreditorName = tx.Creditor.Name
+	}
+
+	row.CreditorIBAN, row.CreditorAccountOther = flattenAccount(tx.CreditorAccount)
+	if tx.CreditorAgent != nil {
+		row.CreditorAgentBIC = tx.CreditorAgent.BICFI
+	}
+
+	if tx.Debtor != nil {
+		row.DebtorName = tx.Debtor.Name
+	}
+
+	row.DebtorIBAN, row.DebtorAccountOther = flattenAccount(tx.DebtorAccount)
+	if tx.DebtorAgent != nil {
+		row.DebtorAgentBIC = tx.DebtorAgent.BICFI
+	}
+
+	row.CounterpartyName, row.CounterpartyIBAN = row.CreditorName, row.CreditorIBAN
+	if tx.CreditDebitIndicator == enablebankinggo.CreditCreditDebitIndicator {
+		row.CounterpartyName, row.CounterpartyIBAN = row.DebtorName, row.DebtorIBAN
+	}
+
+	if code := tx.BankTransactionCode; code != nil {
+		row.BankTransactionCode = code.Code
+		row.BankTransactionSubCode = code.SubCode
+		row.BankTransactionDescription = code.Description
+	}
+
+	if rate := tx.ExchangeRate; rate != nil {
+		row.ExchangeRate = rate.ExchangeRate
+		row.ExchangeRateUnitCurrency = rate.UnitCurrency
+		row.ExchangeRateType = string(rate.RateType)
+		row.ExchangeRateContract = rate.ContractIdentification
+		if rate.InstructedAmount != nil {
+			row.InstructedAmount = rate.InstructedAmount.Amount
+			row.InstructedCurrency = rate.InstructedAmount.Currency
+		}
+	}
+
+	if balance := tx.BalanceAfterTransaction; balance != nil {
+		row.BalanceAfterTransaction = balance.Amount
+		row.BalanceAfterTransactionCurrency = balance.Currency
+	}
+
+	return row
+}
+
+// FlattenStatement flattens the transactions of the statement to rows.
+func FlattenStatement(s *Statement) ([]*TransactionRow, error) {
+	err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	account := accountIdentifier(s.Account)
+	rows := make([]*TransactionRow, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		if row := FlattenTransaction(account, tx); row != nil {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// Values returns the values of the row, in the order of [TransactionColumns].
+func (r *TransactionRow) Values() []string {
+	return []string{
+		r.Account,
+		r.TransactionID,
+		r.EntryReference,
+		r.Status,
+		r.BookingDate,
+		r.ValueDate,
+		r.TransactionDate,
+		r.CreditDebitIndicator,
+		r.Amount,
+		r.SignedAmount,
+		r.Currency,
+		r.CreditorName,
+		r.CreditorIBAN,
+		r.CreditorAccountOther,
+		r.CreditorAgentBIC,
+		r.DebtorName,
+		r.DebtorIBAN,
+		r.DebtorAccountOther,
+		r.DebtorAgentBIC,
+		r.CounterpartyName,
+		r.CounterpartyIBAN,
+		r.RemittanceInformation,
+		r.ReferenceNumber,
+		r.ReferenceNumberSchema,
+		r.BankTransactionCode,
+		r.BankTransactionSubCode,
+		r.BankTransactionDescription,
+		r.MerchantCategoryCode,
+		r.ExchangeRate,
+		r.ExchangeRateUnitCurrency,
+		r.ExchangeRateType,
+		r.ExchangeRateContract,
+		r.InstructedAmount,
+		r.InstructedCurrency,
+		r.BalanceAfterTransaction,
+		r.BalanceAfterTransactionCurrency,
+		r.Note,
+	}
+}
+
+// Map returns the values of the row by column name.
+func (r *TransactionRow) Map() map[string]string {
+	values := r.Values()
+	m := make(map[string]string, len(values))
+	for i, column := range TransactionColumns {
+		m[column] = values[i]
+	}
+
+	return m
+}
+
+// WriteTransactionsCSV writes the rows to w as CSV with a header of [TransactionColumns].
+func WriteTransactionsCSV(w io.Writer, rows []*TransactionRow) error {
+	if rows == nil {
+		return errors.New("rows cannot be nil")
+	}
+
+	cw := csv.NewWriter(w)
+	err := cw.Write(TransactionColumns)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = cw.Write(row.Values())
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// flattenAccount returns the IBAN and other identification of account.
+func flattenAccount(account *enablebankinggo.AccountIdentification) (string, string) {
+	if account == nil {
+		return "", ""
+	}
+
+	var other string
+	if account.Other != nil {
+		other = account.Other.Identification
+	}
+
+	return account.IBAN, other
+}
@@ -0,0 +1,216 @@
+// Package export provides converters from Enable Banking account data to file formats consumed by
+// accounting, ERP and treasury systems, and from payment requests to payment initiation files.
+package export
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// Statement represents the account data of a statement period.
+type Statement struct {
+	// ID is the identification of the statement. Defaults to an ID derived from the account and period.
+	ID string
+
+	// Account is the account details.
+	Account *enablebankinggo.AccountResource
+
+	// Balances is the balances of the account, e.g. opening and closing booked balances of the period.
+	Balances []*enablebankinggo.BalanceResource
+
+	// Transactions is the transactions of the period. Only booked transactions are exported.
+	Transactions []*enablebankinggo.Transaction
+
+	// From is the first date of the statement period.
+	From time.Time
+
+	// To is the last date of the statement period.
+	To time.Time
+
+	// CreatedAt is the time the statement is created. Defaults to now.
+	CreatedAt time.Time
+}
+
+func (s *Statement) validate() error {
+	if s == nil {
+		return errors.New("statement cannot be nil")
+	}
+
+	if s.Account == nil {
+		return errors.New("statement.Account cannot be nil")
+	}
+
+	return nil
+}
+
+func (s *Statement) id() string {
+	if s.ID != "" {
+		return s.ID
+	}
+
+	id := accountIdentifier(s.Account)
+	if !s.To.IsZero() {
+		id += "-" + s.To.Format("20060102")
+	}
+
+	return truncate(id, 35)
+}
+
+func (s *Statement) createdAt() time.Time {
+	if s.CreatedAt.IsZero() {
+		return time.Now().UTC()
+	}
+
+	return s.CreatedAt
+}
+
+// bookedTransactions returns the booked transactions of the statement.
+func (s *Statement) bookedTransactions() []*enablebankinggo.Transaction {
+	booked := make([]*enablebankinggo.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		if tx != nil && (tx.Status == enablebankinggo.AccountedTransactionStatus || tx.Status == "") {
+			booked = append(booked, tx)
+		}
+	}
+
+	return booked
+}
+
+// balance returns the first balance of the statement having one of the provided types.
+func (s *Statement) balance(balanceTypes ...enablebankinggo.BalanceType) *enablebankinggo.BalanceResource {
+	for _, balanceType := range balanceTypes {
+		for _, balance := range s.Balances {
+			if balance != nil && balance.BalanceType == balanceType && balance.BalanceAmmount != nil {
+				return balance
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Statement) currency() string {
+	if s.Account.Currency != "" {
+		return s.Account.Currency
+	}
+
+	for _, balance := range s.Balances {
+		if balance != nil && balance.BalanceAmmount != nil {
+			return balance.BalanceAmmount.Currency
+		}
+	}
+
+	return ""
+}
+
+// accountIdentifier returns the IBAN or other identification of the account.
+func accountIdentifier(account *enablebankinggo.AccountResource) string {
+	if account.AccountID != nil {
+		if account.AccountID.IBAN != "" {
+			return account.AccountID.IBAN
+		}
+
+		if account.AccountID.Other != nil {
+			return account.AccountID.Other.Identification
+		}
+	}
+
+	for _, id := range account.AllAccountIDs {
+		if id != nil && id.Identification != "" {
+			return id.Identification
+		}
+	}
+
+	return account.UID
+}
+
+// splitAmount returns the absolute value of amount and whether it's negative.
+func splitAmount(amount string) (string, bool) {
+	amount = strings.TrimSpace(amount)
+	if rest, ok := strings.CutPrefix(amount, "-"); ok {
+		return rest, true
+	}
+
+	return strings.TrimPrefix(amount, "+"), false
+}
+
+// transactionDate returns the booking date of tx, falling back to the value and transaction date.
+func transactionDate(tx *enablebankinggo.Transaction) string {
+	for _, date := range []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate} {
+		if date != "" {
+			return date
+		}
+	}
+
+	return ""
+}
+
+// signedAmount returns the amount of tx, negative for debits.
+func signedAmount(tx *enablebankinggo.Transaction) *big.Rat {
+	if tx.TransactionAmount == nil {
+		return new(big.Rat)
+	}
+
+	amount, negative := splitAmount(tx.TransactionAmount.Amount)
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return new(big.Rat)
+	}
+
+	if tx.CreditDebitIndicator == enablebankinggo.DebitCreditDebitIndicator || (tx.CreditDebitIndicator == "" && negative) {
+		r.Neg(r)
+	}
+
+	return r
+}
+
+// balanceAmount returns the amount of balance.
+func balanceAmount(balance *enablebankinggo.BalanceResource) (*big.Rat, bool) {
+	if balance == nil || balance.BalanceAmmount == nil {
+		return nil, false
+	}
+
+	return new(big.Rat).SetString(strings.TrimSpace(balance.BalanceAmmount.Amount))
+}
+
+// counterparty returns the counterparty of tx, i.e. the creditor of debits and the debtor of credits.
+func counterparty(tx *enablebankinggo.Transaction) (*enablebankinggo.PartyIdentification, *enablebankinggo.AccountIdentification) {
+	if tx.CreditDebitIndicator == enablebankinggo.CreditCreditDebitIndicator {
+		return tx.Debtor, tx.DebtorAccount
+	}
+
+	return tx.Creditor, tx.CreditorAccount
+}
+
+// sumAmounts returns the sum of the decimal amounts, with at least two decimals. Invalid amounts are
+// ignored.
+func sumAmounts(amounts []string) string {
+	sum := new(big.Rat)
+	decimals := 2
+	for _, amount := range amounts {
+		r, ok := new(big.Rat).SetString(amount)
+		if !ok {
+			continue
+		}
+
+		sum.Add(sum, r)
+		if _, fraction, ok := strings.Cut(amount, "."); ok && len(fraction) > decimals {
+			decimals = len(fraction)
+		}
+	}
+
+	return sum.FloatString(decimals)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n])
+}
@@ -0,0 +1,278 @@
+// Package fixtures provides realistic JSON samples of every Enable Banking API response type, for use in
+// tests of code built on the enablebankinggo package and for catching model changes dropping fields.
+package fixtures
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+//go:embed data/*.json
+var data embed.FS
+
+var registry = map[string]func() any{
+	"application":         func() any { return &enablebankinggo.GetApplicationResponse{} },
+	"aspsps":              func() any { return &enablebankinggo.GetASPSPsResponse{} },
+	"start_authorization": func() any { return &enablebankinggo.StartAuthorizationResponse{} },
+	"authorize_session":   func() any { return &enablebankinggo.AuthorizeSessionResponse{} },
+	"delete_session":      func() any { return &enablebankinggo.SuccessResponse{} },
+	"account_details":     func() any { return &enablebankinggo.AccountResource{} },
+	"balances":            func() any { return &enablebankinggo.HalBalances{} },
+	"transactions":        func() any { return &enablebankinggo.HalTransactions{} },
+	"transaction_details": func() any { return &enablebankinggo.Transaction{} },
+	"error":               func() any { return &enablebankinggo.ErrorResponse{} },
+}
+
+func init() {
+	for _, status := range sessionStatuses {
+		registry[sessionFixtureName(status)] = func() any { return &enablebankinggo.GetSessionResponse{} }
+	}
+}
+
+var sessionStatuses = []enablebankinggo.SessionStatus{
+	enablebankinggo.AuthorizedSessionStatus,
+	enablebankinggo.CancelledSessionStatus,
+	enablebankinggo.ClosedSessionStatus,
+	enablebankinggo.ExpiredSessionStatus,
+	enablebankinggo.InvalidSessionStatus,
+	enablebankinggo.PendingAuthorizationSessionStatus,
+	enablebankinggo.ReturnedFromBankSessionStatus,
+	enablebankinggo.RevokedSessionStatus,
+}
+
+func sessionFixtureName(status enablebankinggo.SessionStatus) string {
+	return "session_" + strings.ToLower(string(status))
+}
+
+// Names returns the sorted names of all fixtures.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Bytes returns the raw JSON of the fixture with the provided name.
+func Bytes(name string) ([]byte, error) {
+	if _, ok := registry[name]; !ok {
+		return nil, fmt.Errorf("unknown fixture %q", name)
+	}
+
+	return data.ReadFile(path.Join("data", name+".json"))
+}
+
+// Load decodes the fixture with the provided name into a new value of its response type, e.g.
+// *enablebankinggo.HalTransactions for the transactions fixture.
+func Load(name string) (any, error) {
+	b, err := Bytes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	v := registry[name]()
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %q: %w", name, err)
+	}
+
+	return v, nil
+}
+
+// VerifyRoundTrip decodes the fixture with the provided name into its response type, encodes it again
+// and verifies that every field of the fixture survived the round trip, returning an error describing the
+// first field that was dropped or changed.
+func VerifyRoundTrip(name string) error {
+	b, err := Bytes(name)
+	if err != nil {
+		return err
+	}
+
+	v, err := Load(name)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture %q: %w", name, err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal(b, &want); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		return err
+	}
+
+	return containsJSON(name, want, got)
+}
+
+// containsJSON checks that got contains every value of want. Strings representing the same point in
+// time are considered equal, since time values are encoded using a different layout than the API uses.
+func containsJSON(path string, want, got any) error {
+	switch want := want.(type) {
+	case map[string]any:
+		gotMap, ok := got.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, got)
+		}
+
+		for key, value := range want {
+			gotValue, ok := gotMap[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: field dropped", path, key)
+			}
+
+			if err := containsJSON(path+"."+key, value, gotValue); err != nil {
+				return err
+			}
+		}
+	case []any:
+		gotSlice, ok := got.([]any)
+		if !ok || len(gotSlice) != len(want) {
+			return fmt.Errorf("%s: expected array of length %d, got %v", path, len(want), got)
+		}
+
+		for i := range want {
+			if err := containsJSON(fmt.Sprintf("%s[%d]", path, i), want[i], gotSlice[i]); err != nil {
+				return err
+			}
+		}
+	case string:
+		gotString, ok := got.(string)
+		if ok && (gotString == want || sameTime(want, gotString)) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: expected %q, got %v", path, want, got)
+	default:
+		if want != got {
+			return fmt.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+
+	return nil
+}
+
+func sameTime(a, b string) bool {
+	ta, err := time.Parse(time.RFC3339Nano, a)
+	if err != nil {
+		return false
+	}
+
+	tb, err := time.Parse(time.RFC3339Nano, b)
+	if err != nil {
+		return false
+	}
+
+	return ta.Equal(tb)
+}
+
+// LoadAs decodes the fixture with the provided name into a new value of type T, which must be its response
+// type.
+func LoadAs[T any](name string) (*T, error) {
+	v, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := v.(*T)
+	if !ok {
+		return nil, fmt.Errorf("fixture %q is a %T, not a %T", name, v, t)
+	}
+
+	return t, nil
+}
+
+// Require decodes the fixture with the provided name into a new value of type T, see [LoadAs], failing the
+// test if it can't be loaded.
+func Require[T any](tb testing.TB, name string) *T {
+	tb.Helper()
+
+	v, err := LoadAs[T](name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return v
+}
+
+// mustLoad loads a fixture of the typed accessors, which panic since the embedded fixtures are verified by the
+// tests of this package. Use [Require] in tests.
+func mustLoad[T any](name string) *T {
+	v, err := LoadAs[T](name)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Application returns the GET /application response fixture.
+func Application() *enablebankinggo.GetApplicationResponse {
+	return mustLoad[enablebankinggo.GetApplicationResponse]("application")
+}
+
+// ASPSPs returns the GET /aspsps response fixture.
+func ASPSPs() *enablebankinggo.GetASPSPsResponse {
+	return mustLoad[enablebankinggo.GetASPSPsResponse]("aspsps")
+}
+
+// StartAuthorization returns the POST /auth response fixture.
+func StartAuthorization() *enablebankinggo.StartAuthorizationResponse {
+	return mustLoad[enablebankinggo.StartAuthorizationResponse]("start_authorization")
+}
+
+// AuthorizeSession returns the POST /sessions response fixture.
+func AuthorizeSession() *enablebankinggo.AuthorizeSessionResponse {
+	return mustLoad[enablebankinggo.AuthorizeSessionResponse]("authorize_session")
+}
+
+// Session returns the GET /sessions/{session_id} response fixture of a session with the provided status.
+func Session(status enablebankinggo.SessionStatus) *enablebankinggo.GetSessionResponse {
+	return mustLoad[enablebankinggo.GetSessionResponse](sessionFixtureName(status))
+}
+
+// DeleteSession returns the DELETE /sessions/{session_id} response fixture.
+func DeleteSession() *enablebankinggo.SuccessResponse {
+	return mustLoad[enablebankinggo.SuccessResponse]("delete_session")
+}
+
+// AccountDetails returns the GET /accounts/{account_id}/details response fixture.
+func AccountDetails() *enablebankinggo.AccountResource {
+	return mustLoad[enablebankinggo.AccountResource]("account_details")
+}
+
+// Balances returns the GET /accounts/{account_id}/balances response fixture, covering most balance types.
+func Balances() *enablebankinggo.HalBalances {
+	return mustLoad[enablebankinggo.HalBalances]("balances")
+}
+
+// Transactions returns the GET /accounts/{account_id}/transactions response fixture, covering
+// multi-currency transactions and different transaction statuses.
+func Transactions() *enablebankinggo.HalTransactions {
+	return mustLoad[enablebankinggo.HalTransactions]("transactions")
+}
+
+// TransactionDetails returns the GET /accounts/{account_id}/transactions/{transaction_id} response fixture.
+func TransactionDetails() *enablebankinggo.Transaction {
+	return mustLoad[enablebankinggo.Transaction]("transaction_details")
+}
+
+// Error returns an error response fixture.
+func Error() *enablebankinggo.ErrorResponse {
+	return mustLoad[enablebankinggo.ErrorResponse]("error")
+}
@@ -0,0 +1,78 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+func TestFixturesRegistered(t *testing.T) {
+	files, err := data.ReadDir("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		if !slices.Contains(Names(), name) {
+			t.Errorf("fixture file %s has no registered model", file.Name())
+		}
+	}
+}
+
+func TestFixturesRoundTrip(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			b, err := Bytes(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Fields unknown to the model fail the test, instead of being dropped silently.
+			v := registry[name]()
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(v); err != nil {
+				t.Fatalf("failed to decode fixture: %v", err)
+			}
+
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("failed to encode fixture: %v", err)
+			}
+
+			var want, got any
+			if err := json.Unmarshal(b, &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := containsJSON(name, want, got); err != nil {
+				t.Error(err)
+			}
+
+			if err := VerifyRoundTrip(name); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	resp := Require[enablebankinggo.HalTransactions](t, "transactions")
+	if len(resp.Transactions) == 0 {
+		t.Error("expected transactions")
+	}
+
+	if _, err := LoadAs[enablebankinggo.HalBalances]("transactions"); err == nil {
+		t.Error("expected an error loading a fixture as the wrong type")
+	}
+}
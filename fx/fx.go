@@ -0,0 +1,317 @@
+// Package fx provides valuation of balances of accounts in different currencies in a single reporting
+// currency, using exchange rates of transactions where available and an injectable exchange rate
+// source otherwise.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ErrRateNotFound is returned by rate sources without a rate for a currency pair.
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateSource provides exchange rates.
+type RateSource interface {
+	// Rate returns the number of units of the to currency per unit of the from currency at date.
+	Rate(ctx context.Context, from, to string, date time.Time) (*big.Rat, error)
+}
+
+// RateSourceFunc is an adapter allowing a function to be used as a [RateSource].
+type RateSourceFunc func(ctx context.Context, from, to string, date time.Time) (*big.Rat, error)
+
+// Rate returns f(ctx, from, to, date).
+func (f RateSourceFunc) Rate(ctx context.Context, from, to string, date time.Time) (*big.Rat, error) {
+	return f(ctx, from, to, date)
+}
+
+// StaticRates is a [RateSource] of fixed rates, keyed by currency pair, e.g. EUR/SEK for the number of
+// Swedish kronor per euro. Inverse rates are derived.
+type StaticRates map[string]string
+
+// Rate returns the rate of the currency pair, or its inverse.
+func (r StaticRates) Rate(_ context.Context, from, to string, _ time.Time) (*big.Rat, error) {
+	if rate, ok := r[from+"/"+to]; ok {
+		return parseRate(rate)
+	}
+
+	if rate, ok := r[to+"/"+from]; ok {
+		inverse, err := parseRate(rate)
+		if err != nil {
+			return nil, err
+		}
+
+		return inverse.Inv(inverse), nil
+	}
+
+	return nil, ErrRateNotFound
+}
+
+// TransactionRates is a [RateSource] of the exchange rates of currency exchanged transactions, i.e. the
+// latest rate observed for each currency pair regardless of date.
+type TransactionRates struct {
+	rates map[string]*observedRate
+}
+
+type observedRate struct {
+	rate *big.Rat
+	date string
+}
+
+// RatesFromTransactions returns the exchange rates of the currency exchanged transactions. Rates are
+// derived from the transaction amount and the instructed amount, or from the exchange rate and unit
+// currency.
+func RatesFromTransactions(transactions []*enablebankinggo.Transaction) *TransactionRates {
+	r := &TransactionRates{rates: map[string]*observedRate{}}
+	for _, tx := range transactions {
+		if tx == nil || tx.ExchangeRate == nil || tx.TransactionAmount == nil {
+			continue
+		}
+
+		from, to, rate, ok := transactionRate(tx)
+		if !ok || from == to || rate.Sign() <= 0 {
+			continue
+		}
+
+		date := tx.BookingDate
+		if date == "" {
+			date = tx.ValueDate
+		}
+
+		if current, ok := r.rates[from+"/"+to]; ok && current.date > date {
+			continue
+		}
+
+		r.rates[from+"/"+to] = &observedRate{rate: rate, date: date}
+		r.rates[to+"/"+from] = &observedRate{rate: new(big.Rat).Inv(rate), date: date}
+	}
+
+	return r
+}
+
+// Rate returns the latest observed rate of the currency pair.
+func (r *TransactionRates) Rate(_ context.Context, from, to string, _ time.Time) (*big.Rat, error) {
+	if observed, ok := r.rates[from+"/"+to]; ok {
+		return new(big.Rat).Set(observed.rate), nil
+	}
+
+	return nil, ErrRateNotFound
+}
+
+// transactionRate returns the rate converting the instructed currency to the transaction currency.
+func transactionRate(tx *enablebankinggo.Transaction) (string, string, *big.Rat, bool) {
+	to := tx.TransactionAmount.Currency
+	amount, ok := parseAmount(tx.TransactionAmount.Amount)
+	if !ok {
+		return "", "", nil, false
+	}
+
+	if instructed := tx.ExchangeRate.InstructedAmount; instructed != nil && instructed.Currency != "" {
+		instructedAmount, ok := parseAmount(instructed.Amount)
+		if ok && instructedAmount.Sign() != 0 {
+			return instructed.Currency, to, new(big.Rat).Quo(amount, instructedAmount), true
+		}
+	}
+
+	// The exchange rate is the number of units of the transaction currency per unit currency.
+	unit := tx.ExchangeRate.UnitCurrency
+	rate, err := parseRate(tx.ExchangeRate.ExchangeRate)
+	if unit == "" || unit == to || err != nil {
+		return "", "", nil, false
+	}
+
+	return unit, to, rate, true
+}
+
+// Chain returns a [RateSource] returning the rate of the first source having a rate of the currency
+// pair.
+func Chain(sources ...RateSource) RateSource {
+	return RateSourceFunc(func(ctx context.Context, from, to string, date time.Time) (*big.Rat, error) {
+		for _, source := range sources {
+			if source == nil {
+				continue
+			}
+
+			rate, err := source.Rate(ctx, from, to, date)
+			if err == nil {
+				return rate, nil
+			}
+
+			if !errors.Is(err, ErrRateNotFound) {
+				return nil, err
+			}
+		}
+
+		return nil, ErrRateNotFound
+	})
+}
+
+// Account represents the balances and transactions of an account to value.
+type Account struct {
+	// ID is the identifier of the account.
+	ID string
+
+	// Balances is the balances of the account.
+	Balances []*enablebankinggo.BalanceResource
+
+	// Transactions is the transactions of the account, providing exchange rates, if any.
+	Transactions []*enablebankinggo.Transaction
+}
+
+// Position represents the valuation of the balance of an account.
+type Position struct {
+	// AccountID is the identifier of the account.
+	AccountID string
+
+	// BalanceType is the type of the valued balance.
+	BalanceType enablebankinggo.BalanceType
+
+	// Amount is the balance amount in Currency.
+	Amount string
+
+	// Currency is the currency of the balance.
+	Currency string
+
+	// Rate is the exchange rate to the reporting currency.
+	Rate string
+
+	// Value is the balance amount in the reporting currency, rounded to two decimals.
+	Value string
+
+	// FromTransactions is whether the exchange rate is observed in transactions.
+	FromTransactions bool
+}
+
+// Valuation represents the valuation of the balances of accounts in a reporting currency.
+type Valuation struct {
+	// Currency is the reporting currency.
+	Currency string
+
+	// Date is the valuation date.
+	Date time.Time
+
+	// Total is the sum of the values of the positions, rounded to two decimals.
+	Total string
+
+	// Positions is the valuations of the balances of the accounts, in account order.
+	Positions []*Position
+}
+
+// balanceTypes is the preferred balance types to value, in order.
+var balanceTypes = []enablebankinggo.BalanceType{
+	enablebankinggo.ClosingBookedBalanceType,
+	enablebankinggo.InterimBookedBalanceType,
+	enablebankinggo.ClosingAvailableBalanceType,
+	enablebankinggo.InterimAvailableBalanceType,
+	enablebankinggo.ExpectedBalanceType,
+}
+
+// Value values the balances of the accounts in the reporting currency at date. The closing booked
+// balance of each account is valued, or the first available of interim booked, closing available,
+// interim available and expected balances. Exchange rates observed in the transactions of the accounts
+// are preferred over rates of source, which may be nil.
+func Value(ctx context.Context, currency string, date time.Time, source RateSource, accounts ...*Account) (*Valuation, error) {
+	if currency == "" {
+		return nil, errors.New("currency cannot be empty")
+	}
+
+	var transactions []*enablebankinggo.Transaction
+	for _, account := range accounts {
+		if account != nil {
+			transactions = append(transactions, account.Transactions...)
+		}
+	}
+
+	observed := RatesFromTransactions(transactions)
+	valuation := &Valuation{Currency: currency, Date: date}
+	total := new(big.Rat)
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+
+		balance := preferredBalance(account.Balances)
+		if balance == nil {
+			return nil, fmt.Errorf("account %s: no balance", account.ID)
+		}
+
+		amount, ok := parseAmount(balance.BalanceAmmount.Amount)
+		if !ok {
+			return nil, fmt.Errorf("account %s: invalid balance amount %q", account.ID, balance.BalanceAmmount.Amount)
+		}
+
+		position := &Position{
+			AccountID:   account.ID,
+			BalanceType: balance.BalanceType,
+			Amount:      balance.BalanceAmmount.Amount,
+			Currency:    balance.BalanceAmmount.Currency,
+		}
+
+		rate := big.NewRat(1, 1)
+		if position.Currency != currency {
+			var err error
+			rate, err = observed.Rate(ctx, position.Currency, currency, date)
+			position.FromTransactions = err == nil
+			if err != nil {
+				rate, err = Chain(source).Rate(ctx, position.Currency, currency, date)
+				if err != nil {
+					return nil, fmt.Errorf("account %s: %s/%s: %w", account.ID, position.Currency, currency, err)
+				}
+			}
+		}
+
+		value := new(big.Rat).Mul(amount, rate)
+		total.Add(total, value)
+
+		position.Rate = formatRate(rate)
+		position.Value = value.FloatString(2)
+		valuation.Positions = append(valuation.Positions, position)
+	}
+
+	valuation.Total = total.FloatString(2)
+
+	return valuation, nil
+}
+
+func preferredBalance(balances []*enablebankinggo.BalanceResource) *enablebankinggo.BalanceResource {
+	for _, balanceType := range balanceTypes {
+		for _, balance := range balances {
+			if balance != nil && balance.BalanceType == balanceType && balance.BalanceAmmount != nil {
+				return balance
+			}
+		}
+	}
+
+	for _, balance := range balances {
+		if balance != nil && balance.BalanceAmmount != nil {
+			return balance
+		}
+	}
+
+	return nil
+}
+
+func parseAmount(amount string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strings.TrimSpace(amount))
+}
+
+func parseRate(rate string) (*big.Rat, error) {
+	r, ok := parseAmount(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	return r, nil
+}
+
+// formatRate formats rate with up to 8 decimals, without trailing zeros.
+func formatRate(rate *big.Rat) string {
+	s := strings.TrimRight(rate.FloatString(8), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,240 @@
+// Package gdpr provides data retention and anonymization utilities, anonymizing or purging PSU
+// identifiable fields of stored account data, and an erasure routine deleting sessions via the API.
+package gdpr
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// PseudonymPrefix is the prefix of pseudonyms replacing identifiers.
+const PseudonymPrefix = "pseudonym:"
+
+// Anonymizer anonymizes PSU identifiable fields of account data in place, i.e. names, addresses,
+// contact details, private identifications, account numbers and free text.
+type Anonymizer struct {
+	// Key is the key pseudonymizing names and account numbers using HMAC-SHA256, keeping records of the
+	// same party linkable. Names and account numbers are removed if nil.
+	Key []byte
+
+	// KeepRemittanceInformation keeps the remittance information of transactions, which may contain
+	// personal data.
+	KeepRemittanceInformation bool
+}
+
+// Transaction anonymizes the parties, accounts, remittance information and note of tx.
+func (a *Anonymizer) Transaction(tx *enablebankinggo.Transaction) {
+	if tx == nil {
+		return
+	}
+
+	a.party(tx.Creditor)
+	a.party(tx.Debtor)
+	a.accountID(tx.CreditorAccount)
+	a.accountID(tx.DebtorAccount)
+	a.genericIDs(tx.CreditorAccountAdditionalIdentification)
+	a.genericIDs(tx.DebtorAccountAdditionalIdentification)
+
+	if !a.KeepRemittanceInformation {
+		tx.RemittanceInformation = nil
+	}
+
+	tx.Note = ""
+}
+
+// Transactions anonymizes the transactions.
+func (a *Anonymizer) Transactions(transactions []*enablebankinggo.Transaction) {
+	for _, tx := range transactions {
+		a.Transaction(tx)
+	}
+}
+
+// Account anonymizes the account numbers, name, details and postal address of account.
+func (a *Anonymizer) Account(account *enablebankinggo.AccountResource) {
+	if account == nil {
+		return
+	}
+
+	a.accountID(account.AccountID)
+	a.genericIDs(account.AllAccountIDs)
+	account.Name = a.pseudonym(account.Name)
+	account.Details = ""
+	account.PostalAddress = anonymizeAddress(account.PostalAddress)
+}
+
+// ApplyRetention anonymizes the transactions booked before the retention period ending at now,
+// returning the number of anonymized transactions. Transactions without a date are anonymized.
+func (a *Anonymizer) ApplyRetention(transactions []*enablebankinggo.Transaction, retention time.Duration, now time.Time) int {
+	count := 0
+	for _, tx := range transactions {
+		if tx != nil && Expired(transactionDate(tx), retention, now) {
+			a.Transaction(tx)
+			count++
+		}
+	}
+
+	return count
+}
+
+func (a *Anonymizer) party(party *enablebankinggo.PartyIdentification) {
+	if party == nil {
+		return
+	}
+
+	party.Name = a.pseudonym(party.Name)
+	party.PostalAddress = anonymizeAddress(party.PostalAddress)
+	party.PrivateID = nil
+	party.ContactDetails = nil
+}
+
+func (a *Anonymizer) accountID(accountID *enablebankinggo.AccountIdentification) {
+	if accountID == nil {
+		return
+	}
+
+	accountID.IBAN = a.pseudonym(accountID.IBAN)
+	if accountID.Other != nil {
+		accountID.Other.Identification = a.pseudonym(accountID.Other.Identification)
+	}
+}
+
+func (a *Anonymizer) genericIDs(ids []*enablebankinggo.GenericIdentification) {
+	for _, id := range ids {
+		if id != nil {
+			id.Identification = a.pseudonym(id.Identification)
+		}
+	}
+}
+
+// pseudonym returns the pseudonym of value, or an empty string if no key is set.
+func (a *Anonymizer) pseudonym(value string) string {
+	if value == "" || a.Key == nil {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, a.Key)
+	mac.Write([]byte(value))
+
+	return PseudonymPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// anonymizeAddress returns address keeping only the country, or nil.
+func anonymizeAddress(address *enablebankinggo.PostalAddress) *enablebankinggo.PostalAddress {
+	if address == nil || address.Country == "" {
+		return nil
+	}
+
+	return &enablebankinggo.PostalAddress{Country: address.Country}
+}
+
+// Expired returns whether a record dated date is past the retention period ending at now. Records
+// without a date are expired.
+func Expired(date time.Time, retention time.Duration, now time.Time) bool {
+	return date.IsZero() || date.Before(now.Add(-retention))
+}
+
+// PurgeTransactions returns the transactions booked within the retention period ending at now,
+// dropping older transactions and transactions without a date.
+func PurgeTransactions(transactions []*enablebankinggo.Transaction, retention time.Duration, now time.Time) []*enablebankinggo.Transaction {
+	kept := make([]*enablebankinggo.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx != nil && !Expired(transactionDate(tx), retention, now) {
+			kept = append(kept, tx)
+		}
+	}
+
+	return kept
+}
+
+func transactionDate(tx *enablebankinggo.Transaction) time.Time {
+	for _, date := range []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate} {
+		if t, err := time.Parse(time.DateOnly, date); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// EraseFunc erases the stored data of a session, e.g. accounts and transactions.
+type EraseFunc func(ctx context.Context, sessionID string) error
+
+// Erasure represents the erasure of a session.
+type Erasure struct {
+	// SessionID is the session ID.
+	SessionID string
+
+	// SessionDeleted is whether the session was deleted via the API, or no longer exists.
+	SessionDeleted bool
+
+	// DataErased is whether the stored data was erased.
+	DataErased bool
+
+	// Err is the error of the erasure, if any.
+	Err error
+}
+
+// Erase erases the sessions of a PSU, deleting each session via the API, closing the consent with the
+// ASPSP, and erasing its stored data using erase, if set. Stored data is erased even if the session
+// can't be deleted. Sessions that no longer exist or are already closed are considered deleted. Returns
+// the erasures and the errors joined.
+func Erase(ctx context.Context, client enablebankinggo.UserSessionsClient, erase EraseFunc, sessionIDs ...string) ([]*Erasure, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	erasures := make([]*Erasure, 0, len(sessionIDs))
+	var errs []error
+	for _, sessionID := range sessionIDs {
+		erasure := &Erasure{SessionID: sessionID}
+		erasures = append(erasures, erasure)
+
+		var sessionErr, dataErr error
+		_, err := client.DeleteSession(ctx, sessionID, nil)
+		switch {
+		case err == nil, isGone(err):
+			erasure.SessionDeleted = true
+		default:
+			sessionErr = fmt.Errorf("delete session: %w", err)
+		}
+
+		if erase != nil {
+			err = erase(ctx, sessionID)
+			if err != nil {
+				dataErr = fmt.Errorf("erase data: %w", err)
+			} else {
+				erasure.DataErased = true
+			}
+		}
+
+		erasure.Err = errors.Join(sessionErr, dataErr)
+		if erasure.Err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", sessionID, erasure.Err))
+		}
+	}
+
+	return erasures, errors.Join(errs...)
+}
+
+// isGone returns whether err indicates the session no longer exists or is already closed.
+func isGone(err error) bool {
+	errResp, ok := enablebankinggo.IsErrorResponse(err)
+	if !ok {
+		return false
+	}
+
+	switch errResp.ErrorCode {
+	case enablebankinggo.SessionDoesNotExistErrorCode, enablebankinggo.ClosedSessionErrorCode,
+		enablebankinggo.ExpiredSessionErrorCode, enablebankinggo.RevokedSessionErrorCode:
+		return true
+	}
+
+	return false
+}
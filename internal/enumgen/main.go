@@ -0,0 +1,186 @@
+// Command enumgen generates IsValid methods of enumeration types as switch statements, which are
+// faster than lookups of the description maps on hot decoding and validation paths.
+//
+// Every package level variable named <name>Descriptions of type map[T]string, where T is a type
+// declared in the package, generates an IsValid method of T returning whether a value is a key of the
+// map. The description maps are kept for Description and listing descriptions.
+//
+// Usage:
+//
+//	//go:generate go run <path to>/internal/enumgen -output enumerations_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// enum represents an enumeration type and its values.
+type enum struct {
+	typeName string
+	receiver string
+	values   []string
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package")
+	output := flag.String("output", "enumerations_gen.go", "output file name, relative to dir")
+	flag.Parse()
+
+	if err := run(*dir, *output); err != nil {
+		log.Fatalf("enumgen: %v", err)
+	}
+}
+
+func run(dir, output string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != output
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(pkgs) != 1 {
+		return fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkgName string
+	var files []*ast.File
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	enums, err := findEnums(fset, files)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkgName, enums)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), src, 0o644)
+}
+
+// findEnums returns the enumerations of the description maps of the files, sorted by type name.
+func findEnums(fset *token.FileSet, files []*ast.File) ([]*enum, error) {
+	receivers := map[string]string{}
+	var enums []*enum
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil && len(decl.Recv.List) == 1 && len(decl.Recv.List[0].Names) == 1 {
+					if ident, ok := decl.Recv.List[0].Type.(*ast.Ident); ok {
+						receivers[ident.Name] = decl.Recv.List[0].Names[0].Name
+					}
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+
+				for _, spec := range decl.Specs {
+					e, err := enumOf(fset, spec.(*ast.ValueSpec))
+					if err != nil {
+						return nil, err
+					}
+
+					if e != nil {
+						enums = append(enums, e)
+					}
+				}
+			}
+		}
+	}
+
+	for _, e := range enums {
+		e.receiver = receivers[e.typeName]
+		if e.receiver == "" {
+			e.receiver = string(unicode.ToLower(rune(e.typeName[0])))
+		}
+	}
+
+	slices.SortFunc(enums, func(a, b *enum) int {
+		return strings.Compare(a.typeName, b.typeName)
+	})
+
+	return enums, nil
+}
+
+// enumOf returns the enumeration of a description map variable, or nil if spec isn't one.
+func enumOf(fset *token.FileSet, spec *ast.ValueSpec) (*enum, error) {
+	if len(spec.Names) != 1 || len(spec.Values) != 1 || !strings.HasSuffix(spec.Names[0].Name, "Descriptions") {
+		return nil, nil
+	}
+
+	lit, ok := spec.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return nil, nil
+	}
+
+	mapType, ok := lit.Type.(*ast.MapType)
+	if !ok {
+		return nil, nil
+	}
+
+	keyType, ok := mapType.Key.(*ast.Ident)
+	if !ok || keyType.Name == "string" {
+		return nil, nil
+	}
+
+	e := &enum{typeName: keyType.Name}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("%s: unexpected element of %s", fset.Position(elt.Pos()), spec.Names[0].Name)
+		}
+
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, kv.Key); err != nil {
+			return nil, err
+		}
+
+		e.values = append(e.values, buf.String())
+	}
+
+	return e, nil
+}
+
+func generate(pkgName string, enums []*enum) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by enumgen. DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "package %s\n", pkgName)
+
+	for _, e := range enums {
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "// IsValid checks if the %s is valid.\n", e.typeName)
+		fmt.Fprintf(&buf, "func (%s %s) IsValid() bool {\n", e.receiver, e.typeName)
+		if len(e.values) == 0 {
+			fmt.Fprintln(&buf, "return false\n}")
+			continue
+		}
+
+		fmt.Fprintf(&buf, "switch %s {\ncase %s:\nreturn true\n}\n\nreturn false\n}\n", e.receiver, strings.Join(e.values, ",\n"))
+	}
+
+	return format.Source(buf.Bytes())
+}
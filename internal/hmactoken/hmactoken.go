@@ -0,0 +1,50 @@
+// Package hmactoken provides tokens of a JSON payload signed using HMAC-SHA256, i.e. the base64url
+// encoded payload and signature separated by a dot, used by the connect state and signed verification
+// results.
+package hmactoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a token is malformed or its signature doesn't match.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Sign returns v encoded as JSON and signed with key.
+func Sign(v any, key []byte) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + MAC(encoded, key), nil
+}
+
+// Parse verifies the signature of token and decodes its payload into v. Returns ErrInvalidSignature if
+// the token is malformed or the signature doesn't match, or the error decoding the payload.
+func Parse(token string, key []byte, v any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || len(key) == 0 || !hmac.Equal([]byte(signature), []byte(MAC(encoded, key))) {
+		return ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+// MAC returns the base64url encoded HMAC-SHA256 of data with key.
+func MAC(data string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,32 @@
+// Package observe provides logging and observing of completed API requests, shared by the Enable Banking API
+// client and the control panel API client.
+package observe
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Observer observes completed API requests of information T, i.e. enablebankinggo.RequestObserver.
+type Observer[T any] interface {
+	// ObserveRequest is called once every API request has completed.
+	ObserveRequest(ctx context.Context, info T)
+}
+
+// Request logs a completed request using logger and notifies observer, each if not nil. The request is logged
+// with the attributes of the log value of info, at debug level if successful and at warning level if err isn't
+// nil.
+func Request[T slog.LogValuer](ctx context.Context, logger *slog.Logger, observer Observer[T], info T, err error) {
+	if logger != nil {
+		attrs := info.LogValue().Resolve().Group()
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "Enable Banking API request failed", attrs...)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelDebug, "Enable Banking API request completed", attrs...)
+		}
+	}
+
+	if observer != nil {
+		observer.ObserveRequest(ctx, info)
+	}
+}
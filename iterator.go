@@ -0,0 +1,148 @@
+package enablebankinggo
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// TransactionsIteratorParams represents the parameters of iterating transactions of all pages.
+type TransactionsIteratorParams struct {
+	// Prefetch retrieves the next page using the continuation key while the caller processes the
+	// current page, hiding ASPSP latency of long histories. At most one page is prefetched.
+	Prefetch bool
+
+	// RateLimiter limits the rate of page requests, including prefetched pages, if set.
+	RateLimiter RateLimiter
+}
+
+// IterateTransactions returns an iterator of the transactions of all pages of an account, see
+// [IterateTransactions].
+func (c *APIClient) IterateTransactions(ctx context.Context, accountID string, params *GetAccountTransactionsRequestParams, iterParams *TransactionsIteratorParams) iter.Seq2[*Transaction, error] {
+	return IterateTransactions(ctx, c, accountID, params, iterParams)
+}
+
+// IterateTransactions returns an iterator of the transactions of all pages of an account, following
+// continuation keys. Iteration stops after yielding an error. params isn't modified.
+func IterateTransactions(ctx context.Context, client AccountsDataClient, accountID string, params *GetAccountTransactionsRequestParams, iterParams *TransactionsIteratorParams) iter.Seq2[*Transaction, error] {
+	if iterParams == nil {
+		iterParams = &TransactionsIteratorParams{}
+	}
+
+	req := GetAccountTransactionsRequestParams{}
+	if params != nil {
+		req = *params
+	}
+
+	fetch := func(ctx context.Context, continuationKey string) (*HalTransactions, error) {
+		if iterParams.RateLimiter != nil {
+			if err := iterParams.RateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pageReq := req
+		pageReq.ContinuationKeyQueryParam = continuationKey
+		return client.GetAccountTransactions(ctx, accountID, &pageReq)
+	}
+
+	return func(yield func(*Transaction, error) bool) {
+		if client == nil {
+			yield(nil, errors.New("client cannot be nil"))
+			return
+		}
+
+		pages := sequentialPages
+		if iterParams.Prefetch {
+			pages = prefetchedPages
+		}
+
+		for page, err := range pages(ctx, req.ContinuationKeyQueryParam, fetch) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, tx := range page.Transactions {
+				if !yield(tx, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type pageFetcher func(ctx context.Context, continuationKey string) (*HalTransactions, error)
+
+// sequentialPages returns an iterator of pages, retrieving each page when requested. A nil page ends
+// the iteration.
+func sequentialPages(ctx context.Context, continuationKey string, fetch pageFetcher) iter.Seq2[*HalTransactions, error] {
+	return func(yield func(*HalTransactions, error) bool) {
+		for {
+			page, err := fetch(ctx, continuationKey)
+			if err == nil && page == nil {
+				return
+			}
+
+			if !yield(page, err) || err != nil || page.ContinuationKey == "" {
+				return
+			}
+
+			continuationKey = page.ContinuationKey
+		}
+	}
+}
+
+type pageResult struct {
+	page *HalTransactions
+	err  error
+}
+
+// prefetchedPages returns an iterator of pages, retrieving the next page in the background while the
+// current page is processed. A nil page ends the iteration.
+func prefetchedPages(ctx context.Context, continuationKey string, fetch pageFetcher) iter.Seq2[*HalTransactions, error] {
+	return func(yield func(*HalTransactions, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		results := make(chan pageResult)
+		done := make(chan struct{})
+
+		// Stop the fetcher and wait for it to exit, so no request outlives the iteration.
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		go func() {
+			defer close(done)
+			defer close(results)
+
+			key := continuationKey
+			for {
+				page, err := fetch(ctx, key)
+				if err == nil && page == nil {
+					return
+				}
+
+				select {
+				case results <- pageResult{page: page, err: err}:
+				case <-ctx.Done():
+					return
+				}
+
+				if err != nil || page.ContinuationKey == "" {
+					return
+				}
+
+				key = page.ContinuationKey
+			}
+		}()
+
+		for result := range results {
+			if !yield(result.page, result.err) || result.err != nil {
+				return
+			}
+		}
+	}
+}
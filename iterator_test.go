@@ -0,0 +1,44 @@
+package enablebankinggo
+
+import (
+	"context"
+	"iter"
+	"testing"
+)
+
+func TestPagesEndOnNilPage(t *testing.T) {
+	pages := map[string]func(context.Context, string, pageFetcher) iter.Seq2[*HalTransactions, error]{
+		"sequential": sequentialPages,
+		"prefetched": prefetchedPages,
+	}
+
+	for name, pagesOf := range pages {
+		t.Run(name, func(t *testing.T) {
+			// The second page is nil despite the continuation key of the first.
+			fetch := func(_ context.Context, continuationKey string) (*HalTransactions, error) {
+				if continuationKey == "" {
+					return &HalTransactions{ContinuationKey: "next"}, nil
+				}
+
+				return nil, nil
+			}
+
+			var n int
+			for page, err := range pagesOf(context.Background(), "", fetch) {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if page == nil {
+					t.Fatal("expected no nil page")
+				}
+
+				n++
+			}
+
+			if n != 1 {
+				t.Fatalf("expected 1 page, got %d", n)
+			}
+		})
+	}
+}
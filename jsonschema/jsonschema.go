@@ -0,0 +1,267 @@
+// Package jsonschema provides JSON Schemas describing the request and response models of the
+// enablebankinggo package, allowing consumers to validate stored payloads and to generate clients in
+// other languages consistent with the SDK.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema represents a JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Models is the request and response models of the enablebankinggo package, by name.
+var Models = map[string]any{
+	"AccountResource":            enablebankinggo.AccountResource{},
+	"AuthorizeSessionRequest":    enablebankinggo.AuthorizeSessionRequest{},
+	"AuthorizeSessionResponse":   enablebankinggo.AuthorizeSessionResponse{},
+	"BalanceResource":            enablebankinggo.BalanceResource{},
+	"CreatePaymentRequest":       enablebankinggo.CreatePaymentRequest{},
+	"CreatePaymentResponse":      enablebankinggo.CreatePaymentResponse{},
+	"ErrorResponse":              enablebankinggo.ErrorResponse{},
+	"GetApplicationResponse":     enablebankinggo.GetApplicationResponse{},
+	"GetASPSPsResponse":          enablebankinggo.GetASPSPsResponse{},
+	"GetPaymentResponse":         enablebankinggo.GetPaymentResponse{},
+	"GetSessionResponse":         enablebankinggo.GetSessionResponse{},
+	"HalBalances":                enablebankinggo.HalBalances{},
+	"HalTransactions":            enablebankinggo.HalTransactions{},
+	"StartAuthorizationRequest":  enablebankinggo.StartAuthorizationRequest{},
+	"StartAuthorizationResponse": enablebankinggo.StartAuthorizationResponse{},
+	"SuccessResponse":            enablebankinggo.SuccessResponse{},
+	"Transaction":                enablebankinggo.Transaction{},
+}
+
+// enumerations is the values of enumeration types exposing them.
+var enumerations = map[reflect.Type]func() []string{
+	reflect.TypeOf(enablebankinggo.BalanceType("")):          descriptionKeys(enablebankinggo.BalanceTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.ChargeBearer("")):         descriptionKeys(enablebankinggo.ChargeBearerDescriptions),
+	reflect.TypeOf(enablebankinggo.CreditDebitIndicator("")): descriptionKeys(enablebankinggo.CreditDebitIndicatorDescriptions),
+	reflect.TypeOf(enablebankinggo.ExecutionRule("")):        descriptionKeys(enablebankinggo.ExecutionRuleDescriptions),
+	reflect.TypeOf(enablebankinggo.FrequencyCode("")):        descriptionKeys(enablebankinggo.FrequencyCodeDescriptions),
+	reflect.TypeOf(enablebankinggo.PaymentStatus("")):        descriptionKeys(enablebankinggo.PaymentStatusDescriptions),
+	reflect.TypeOf(enablebankinggo.PSUType("")):              descriptionKeys(enablebankinggo.PSUTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.PurposeCode("")):          descriptionKeys(enablebankinggo.PurposeCodeDescriptions),
+	reflect.TypeOf(enablebankinggo.RateType("")):             descriptionKeys(enablebankinggo.RateTypeDescriptions),
+	reflect.TypeOf(enablebankinggo.Service("")):              descriptionKeys(enablebankinggo.ServiceDescriptions),
+	reflect.TypeOf(enablebankinggo.ServiceLevel("")):         descriptionKeys(enablebankinggo.ServiceLevelDescriptions),
+	reflect.TypeOf(enablebankinggo.TransactionStatus("")):    descriptionKeys(enablebankinggo.TransactionStatusDescriptions),
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// For generates the JSON Schema of v, i.e. a struct or pointer to a struct, describing its JSON
+// encoding. Nested structs are described in $defs and referenced by type name. Fields without omitempty
+// are required.
+func For(v any) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("v must be a struct or a pointer to a struct")
+	}
+
+	g := &generator{defs: map[string]*Schema{}}
+	schema := g.structSchema(t)
+	schema.Schema = Draft
+	schema.Title = t.Name()
+	if len(g.defs) > 0 {
+		schema.Defs = g.defs
+	}
+
+	return schema, nil
+}
+
+// All generates the JSON Schemas of all [Models], by name.
+func All() (map[string]*Schema, error) {
+	schemas := make(map[string]*Schema, len(Models))
+	for name, model := range Models {
+		schema, err := For(model)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		schemas[name] = schema
+	}
+
+	return schemas, nil
+}
+
+// WriteAll writes the JSON Schemas of all [Models] to dir, one <name>.schema.json file per model.
+func WriteAll(dir string) error {
+	schemas, err := All()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	for name, schema := range schemas {
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name+".schema.json"), append(b, '\n'), 0o644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		omitempty := slices.Contains(strings.Split(opts, ","), "omitempty")
+		asString := slices.Contains(strings.Split(opts, ","), "string")
+
+		property := g.typeSchema(field.Type, !omitempty)
+		if asString {
+			property = &Schema{Type: "string"}
+		}
+
+		schema.Properties[name] = property
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	slices.Sort(schema.Required)
+
+	return schema
+}
+
+// typeSchema returns the schema of t. If nullable, nil pointers, slices and maps are encoded as null.
+func (g *generator) typeSchema(t reflect.Type, nullable bool) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.withNull(g.typeSchema(t.Elem(), false), nullable)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return g.withNull(&Schema{Type: "string", Format: "byte"}, nullable && t.Kind() == reflect.Slice)
+		}
+
+		return g.withNull(&Schema{Type: "array", Items: g.typeSchema(t.Elem(), false)}, nullable && t.Kind() == reflect.Slice)
+	case reflect.Map:
+		return g.withNull(&Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem(), false)}, nullable)
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		schema := &Schema{Type: "string"}
+		if values, ok := enumerations[t]; ok {
+			for _, value := range values() {
+				schema.Enum = append(schema.Enum, value)
+			}
+		}
+
+		return schema
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+
+		if _, ok := g.defs[t.Name()]; !ok && t.Name() != "" {
+			// Registered before generating, supporting recursive types.
+			g.defs[t.Name()] = &Schema{}
+			*g.defs[t.Name()] = *g.structSchema(t)
+		}
+
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	}
+
+	return &Schema{}
+}
+
+func (g *generator) withNull(schema *Schema, nullable bool) *Schema {
+	if !nullable {
+		return schema
+	}
+
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+
+	if schemaType, ok := schema.Type.(string); ok {
+		schema.Type = []string{schemaType, "null"}
+	}
+
+	return schema
+}
+
+func descriptionKeys[K ~string](descriptions func() map[K]string) func() []string {
+	return func() []string {
+		keys := make([]string, 0, len(descriptions()))
+		for key := range descriptions() {
+			keys = append(keys, string(key))
+		}
+		slices.Sort(keys)
+
+		return keys
+	}
+}
@@ -0,0 +1,178 @@
+// Package mocks provides mock implementations of the enablebankinggo client interfaces for unit testing
+// code built on the library.
+//
+// Every mock has a function field per interface method. Calling a method whose function field is nil
+// returns [ErrNotImplemented].
+package mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ErrNotImplemented is returned when calling a mocked method without a configured function.
+var ErrNotImplemented = errors.New("mocks: method not implemented")
+
+// UserSessionsClient is a mock implementation of [enablebankinggo.UserSessionsClient].
+type UserSessionsClient struct {
+	StartAuthorizationFunc func(ctx context.Context, req *enablebankinggo.StartAuthorizationRequest) (*enablebankinggo.StartAuthorizationResponse, error)
+	AuthorizeSessionFunc   func(ctx context.Context, req *enablebankinggo.AuthorizeSessionRequest) (*enablebankinggo.AuthorizeSessionResponse, error)
+	GetSessionFunc         func(ctx context.Context, sessionID string) (*enablebankinggo.GetSessionResponse, error)
+	DeleteSessionFunc      func(ctx context.Context, sessionID string, params *enablebankinggo.DeleteSessionRequestParams) (*enablebankinggo.SuccessResponse, error)
+}
+
+var _ enablebankinggo.UserSessionsClient = (*UserSessionsClient)(nil)
+
+// StartAuthorization calls StartAuthorizationFunc.
+func (m *UserSessionsClient) StartAuthorization(ctx context.Context, req *enablebankinggo.StartAuthorizationRequest) (*enablebankinggo.StartAuthorizationResponse, error) {
+	if m.StartAuthorizationFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.StartAuthorizationFunc(ctx, req)
+}
+
+// AuthorizeSession calls AuthorizeSessionFunc.
+func (m *UserSessionsClient) AuthorizeSession(ctx context.Context, req *enablebankinggo.AuthorizeSessionRequest) (*enablebankinggo.AuthorizeSessionResponse, error) {
+	if m.AuthorizeSessionFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.AuthorizeSessionFunc(ctx, req)
+}
+
+// GetSession calls GetSessionFunc.
+func (m *UserSessionsClient) GetSession(ctx context.Context, sessionID string) (*enablebankinggo.GetSessionResponse, error) {
+	if m.GetSessionFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetSessionFunc(ctx, sessionID)
+}
+
+// DeleteSession calls DeleteSessionFunc.
+func (m *UserSessionsClient) DeleteSession(ctx context.Context, sessionID string, params *enablebankinggo.DeleteSessionRequestParams) (*enablebankinggo.SuccessResponse, error) {
+	if m.DeleteSessionFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.DeleteSessionFunc(ctx, sessionID, params)
+}
+
+// AccountsDataClient is a mock implementation of [enablebankinggo.AccountsDataClient].
+type AccountsDataClient struct {
+	GetAccountDetailsFunc      func(ctx context.Context, accountID string, params *enablebankinggo.GetAccountDetailsRequestParams) (*enablebankinggo.AccountResource, error)
+	GetAccountBalancesFunc     func(ctx context.Context, accountID string, params *enablebankinggo.GetAccountBalancesRequestParams) (*enablebankinggo.HalBalances, error)
+	GetAccountTransactionsFunc func(ctx context.Context, accountID string, params *enablebankinggo.GetAccountTransactionsRequestParams) (*enablebankinggo.HalTransactions, error)
+	GetTransactionDetailsFunc  func(ctx context.Context, accountID string, transactionID string, params *enablebankinggo.GetTransactionDetailsRequestParams) (*enablebankinggo.Transaction, error)
+}
+
+var _ enablebankinggo.AccountsDataClient = (*AccountsDataClient)(nil)
+
+// GetAccountDetails calls GetAccountDetailsFunc.
+func (m *AccountsDataClient) GetAccountDetails(ctx context.Context, accountID string, params *enablebankinggo.GetAccountDetailsRequestParams) (*enablebankinggo.AccountResource, error) {
+	if m.GetAccountDetailsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetAccountDetailsFunc(ctx, accountID, params)
+}
+
+// GetAccountBalances calls GetAccountBalancesFunc.
+func (m *AccountsDataClient) GetAccountBalances(ctx context.Context, accountID string, params *enablebankinggo.GetAccountBalancesRequestParams) (*enablebankinggo.HalBalances, error) {
+	if m.GetAccountBalancesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetAccountBalancesFunc(ctx, accountID, params)
+}
+
+// GetAccountTransactions calls GetAccountTransactionsFunc.
+func (m *AccountsDataClient) GetAccountTransactions(ctx context.Context, accountID string, params *enablebankinggo.GetAccountTransactionsRequestParams) (*enablebankinggo.HalTransactions, error) {
+	if m.GetAccountTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetAccountTransactionsFunc(ctx, accountID, params)
+}
+
+// GetTransactionDetails calls GetTransactionDetailsFunc.
+func (m *AccountsDataClient) GetTransactionDetails(ctx context.Context, accountID string, transactionID string, params *enablebankinggo.GetTransactionDetailsRequestParams) (*enablebankinggo.Transaction, error) {
+	if m.GetTransactionDetailsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetTransactionDetailsFunc(ctx, accountID, transactionID, params)
+}
+
+// MiscClient is a mock implementation of [enablebankinggo.MiscClient].
+type MiscClient struct {
+	GetApplicationFunc func(ctx context.Context) (*enablebankinggo.GetApplicationResponse, error)
+	GetASPSPsFunc      func(ctx context.Context, params *enablebankinggo.GetASPSPsRequestParams) (*enablebankinggo.GetASPSPsResponse, error)
+}
+
+var _ enablebankinggo.MiscClient = (*MiscClient)(nil)
+
+// GetApplication calls GetApplicationFunc.
+func (m *MiscClient) GetApplication(ctx context.Context) (*enablebankinggo.GetApplicationResponse, error) {
+	if m.GetApplicationFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetApplicationFunc(ctx)
+}
+
+// GetASPSPs calls GetASPSPsFunc.
+func (m *MiscClient) GetASPSPs(ctx context.Context, params *enablebankinggo.GetASPSPsRequestParams) (*enablebankinggo.GetASPSPsResponse, error) {
+	if m.GetASPSPsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetASPSPsFunc(ctx, params)
+}
+
+// PaymentsClient is a mock implementation of [enablebankinggo.PaymentsClient].
+type PaymentsClient struct {
+	CreatePaymentFunc func(ctx context.Context, req *enablebankinggo.CreatePaymentRequest) (*enablebankinggo.CreatePaymentResponse, error)
+	GetPaymentFunc    func(ctx context.Context, paymentID string) (*enablebankinggo.GetPaymentResponse, error)
+	DeletePaymentFunc func(ctx context.Context, paymentID string, params *enablebankinggo.DeletePaymentRequestParams) (*enablebankinggo.SuccessResponse, error)
+}
+
+var _ enablebankinggo.PaymentsClient = (*PaymentsClient)(nil)
+
+// CreatePayment calls CreatePaymentFunc.
+func (m *PaymentsClient) CreatePayment(ctx context.Context, req *enablebankinggo.CreatePaymentRequest) (*enablebankinggo.CreatePaymentResponse, error) {
+	if m.CreatePaymentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.CreatePaymentFunc(ctx, req)
+}
+
+// GetPayment calls GetPaymentFunc.
+func (m *PaymentsClient) GetPayment(ctx context.Context, paymentID string) (*enablebankinggo.GetPaymentResponse, error) {
+	if m.GetPaymentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.GetPaymentFunc(ctx, paymentID)
+}
+
+// DeletePayment calls DeletePaymentFunc.
+func (m *PaymentsClient) DeletePayment(ctx context.Context, paymentID string, params *enablebankinggo.DeletePaymentRequestParams) (*enablebankinggo.SuccessResponse, error) {
+	if m.DeletePaymentFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.DeletePaymentFunc(ctx, paymentID, params)
+}
+
+// Client is a mock implementing all the enablebankinggo client interfaces.
+type Client struct {
+	UserSessionsClient
+	AccountsDataClient
+	MiscClient
+	PaymentsClient
+}
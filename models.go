@@ -2,6 +2,7 @@ package enablebankinggo
 
 import (
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -42,6 +43,23 @@ type AccountIdentification struct {
 	Other *GenericIdentification `json:"other,omitempty"`
 }
 
+// NewIBANAccountIdentification returns the account identification of an IBAN, e.g. to preselect the
+// debtor account of a payment. Spaces are removed.
+func NewIBANAccountIdentification(iban string) *AccountIdentification {
+	return &AccountIdentification{IBAN: strings.ReplaceAll(iban, " ", "")}
+}
+
+// NewBBANAccountIdentification returns the account identification of a Basic Bank Account Number (BBAN),
+// e.g. to preselect the debtor account of a payment. Spaces are removed.
+func NewBBANAccountIdentification(bban string) *AccountIdentification {
+	return &AccountIdentification{
+		Other: &GenericIdentification{
+			Identification: strings.ReplaceAll(bban, " ", ""),
+			SchemeName:     string(BasicBankAccountNumberScheme),
+		},
+	}
+}
+
 // AccountResource represents an authorized account.
 type AccountResource struct {
 	// AccountID is the primary account identifier.
@@ -167,6 +185,9 @@ type ASPSPData struct {
 
 	// Group is the group which the ASPSP belongs to, if available.
 	Group *ASPSPGroup `json:"group,omitempty"`
+
+	// Payments is the list of payments supported by the ASPSP, by payment type and PSU type.
+	Payments []*SupportedPayment `json:"payments,omitempty"`
 }
 
 // ASPSPGroup represents group which the ASPSP belongs to.
@@ -237,6 +258,18 @@ type BankTransactionCode struct {
 	SubCode string `json:"sub_code,omitempty"`
 }
 
+// Beneficiary represents the creditor of a credit transfer, i.e. the party receiving the payment.
+type Beneficiary struct {
+	// CreditorAgent is the financial institution servicing the account of the creditor.
+	CreditorAgent *FinancialInstitutionIdentification `json:"creditor_agent,omitempty"`
+
+	// Creditor is the party to which the amount of money is due.
+	Creditor *PartyIdentification `json:"creditor,omitempty"`
+
+	// CreditorAccount is the account of the creditor, to which the amount of money is credited.
+	CreditorAccount *AccountIdentification `json:"creditor_account"`
+}
+
 // ClearingSystemMemberIdentification represents information used to identify a member within a clearing system.
 type ClearingSystemMemberIdentification struct {
 	// ClearingSystemID is the specification of a pre-agreed offering between clearing agents or the
@@ -275,6 +308,60 @@ type Credential struct {
 	Template string `json:"template,omitempty"`
 }
 
+// CreditTransferTransaction represents a single credit transfer of a payment request.
+type CreditTransferTransaction struct {
+	// InstructedAmount is the amount of money to be moved between the debtor and creditor, in the
+	// currency as ordered by the initiating party.
+	InstructedAmount *AmountType `json:"instructed_amount"`
+
+	// Beneficiary is the creditor of the credit transfer.
+	Beneficiary *Beneficiary `json:"beneficiary"`
+
+	// PaymentID is the identification of the credit transfer, assigned by the initiating party and
+	// passed on through the payment chain.
+	PaymentID *PaymentIdentification `json:"payment_id,omitempty"`
+
+	// RequestedExecutionDate is the date (YYYY-MM-DD) at which the initiating party requests the
+	// payment to be executed. If not provided, the payment is executed as soon as possible. For periodic
+	// payments, it's the date of the first execution.
+	RequestedExecutionDate string `json:"requested_execution_date,omitempty"`
+
+	// Frequency is the frequency of a periodic payment, e.g. a standing order. Only set for periodic
+	// payments.
+	Frequency FrequencyCode `json:"frequency,omitempty"`
+
+	// EndDate is the date (YYYY-MM-DD) of the last execution of a periodic payment. If not provided, the
+	// periodic payment is executed until cancelled.
+	EndDate string `json:"end_date,omitempty"`
+
+	// ExecutionRule is the rule for executing a periodic payment falling on a non-banking day.
+	ExecutionRule ExecutionRule `json:"execution_rule,omitempty"`
+
+	// UltimateDebtor is the ultimate party that owes an amount of money to the (ultimate) creditor.
+	UltimateDebtor *PartyIdentification `json:"ultimate_debtor,omitempty"`
+
+	// UltimateCreditor is the ultimate party to which an amount of money is due.
+	UltimateCreditor *PartyIdentification `json:"ultimate_creditor,omitempty"`
+
+	// RemittanceInformation is the unstructured remittance information, i.e. the message to the
+	// creditor, enabling the matching of the payment with the items it's intended to settle.
+	RemittanceInformation []string `json:"remittance_information,omitempty"`
+
+	// ReferenceNumber is the structured creditor reference, e.g. an ISO 11649 reference or a local
+	// reference number such as an OCR number.
+	ReferenceNumber string `json:"reference_number,omitempty"`
+
+	// ReferenceNumberSchema indicates what kind of reference number is used.
+	ReferenceNumberSchema ReferenceNumberScheme `json:"reference_number_schema,omitempty"`
+
+	// Purpose is the underlying reason of the credit transfer, e.g. SALA for salary payments.
+	Purpose PurposeCode `json:"purpose,omitempty"`
+
+	// RegulatoryReporting is the information needed due to regulatory and statutory requirements, e.g.
+	// for crossborder and corporate payments.
+	RegulatoryReporting []*RegulatoryReporting `json:"regulatory_reporting,omitempty"`
+}
+
 // ExchangeRate provides details on the currency exchange rate and contract.
 type ExchangeRate struct {
 	// UnitCurrency is the ISO 4217 code of the currency, in which the rate of exchange is expressed
@@ -358,6 +445,64 @@ type PartyIdentification struct {
 	ContactDetails *ContactDetails `json:"contact_details,omitempty"`
 }
 
+// PaymentRequestResource represents the details of a payment, i.e. the debtor and the credit
+// transfers to initiate.
+type PaymentRequestResource struct {
+	// CreditTransferTransaction is the credit transfers of the payment. Single payments have exactly
+	// one credit transfer, bulk payments one or more.
+	CreditTransferTransaction []*CreditTransferTransaction `json:"credit_transfer_transaction"`
+
+	// Debtor is the party that owes an amount of money to the creditor.
+	Debtor *PartyIdentification `json:"debtor,omitempty"`
+
+	// DebtorAccount is the account of the debtor, to which a debit entry will be made. If not
+	// provided, the PSU selects the account during authorization.
+	DebtorAccount *AccountIdentification `json:"debtor_account,omitempty"`
+
+	// DebtorAgent is the financial institution servicing the account of the debtor.
+	DebtorAgent *FinancialInstitutionIdentification `json:"debtor_agent,omitempty"`
+
+	// DebtorCurrency is the ISO 4217 code of the currency of the debtor account, if the account
+	// identification is shared by accounts in different currencies.
+	DebtorCurrency string `json:"debtor_currency,omitempty"`
+
+	// PaymentTypeInformation is the set of elements used to further specify the type of the payment.
+	PaymentTypeInformation *PaymentTypeInformation `json:"payment_type_information,omitempty"`
+
+	// ChargeBearer specifies which party bears the charges of the payment, e.g. for crossborder
+	// payments.
+	ChargeBearer ChargeBearer `json:"charge_bearer,omitempty"`
+}
+
+// PaymentIdentification represents the identification of a credit transfer.
+type PaymentIdentification struct {
+	// InstructionID is the unique identification of the instruction, assigned by the instructing
+	// party for the instructed party.
+	InstructionID string `json:"instruction_id,omitempty"`
+
+	// EndToEndID is the unique identification assigned by the initiating party to unambiguously
+	// identify the transaction. It's passed on, unchanged, throughout the entire end-to-end chain.
+	EndToEndID string `json:"end_to_end_id,omitempty"`
+}
+
+// PaymentTypeInformation represents the set of elements used to further specify the type of a payment.
+type PaymentTypeInformation struct {
+	// InstructionPriority indicates the urgency or order of importance that the instructing party
+	// would like the instructed party to apply to the processing of the instruction, e.g. NORM or HIGH.
+	InstructionPriority string `json:"instruction_priority,omitempty"`
+
+	// ServiceLevel is the agreement under which or rules under which the payment should be processed.
+	ServiceLevel ServiceLevel `json:"service_level,omitempty"`
+
+	// CategoryPurpose specifies the high level purpose of the payment based on a set of pre-defined
+	// categories, e.g. SALA for salary payments.
+	CategoryPurpose string `json:"category_purpose,omitempty"`
+
+	// LocalInstrument specifies a local instrument, local clearing option and/or further qualifies the
+	// service or service level.
+	LocalInstrument string `json:"local_instrument,omitempty"`
+}
+
 // PostalAddress represents a postal address.
 type PostalAddress struct {
 	// AddressType is the type of address.
@@ -394,6 +539,51 @@ type PostalAddress struct {
 	AddressLines []string `json:"address_lines,omitempty"`
 }
 
+// RegulatoryAuthority represents an entity requiring regulatory reporting information.
+type RegulatoryAuthority struct {
+	// Name is the name of the authority.
+	Name string `json:"name,omitempty"`
+
+	// Country is the two-letter ISO 3166 code of the country of the authority.
+	Country string `json:"country,omitempty"`
+}
+
+// RegulatoryReporting represents information needed due to regulatory and statutory requirements.
+type RegulatoryReporting struct {
+	// DebitCreditReportingIndicator identifies whether the reporting applies to the debit side, the credit
+	// side or both.
+	DebitCreditReportingIndicator RegulatoryReportingIndicator `json:"debit_credit_reporting_indicator,omitempty"`
+
+	// Authority is the entity requiring the regulatory reporting information.
+	Authority *RegulatoryAuthority `json:"authority,omitempty"`
+
+	// Details is the details of the regulatory reporting information.
+	Details []*RegulatoryReportingDetails `json:"details,omitempty"`
+}
+
+// RegulatoryReportingDetails represents the details of regulatory reporting information.
+type RegulatoryReportingDetails struct {
+	// Type specifies the type of the information supplied in the regulatory reporting details.
+	Type string `json:"type,omitempty"`
+
+	// Date is the date (YYYY-MM-DD) related to the specified type of regulatory reporting details.
+	Date string `json:"date,omitempty"`
+
+	// Country is the two-letter ISO 3166 code of the country related to the specified type of regulatory
+	// reporting details.
+	Country string `json:"country,omitempty"`
+
+	// Code specifies the nature, purpose and reason for the transaction to be reported for regulatory and
+	// statutory requirements, e.g. a central bank reporting code.
+	Code string `json:"code,omitempty"`
+
+	// Amount is the amount of money to be reported for regulatory and statutory requirements.
+	Amount *AmountType `json:"amount,omitempty"`
+
+	// Information is additional details that cater for specific domestic regulatory requirements.
+	Information []string `json:"information,omitempty"`
+}
+
 // SessionAccount represents account data stored in the user session.
 type SessionAccount struct {
 	// UID is the account identificator within the session.
@@ -408,6 +598,62 @@ type SessionAccount struct {
 	IdentificationHashes []string `json:"identification_hashes"`
 }
 
+// SupportedPayment represents a payment supported by an ASPSP, i.e. the capabilities and
+// requirements of a payment type for a PSU type.
+type SupportedPayment struct {
+	// PaymentType is the type of the payment.
+	PaymentType PaymentType `json:"payment_type"`
+
+	// PSUType is the PSU type the payment is supported for.
+	PSUType PSUType `json:"psu_type"`
+
+	// Currencies is the list of ISO 4217 codes of the currencies supported for the payment.
+	Currencies []string `json:"currencies,omitempty"`
+
+	// MaxTransactions is the maximum number of credit transfers of a payment.
+	MaxTransactions int `json:"max_transactions,omitempty"`
+
+	// DebtorAccountRequired indicates whether the debtor account must be provided.
+	DebtorAccountRequired bool `json:"debtor_account_required"`
+
+	// DebtorAccountSchemas is the list of identification schemes supported for the debtor account.
+	DebtorAccountSchemas []SchemeName `json:"debtor_account_schemas,omitempty"`
+
+	// CreditorAccountSchemas is the list of identification schemes supported for the creditor account.
+	CreditorAccountSchemas []SchemeName `json:"creditor_account_schemas,omitempty"`
+
+	// ChargeBearerValues is the list of supported charge bearers.
+	ChargeBearerValues []ChargeBearer `json:"charge_bearer_values,omitempty"`
+
+	// CreditorNameRequired indicates whether the creditor name must be provided.
+	CreditorNameRequired bool `json:"creditor_name_required"`
+
+	// CreditorCountryRequired indicates whether the country of the creditor postal address must be provided.
+	CreditorCountryRequired bool `json:"creditor_country_required"`
+
+	// CreditorPostalAddressRequired indicates whether the creditor postal address must be provided.
+	CreditorPostalAddressRequired bool `json:"creditor_postal_address_required"`
+
+	// RemittanceInfoRequired indicates whether the remittance information must be provided.
+	RemittanceInfoRequired bool `json:"remittance_info_required"`
+
+	// RemittanceInfoMaxLength is the maximum length of the remittance information.
+	RemittanceInfoMaxLength int `json:"remittance_info_max_length,omitempty"`
+
+	// ReferenceNumberSupported indicates whether structured reference numbers are supported.
+	ReferenceNumberSupported bool `json:"reference_number_supported"`
+
+	// RequestedExecutionDateSupported indicates whether future-dated payments are supported.
+	RequestedExecutionDateSupported bool `json:"requested_execution_date_supported"`
+
+	// RequestedExecutionDateMaxPeriod is the maximum number of days the requested execution date can be
+	// in the future.
+	RequestedExecutionDateMaxPeriod int `json:"requested_execution_date_max_period,omitempty"`
+
+	// AllowedAuthMethods is the list of names of the authentication methods allowed for the payment.
+	AllowedAuthMethods []string `json:"allowed_auth_methods,omitempty"`
+}
+
 // Transaction represents an account transaction resource.
 type Transaction struct {
 	// EntryReference is the unique transaction identifier provided by ASPSP. This identifier is both unique
@@ -495,6 +741,13 @@ type Transaction struct {
 	// ExchangeRate provides details on the currency exchange rate and contract.
 	ExchangeRate *ExchangeRate `json:"exchange_rate,omitempty"`
 
+	// Purpose is the underlying reason of the transaction, if provided by the ASPSP.
+	Purpose PurposeCode `json:"purpose,omitempty"`
+
+	// RegulatoryReporting is the regulatory reporting information of the transaction, if provided by the
+	// ASPSP.
+	RegulatoryReporting []*RegulatoryReporting `json:"regulatory_reporting,omitempty"`
+
 	// Note is the internal note made by PSU
 	Note string `json:"note,omitempty"`
 
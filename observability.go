@@ -0,0 +1,148 @@
+package enablebankinggo
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/marefr/enablebankinggo/internal/observe"
+)
+
+// RequestInfo represents information about a completed API request.
+type RequestInfo struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Path is the URL path of the request, without query parameters.
+	Path string
+
+	// RequestID is the [RequestIDHeader] of the request, if any.
+	RequestID string
+
+	// StatusCode is the HTTP status code of the response, or 0 if no response was received.
+	StatusCode int
+
+	// Header is the HTTP headers of the response, e.g. request ID and rate limit headers, or nil if no
+	// response was received.
+	Header http.Header
+
+	// Duration is the time it took to complete the request.
+	Duration time.Duration
+
+	// Err is the error returned by the request, if any.
+	Err error
+
+	// Connection is the connection diagnostics of the request, if enabled using
+	// [WithConnectionDiagnostics].
+	Connection *ConnectionInfo
+}
+
+// RequestObserver observes completed API requests, e.g. for collecting metrics or tracing. It is shared
+// by the Enable Banking API client and the control panel API client.
+type RequestObserver interface {
+	// ObserveRequest is called once every API request has completed.
+	ObserveRequest(ctx context.Context, info *RequestInfo)
+}
+
+// RequestObserverFunc is an adapter to allow the use of ordinary functions as [RequestObserver].
+type RequestObserverFunc func(ctx context.Context, info *RequestInfo)
+
+// ObserveRequest calls f(ctx, info).
+func (f RequestObserverFunc) ObserveRequest(ctx context.Context, info *RequestInfo) {
+	f(ctx, info)
+}
+
+// ResponseMetadata represents metadata of an API response, see [ContextWithResponseMetadata].
+type ResponseMetadata struct {
+	// StatusCode is the HTTP status code of the response, or 0 if no response was received.
+	StatusCode int
+
+	// Header is the HTTP headers of the response, or nil if no response was received.
+	Header http.Header
+
+	// Duration is the round-trip time of the request.
+	Duration time.Duration
+}
+
+type responseMetadataContextKey struct{}
+
+// ContextWithResponseMetadata returns a context capturing the metadata of the response of an operation called with
+// it into metadata, e.g. to log the request ID or rate limit headers returned by the API. For operations making
+// several requests, e.g. iterators, the metadata of the last response is captured. The context must not be used
+// by concurrent operations.
+func ContextWithResponseMetadata(ctx context.Context, metadata *ResponseMetadata) context.Context {
+	return context.WithValue(ctx, responseMetadataContextKey{}, metadata)
+}
+
+// LogValue returns the attributes of the request as a group, implementing [slog.LogValuer], e.g. logged by
+// [WithLogger].
+func (info *RequestInfo) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("method", info.Method),
+		slog.String("path", info.Path),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("duration", info.Duration),
+	}
+
+	if info.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", info.RequestID))
+	}
+
+	if conn := info.Connection; conn != nil {
+		attrs = append(attrs,
+			slog.Bool("conn_reused", conn.Reused),
+			slog.Duration("dns", conn.DNSDuration),
+			slog.Duration("connect", conn.ConnectDuration),
+			slog.Duration("tls_handshake", conn.TLSHandshakeDuration),
+			slog.Duration("ttfb", conn.TimeToFirstByte),
+		)
+	}
+
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// WithLogger sets a logger used for logging every request made by the client. Successful requests are logged at
+// debug level and failed requests at warning level, see [RequestInfo.LogValue].
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *APIClient) {
+		c.logger = logger
+	}
+}
+
+// WithRequestObserver sets an observer notified about every request made by the client.
+func WithRequestObserver(observer RequestObserver) ClientOption {
+	return func(c *APIClient) {
+		c.observer = observer
+	}
+}
+
+func (c *APIClient) observeRequest(req *http.Request, response *http.Response, duration time.Duration, conn *ConnectionInfo, err error) {
+	info := &RequestInfo{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestID:  req.Header.Get(RequestIDHeader),
+		Duration:   duration,
+		Err:        err,
+		Connection: conn,
+	}
+
+	if response != nil {
+		info.StatusCode = response.StatusCode
+		info.Header = response.Header
+	}
+
+	if metadata, ok := req.Context().Value(responseMetadataContextKey{}).(*ResponseMetadata); ok && metadata != nil {
+		*metadata = ResponseMetadata{
+			StatusCode: info.StatusCode,
+			Header:     info.Header,
+			Duration:   duration,
+		}
+	}
+
+	observe.Request(req.Context(), c.logger, c.observer, info, err)
+}
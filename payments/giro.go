@@ -0,0 +1,208 @@
+// Package payments provides builders and local, pre-flight validation of payment requests for the payment
+// initiation service (PIS), e.g. Swedish domestic Giro payments.
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// SwedishGiroCurrency is the currency of Swedish domestic Giro payments.
+const SwedishGiroCurrency = "SEK"
+
+var (
+	// ErrInvalidGiroNumber is returned when a BankGiro or PlusGiro number is invalid.
+	ErrInvalidGiroNumber = errors.New("invalid giro number")
+
+	// ErrInvalidOCR is returned when an OCR reference is invalid.
+	ErrInvalidOCR = errors.New("invalid OCR reference")
+
+	// ErrInvalidAmount is returned when an amount is invalid.
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+var amountPattern = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+
+// SwedishGiro represents a Swedish domestic Giro payment to a BankGiro or PlusGiro number.
+type SwedishGiro struct {
+	// Scheme is the scheme of Number, i.e. enablebankinggo.SwedishBankgiroNumberScheme or
+	// enablebankinggo.SwedishPlusGiroAccountNumberScheme.
+	Scheme enablebankinggo.SchemeName
+
+	// Number is the BankGiro or PlusGiro number, with or without separators, e.g. 5050-1055.
+	Number string
+
+	// OCR is the OCR reference, if the creditor requires one. Can't be combined with Message.
+	OCR string
+
+	// Message is the free-text message to the creditor, if no OCR reference is used.
+	Message string
+
+	// Amount is the amount in SEK, with up to two decimals, e.g. 100.50.
+	Amount string
+
+	// CreditorName is the name of the creditor, if any.
+	CreditorName string
+
+	// RequestedExecutionDate is the date (YYYY-MM-DD) to execute the payment, if any.
+	RequestedExecutionDate string
+}
+
+// Validate validates the Giro number, OCR reference, message and amount of the payment.
+func (g *SwedishGiro) Validate() error {
+	switch g.Scheme {
+	case enablebankinggo.SwedishBankgiroNumberScheme:
+		if err := ValidateBankgiroNumber(g.Number); err != nil {
+			return err
+		}
+	case enablebankinggo.SwedishPlusGiroAccountNumberScheme:
+		if err := ValidatePlusgiroNumber(g.Number); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported giro scheme %q", g.Scheme)
+	}
+
+	if g.OCR != "" {
+		if g.Message != "" {
+			return errors.New("OCR and message cannot be combined")
+		}
+
+		if err := ValidateOCR(g.OCR); err != nil {
+			return err
+		}
+	}
+
+	if !amountPattern.MatchString(g.Amount) || strings.Trim(g.Amount, "0.") == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, g.Amount)
+	}
+
+	return nil
+}
+
+// Transaction validates the payment and returns it as a credit transfer.
+func (g *SwedishGiro) Transaction() (*enablebankinggo.CreditTransferTransaction, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx := &enablebankinggo.CreditTransferTransaction{
+		InstructedAmount: &enablebankinggo.AmountType{
+			Currency: SwedishGiroCurrency,
+			Amount:   g.Amount,
+		},
+		Beneficiary: &enablebankinggo.Beneficiary{
+			CreditorAccount: &enablebankinggo.AccountIdentification{
+				Other: &enablebankinggo.GenericIdentification{
+					Identification: normalizeNumber(g.Number),
+					SchemeName:     string(g.Scheme),
+				},
+			},
+		},
+		RequestedExecutionDate: g.RequestedExecutionDate,
+	}
+
+	if g.CreditorName != "" {
+		tx.Beneficiary.Creditor = &enablebankinggo.PartyIdentification{Name: g.CreditorName}
+	}
+
+	remittance := NewRemittanceBuilder().Text(g.Message)
+	if g.OCR != "" {
+		remittance.SwedishOCR(normalizeNumber(g.OCR))
+	}
+
+	if err := remittance.Apply(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// SwedishGiroPayment validates the Giro payments and returns a DOMESTIC_SE_GIRO payment request of them.
+// The ASPSP, state and redirect URL are left for the caller to set.
+func SwedishGiroPayment(giros ...*SwedishGiro) (*enablebankinggo.CreatePaymentRequest, error) {
+	if len(giros) == 0 {
+		return nil, errors.New("giros cannot be empty")
+	}
+
+	transactions := make([]*enablebankinggo.CreditTransferTransaction, 0, len(giros))
+	for i, giro := range giros {
+		tx, err := giro.Transaction()
+		if err != nil {
+			return nil, fmt.Errorf("giro %d: %w", i, err)
+		}
+
+		transactions = append(transactions, tx)
+	}
+
+	return &enablebankinggo.CreatePaymentRequest{
+		PaymentType: enablebankinggo.DomesticSeGiroPaymentType,
+		PaymentRequest: &enablebankinggo.PaymentRequestResource{
+			CreditTransferTransaction: transactions,
+		},
+	}, nil
+}
+
+// ValidateBankgiroNumber validates a BankGiro number, i.e. seven or eight digits with a valid check
+// digit, with or without separators.
+func ValidateBankgiroNumber(number string) error {
+	n := normalizeNumber(number)
+	if len(n) < 7 || len(n) > 8 || !luhn(n) {
+		return fmt.Errorf("%w: %q", ErrInvalidGiroNumber, number)
+	}
+
+	return nil
+}
+
+// ValidatePlusgiroNumber validates a PlusGiro number, i.e. two to eight digits with a valid check digit,
+// with or without separators.
+func ValidatePlusgiroNumber(number string) error {
+	n := normalizeNumber(number)
+	if len(n) < 2 || len(n) > 8 || !luhn(n) {
+		return fmt.Errorf("%w: %q", ErrInvalidGiroNumber, number)
+	}
+
+	return nil
+}
+
+// ValidateOCR validates an OCR reference, i.e. two to 25 digits with a valid check digit.
+func ValidateOCR(ocr string) error {
+	n := normalizeNumber(ocr)
+	if len(n) < 2 || len(n) > 25 || !luhn(n) {
+		return fmt.Errorf("%w: %q", ErrInvalidOCR, ocr)
+	}
+
+	return nil
+}
+
+// normalizeNumber removes spaces and hyphens from number.
+func normalizeNumber(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(number)
+}
+
+// luhn returns whether digits are all digits with a valid modulus 10 (Luhn) check digit.
+func luhn(digits string) bool {
+	sum := 0
+	for i := range len(digits) {
+		c := digits[len(digits)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,220 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ErrInvalidReference is returned when a creditor reference is invalid.
+var ErrInvalidReference = errors.New("invalid creditor reference")
+
+// RemittanceBuilder builds the remittance information of credit transfers, i.e. free text and/or a
+// structured creditor reference with its scheme. Errors are returned by Apply.
+type RemittanceBuilder struct {
+	text      []string
+	reference string
+	scheme    enablebankinggo.ReferenceNumberScheme
+	err       error
+}
+
+// NewRemittanceBuilder returns an empty remittance builder.
+func NewRemittanceBuilder() *RemittanceBuilder {
+	return &RemittanceBuilder{}
+}
+
+// Text adds lines of free-text remittance information.
+func (b *RemittanceBuilder) Text(lines ...string) *RemittanceBuilder {
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			b.text = append(b.text, line)
+		}
+	}
+
+	return b
+}
+
+// CreditorReference sets an ISO 11649 structured creditor reference, e.g. RF18 5390 0754 7034.
+func (b *RemittanceBuilder) CreditorReference(reference string) *RemittanceBuilder {
+	return b.setReference(reference, enablebankinggo.InternationalReferenceNumberScheme, ValidateCreditorReference)
+}
+
+// FinnishReference sets a Finnish national reference number, e.g. 1232. Finnish references in the
+// ISO 11649 format, i.e. starting with RF, are set as creditor references.
+func (b *RemittanceBuilder) FinnishReference(reference string) *RemittanceBuilder {
+	if strings.HasPrefix(strings.ToUpper(normalizeReference(reference)), "RF") {
+		return b.CreditorReference(reference)
+	}
+
+	return b.setReference(reference, enablebankinggo.FinnishReferenceNumberScheme, ValidateFinnishReference)
+}
+
+// NorwegianKID sets a Norwegian KID number, with a MOD10 or MOD11 check digit.
+func (b *RemittanceBuilder) NorwegianKID(kid string) *RemittanceBuilder {
+	return b.setReference(kid, enablebankinggo.NorwegianKIDScheme, ValidateNorwegianKID)
+}
+
+// SwedishOCR sets a Swedish BankGiro/PlusGiro OCR reference.
+func (b *RemittanceBuilder) SwedishOCR(ocr string) *RemittanceBuilder {
+	return b.setReference(ocr, enablebankinggo.SwedishBankgiroOCRScheme, ValidateOCR)
+}
+
+func (b *RemittanceBuilder) setReference(reference string, scheme enablebankinggo.ReferenceNumberScheme, validate func(string) error) *RemittanceBuilder {
+	if b.reference != "" {
+		b.err = errors.Join(b.err, errors.New("creditor reference already set"))
+		return b
+	}
+
+	if err := validate(reference); err != nil {
+		b.err = errors.Join(b.err, err)
+		return b
+	}
+
+	b.reference = strings.ToUpper(normalizeReference(reference))
+	b.scheme = scheme
+
+	return b
+}
+
+// Apply sets the remittance information, reference number and reference number scheme of tx, clearing
+// them if not built, or returns the errors of the builder without modifying tx.
+func (b *RemittanceBuilder) Apply(tx *enablebankinggo.CreditTransferTransaction) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	if tx == nil {
+		return errors.New("tx cannot be nil")
+	}
+
+	tx.RemittanceInformation = b.text
+	tx.ReferenceNumber = b.reference
+	tx.ReferenceNumberSchema = b.scheme
+
+	return nil
+}
+
+// ValidateCreditorReference validates an ISO 11649 structured creditor reference, i.e. RF, two check
+// digits and up to 21 alphanumeric characters, with or without spaces.
+func ValidateCreditorReference(reference string) error {
+	r := strings.ToUpper(normalizeReference(reference))
+	if len(r) < 5 || len(r) > 25 || !strings.HasPrefix(r, "RF") || !isDigits(r[2:4]) || mod97(r[4:]+r[:4]) != 1 {
+		return fmt.Errorf("%w: %q", ErrInvalidReference, reference)
+	}
+
+	return nil
+}
+
+// NewCreditorReference returns the ISO 11649 structured creditor reference of an alphanumeric
+// reference, computing the check digits.
+func NewCreditorReference(reference string) (string, error) {
+	r := strings.ToUpper(normalizeReference(reference))
+	if r == "" || len(r) > 21 || mod97(r) < 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReference, reference)
+	}
+
+	return fmt.Sprintf("RF%02d%s", 98-mod97(r+"RF00"), r), nil
+}
+
+// ValidateFinnishReference validates a Finnish national reference number, i.e. four to 20 digits with a
+// valid 7-3-1 check digit, with or without spaces.
+func ValidateFinnishReference(reference string) error {
+	r := normalizeReference(reference)
+	if len(r) < 4 || len(r) > 20 || !isDigits(r) || finnishCheckDigit(r[:len(r)-1]) != r[len(r)-1] {
+		return fmt.Errorf("%w: %q", ErrInvalidReference, reference)
+	}
+
+	return nil
+}
+
+// NewFinnishReference returns the Finnish national reference number of a base of three to 19 digits,
+// appending the check digit.
+func NewFinnishReference(base string) (string, error) {
+	b := normalizeReference(base)
+	if len(b) < 3 || len(b) > 19 || !isDigits(b) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReference, base)
+	}
+
+	return b + string(finnishCheckDigit(b)), nil
+}
+
+// ValidateNorwegianKID validates a Norwegian KID number, i.e. two to 25 digits with a valid MOD10 or
+// MOD11 check digit, where a MOD11 check digit of 10 is written as a hyphen.
+func ValidateNorwegianKID(kid string) error {
+	k := strings.ReplaceAll(kid, " ", "")
+	if len(k) < 2 || len(k) > 25 || !isDigits(k[:len(k)-1]) || !(luhn(k) || mod11(k)) {
+		return fmt.Errorf("%w: %q", ErrInvalidReference, kid)
+	}
+
+	return nil
+}
+
+// normalizeReference removes spaces from reference.
+func normalizeReference(reference string) string {
+	return strings.ReplaceAll(reference, " ", "")
+}
+
+func isDigits(s string) bool {
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+// mod97 returns the ISO 7064 MOD 97-10 remainder of an alphanumeric string, with letters converted to
+// numbers (A = 10, ..., Z = 35), or -1 if s isn't alphanumeric.
+func mod97(s string) int {
+	var digits strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		default:
+			return -1
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return -1
+	}
+
+	return int(n.Mod(n, big.NewInt(97)).Int64())
+}
+
+// finnishCheckDigit returns the check digit of a Finnish reference number base, weighting the digits
+// 7, 3, 1 from the right.
+func finnishCheckDigit(base string) byte {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i := range len(base) {
+		sum += int(base[len(base)-1-i]-'0') * weights[i%3]
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+// mod11 returns whether kid has a valid MOD11 check digit, weighting the digits 2 to 7 from the right.
+func mod11(kid string) bool {
+	base := kid[:len(kid)-1]
+	sum := 0
+	for i := range len(base) {
+		sum += int(base[len(base)-1-i]-'0') * (2 + i%6)
+	}
+
+	check := (11 - sum%11) % 11
+	switch last := kid[len(kid)-1]; {
+	case check == 10:
+		return last == '-'
+	default:
+		return last == byte('0'+check)
+	}
+}
@@ -0,0 +1,142 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// DefaultStatusConcurrency is the default number of payments fetched concurrently.
+const DefaultStatusConcurrency = 8
+
+type (
+	// StatusParams represents the parameters for fetching the statuses of payments.
+	StatusParams struct {
+		// Concurrency is the number of payments fetched concurrently. Defaults to
+		// DefaultStatusConcurrency.
+		Concurrency int
+
+		// RateLimiter limits the rate of API calls, if set.
+		RateLimiter enablebankinggo.RateLimiter
+	}
+
+	// PaymentStatusResult represents the status of a payment.
+	PaymentStatusResult struct {
+		// PaymentID is the ID of the payment.
+		PaymentID string
+
+		// Payment is the payment, unless failed.
+		Payment *enablebankinggo.GetPaymentResponse
+
+		// Err is the error retrieving the payment, if any.
+		Err error
+	}
+
+	// StatusReport represents the statuses of payments.
+	StatusReport struct {
+		// Payments is the statuses of the payments, in the order requested.
+		Payments []*PaymentStatusResult
+
+		// ByStatus is the IDs of the retrieved payments, by status.
+		ByStatus map[enablebankinggo.PaymentStatus][]string
+
+		// Final is the number of payments with a final status.
+		Final int
+
+		// Pending is the number of payments without a final status.
+		Pending int
+	}
+)
+
+// Failed returns the payments that couldn't be retrieved.
+func (r *StatusReport) Failed() []*PaymentStatusResult {
+	var failed []*PaymentStatusResult
+	for _, payment := range r.Payments {
+		if payment.Err != nil {
+			failed = append(failed, payment)
+		}
+	}
+
+	return failed
+}
+
+// Err returns the errors of all payments that couldn't be retrieved, joined, or nil.
+func (r *StatusReport) Err() error {
+	var errs []error
+	for _, payment := range r.Failed() {
+		errs = append(errs, fmt.Errorf("payment %s: %w", payment.PaymentID, payment.Err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Statuses retrieves the payments concurrently, with bounded concurrency and optional rate limiting,
+// e.g. for back-office reconciliation. Failures of individual payments are reported in the report.
+func Statuses(ctx context.Context, client enablebankinggo.PaymentsClient, paymentIDs []string, params *StatusParams) (*StatusReport, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	if params == nil {
+		params = &StatusParams{}
+	}
+
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultStatusConcurrency
+	}
+
+	report := &StatusReport{
+		Payments: make([]*PaymentStatusResult, len(paymentIDs)),
+		ByStatus: map[enablebankinggo.PaymentStatus][]string{},
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, paymentID := range paymentIDs {
+		result := &PaymentStatusResult{PaymentID: paymentID}
+		report.Payments[i] = result
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+
+			if params.RateLimiter != nil {
+				if result.Err = params.RateLimiter.Wait(ctx); result.Err != nil {
+					return
+				}
+			}
+
+			result.Payment, result.Err = client.GetPayment(ctx, paymentID)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Payments {
+		if result.Err != nil {
+			continue
+		}
+
+		status := result.Payment.Status
+		report.ByStatus[status] = append(report.ByStatus[status], result.PaymentID)
+		if status.IsFinal() {
+			report.Final++
+		} else {
+			report.Pending++
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,441 @@
+package payments
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ValidationErrorKind represents the kind of a [ValidationError].
+type ValidationErrorKind string
+
+const (
+	// RequiredFieldValidationError indicates that a required field is missing.
+	RequiredFieldValidationError ValidationErrorKind = "required_field"
+
+	// InvalidValueValidationError indicates that a field has an invalid value.
+	InvalidValueValidationError ValidationErrorKind = "invalid_value"
+
+	// UnsupportedPaymentTypeValidationError indicates that the ASPSP doesn't support the payment type.
+	UnsupportedPaymentTypeValidationError ValidationErrorKind = "unsupported_payment_type"
+
+	// UnsupportedCurrencyValidationError indicates that the payment type or ASPSP doesn't support the
+	// currency.
+	UnsupportedCurrencyValidationError ValidationErrorKind = "unsupported_currency"
+
+	// UnsupportedPSUTypeValidationError indicates that the ASPSP doesn't support the PSU type.
+	UnsupportedPSUTypeValidationError ValidationErrorKind = "unsupported_psu_type"
+
+	// UnsupportedValueValidationError indicates that the ASPSP doesn't support a field or its value for
+	// the payment type, e.g. a creditor account scheme or a requested execution date.
+	UnsupportedValueValidationError ValidationErrorKind = "unsupported_value"
+
+	// ConsentValidityValidationError indicates that the payment is executed after the maximum consent
+	// validity of the ASPSP.
+	ConsentValidityValidationError ValidationErrorKind = "consent_validity"
+)
+
+// ValidationError represents a field of a payment request failing validation.
+type ValidationError struct {
+	// Field is the JSON path of the field, e.g. payment_request.credit_transfer_transaction[0].beneficiary.
+	Field string
+
+	// Kind is the kind of error.
+	Kind ValidationErrorKind
+
+	// Message describes the error.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Field, e.Kind, e.Message)
+}
+
+// ValidationErrors represents the validation errors of a payment request.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Capabilities represents the payment capabilities of an ASPSP. Empty capabilities aren't checked.
+type Capabilities struct {
+	// PaymentTypes is the supported payment types.
+	PaymentTypes []enablebankinggo.PaymentType
+
+	// Currencies is the supported currencies, by payment type.
+	Currencies map[enablebankinggo.PaymentType][]string
+
+	// PSUTypes is the supported PSU types.
+	PSUTypes []enablebankinggo.PSUType
+
+	// MaximumConsentValidity is the maximum consent validity, limiting how far in the future payments
+	// can be executed.
+	MaximumConsentValidity time.Duration
+
+	// Payments is the capabilities and requirements of the supported payments, by payment type and PSU
+	// type.
+	Payments []*enablebankinggo.SupportedPayment
+}
+
+// ASPSPCapabilities returns the capabilities of an ASPSP of GET /aspsps.
+func ASPSPCapabilities(aspsp *enablebankinggo.ASPSPData) *Capabilities {
+	if aspsp == nil {
+		return &Capabilities{}
+	}
+
+	capabilities := &Capabilities{
+		PSUTypes:               aspsp.PSUTypes,
+		MaximumConsentValidity: time.Duration(aspsp.MaximumConsentValidity) * time.Second,
+		Payments:               aspsp.Payments,
+	}
+
+	for _, payment := range aspsp.Payments {
+		if payment == nil {
+			continue
+		}
+
+		if !slices.Contains(capabilities.PaymentTypes, payment.PaymentType) {
+			capabilities.PaymentTypes = append(capabilities.PaymentTypes, payment.PaymentType)
+		}
+
+		if len(payment.Currencies) > 0 {
+			if capabilities.Currencies == nil {
+				capabilities.Currencies = map[enablebankinggo.PaymentType][]string{}
+			}
+
+			for _, currency := range payment.Currencies {
+				if !slices.Contains(capabilities.Currencies[payment.PaymentType], currency) {
+					capabilities.Currencies[payment.PaymentType] = append(capabilities.Currencies[payment.PaymentType], currency)
+				}
+			}
+		}
+	}
+
+	return capabilities
+}
+
+// Payment returns the supported payment of the payment type and PSU type, or of any PSU type if
+// psuType is empty, or nil.
+func (c *Capabilities) Payment(paymentType enablebankinggo.PaymentType, psuType enablebankinggo.PSUType) *enablebankinggo.SupportedPayment {
+	for _, payment := range c.Payments {
+		if payment != nil && payment.PaymentType == paymentType && (psuType == "" || payment.PSUType == psuType) {
+			return payment
+		}
+	}
+
+	return nil
+}
+
+// schemeCurrencies is the currencies required by payment types, if any.
+var schemeCurrencies = map[enablebankinggo.PaymentType]string{
+	enablebankinggo.SepaPaymentType:           "EUR",
+	enablebankinggo.InstSepaPaymentType:       "EUR",
+	enablebankinggo.BulkSepaPaymentType:       "EUR",
+	enablebankinggo.DomesticSeGiroPaymentType: SwedishGiroCurrency,
+}
+
+// Validate validates a payment request locally against the capabilities of the target ASPSP, which
+// may be nil, before it's sent. Returns [ValidationErrors] or nil. Dates are validated relative to now.
+func Validate(req *enablebankinggo.CreatePaymentRequest, capabilities *Capabilities, now time.Time) error {
+	if req == nil {
+		return ValidationErrors{{Field: "request", Kind: RequiredFieldValidationError, Message: "request cannot be nil"}}
+	}
+
+	if capabilities == nil {
+		capabilities = &Capabilities{}
+	}
+
+	v := &validator{capabilities: capabilities, now: now}
+	v.request(req)
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+type validator struct {
+	capabilities *Capabilities
+	supported    *enablebankinggo.SupportedPayment
+	now          time.Time
+	errs         ValidationErrors
+}
+
+func (v *validator) add(field string, kind ValidationErrorKind, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field string, ok bool) bool {
+	if !ok {
+		v.add(field, RequiredFieldValidationError, "cannot be empty")
+	}
+
+	return ok
+}
+
+func (v *validator) request(req *enablebankinggo.CreatePaymentRequest) {
+	v.required("aspsp.name", req.ASPSP.Name != "")
+	v.required("aspsp.country", req.ASPSP.Country != "")
+	v.required("redirect_url", req.RedirectURL != "")
+
+	if v.required("payment_type", req.PaymentType != "") {
+		if len(v.capabilities.PaymentTypes) > 0 && !slices.Contains(v.capabilities.PaymentTypes, req.PaymentType) {
+			v.add("payment_type", UnsupportedPaymentTypeValidationError, "%s isn't supported by the ASPSP", req.PaymentType)
+		}
+	}
+
+	if req.PSUType != "" {
+		if !req.PSUType.IsValid() {
+			v.add("psu_type", InvalidValueValidationError, "unknown PSU type %q", req.PSUType)
+		} else if len(v.capabilities.PSUTypes) > 0 && !slices.Contains(v.capabilities.PSUTypes, req.PSUType) {
+			v.add("psu_type", UnsupportedPSUTypeValidationError, "%s isn't supported by the ASPSP", req.PSUType)
+		}
+	}
+
+	if !v.required("payment_request", req.PaymentRequest != nil) {
+		return
+	}
+
+	v.supported = v.capabilities.Payment(req.PaymentType, req.PSUType)
+	if v.supported != nil && req.AuthMethod != "" && len(v.supported.AllowedAuthMethods) > 0 &&
+		!slices.Contains(v.supported.AllowedAuthMethods, req.AuthMethod) {
+		v.add("auth_method", UnsupportedValueValidationError, "%s isn't allowed by the ASPSP for %s payments", req.AuthMethod, req.PaymentType)
+	}
+
+	v.paymentRequest(req.PaymentType, req.PaymentRequest)
+}
+
+func (v *validator) paymentRequest(paymentType enablebankinggo.PaymentType, pr *enablebankinggo.PaymentRequestResource) {
+	const field = "payment_request.credit_transfer_transaction"
+	if !v.required(field, len(pr.CreditTransferTransaction) > 0) {
+		return
+	}
+
+	bulk := paymentType == enablebankinggo.BulkSepaPaymentType || paymentType == enablebankinggo.BulkDomesticPaymentType
+	if !bulk && len(pr.CreditTransferTransaction) > 1 {
+		v.add(field, InvalidValueValidationError, "%s payments have a single credit transfer", paymentType)
+	}
+
+	if pr.ChargeBearer != "" && !pr.ChargeBearer.IsValid() {
+		v.add("payment_request.charge_bearer", InvalidValueValidationError, "unknown charge bearer %q", pr.ChargeBearer)
+	}
+
+	if supported := v.supported; supported != nil {
+		if supported.MaxTransactions > 0 && len(pr.CreditTransferTransaction) > supported.MaxTransactions {
+			v.add(field, UnsupportedValueValidationError, "the ASPSP supports up to %d credit transfers", supported.MaxTransactions)
+		}
+
+		if pr.ChargeBearer != "" && len(supported.ChargeBearerValues) > 0 && !slices.Contains(supported.ChargeBearerValues, pr.ChargeBearer) {
+			v.add("payment_request.charge_bearer", UnsupportedValueValidationError, "%s isn't supported by the ASPSP", pr.ChargeBearer)
+		}
+
+		if v.required("payment_request.debtor_account", !supported.DebtorAccountRequired || pr.DebtorAccount != nil) && pr.DebtorAccount != nil {
+			v.scheme("payment_request.debtor_account", pr.DebtorAccount, supported.DebtorAccountSchemas)
+		}
+	}
+
+	if info := pr.PaymentTypeInformation; info != nil && info.ServiceLevel != "" && !info.ServiceLevel.IsValid() {
+		v.add("payment_request.payment_type_information.service_level", InvalidValueValidationError, "unknown service level %q", info.ServiceLevel)
+	}
+
+	for i, tx := range pr.CreditTransferTransaction {
+		v.transaction(fmt.Sprintf("%s[%d]", field, i), paymentType, tx)
+	}
+}
+
+func (v *validator) transaction(field string, paymentType enablebankinggo.PaymentType, tx *enablebankinggo.CreditTransferTransaction) {
+	if !v.required(field, tx != nil) {
+		return
+	}
+
+	if v.required(field+".instructed_amount", tx.InstructedAmount != nil) {
+		v.amount(field+".instructed_amount", paymentType, tx.InstructedAmount)
+	}
+
+	if v.required(field+".beneficiary", tx.Beneficiary != nil) {
+		v.beneficiary(field+".beneficiary", paymentType, tx.Beneficiary)
+	}
+
+	if supported := v.supported; supported != nil {
+		remittance := strings.Join(tx.RemittanceInformation, "")
+		v.required(field+".remittance_information", !supported.RemittanceInfoRequired || remittance != "" || tx.ReferenceNumber != "")
+		if supported.RemittanceInfoMaxLength > 0 && len([]rune(remittance)) > supported.RemittanceInfoMaxLength {
+			v.add(field+".remittance_information", UnsupportedValueValidationError, "exceeds the maximum length of %d", supported.RemittanceInfoMaxLength)
+		}
+
+		if tx.ReferenceNumber != "" && !supported.ReferenceNumberSupported {
+			v.add(field+".reference_number", UnsupportedValueValidationError, "reference numbers aren't supported by the ASPSP")
+		}
+	}
+
+	if tx.ReferenceNumberSchema == enablebankinggo.SwedishBankgiroOCRScheme {
+		if err := ValidateOCR(tx.ReferenceNumber); err != nil {
+			v.add(field+".reference_number", InvalidValueValidationError, "%s", err)
+		}
+	}
+
+	v.dates(field, tx)
+}
+
+func (v *validator) amount(field string, paymentType enablebankinggo.PaymentType, amount *enablebankinggo.AmountType) {
+	if !amountPattern.MatchString(amount.Amount) || strings.Trim(amount.Amount, "0.") == "" {
+		v.add(field+".amount", InvalidValueValidationError, "%q isn't a positive amount with up to two decimals", amount.Amount)
+	}
+
+	if !v.required(field+".currency", amount.Currency != "") {
+		return
+	}
+
+	if currency, ok := schemeCurrencies[paymentType]; ok && amount.Currency != currency {
+		v.add(field+".currency", UnsupportedCurrencyValidationError, "%s payments must be in %s", paymentType, currency)
+		return
+	}
+
+	if currencies := v.capabilities.Currencies[paymentType]; len(currencies) > 0 && !slices.Contains(currencies, amount.Currency) {
+		v.add(field+".currency", UnsupportedCurrencyValidationError, "%s isn't supported by the ASPSP for %s payments", amount.Currency, paymentType)
+	}
+}
+
+func (v *validator) beneficiary(field string, paymentType enablebankinggo.PaymentType, beneficiary *enablebankinggo.Beneficiary) {
+	account := beneficiary.CreditorAccount
+	if !v.required(field+".creditor_account", account != nil && (account.IBAN != "" || account.Other != nil)) {
+		return
+	}
+
+	if supported := v.supported; supported != nil {
+		v.scheme(field+".creditor_account", account, supported.CreditorAccountSchemas)
+		v.required(field+".creditor.name", !supported.CreditorNameRequired || (beneficiary.Creditor != nil && beneficiary.Creditor.Name != ""))
+
+		var address *enablebankinggo.PostalAddress
+		if beneficiary.Creditor != nil {
+			address = beneficiary.Creditor.PostalAddress
+		}
+
+		if v.required(field+".creditor.postal_address", !supported.CreditorPostalAddressRequired || address != nil) {
+			v.required(field+".creditor.postal_address.country", !supported.CreditorCountryRequired || (address != nil && address.Country != ""))
+		}
+	}
+
+	switch paymentType {
+	case enablebankinggo.SepaPaymentType, enablebankinggo.InstSepaPaymentType, enablebankinggo.BulkSepaPaymentType,
+		enablebankinggo.CrossborderPaymentType:
+		v.required(field+".creditor.name", beneficiary.Creditor != nil && beneficiary.Creditor.Name != "")
+		if paymentType != enablebankinggo.CrossborderPaymentType {
+			v.required(field+".creditor_account.iban", account.IBAN != "")
+		}
+	case enablebankinggo.DomesticSeGiroPaymentType:
+		if account.Other == nil {
+			v.add(field+".creditor_account.other", RequiredFieldValidationError, "giro payments require a BankGiro or PlusGiro number")
+			return
+		}
+
+		var err error
+		switch enablebankinggo.SchemeName(account.Other.SchemeName) {
+		case enablebankinggo.SwedishBankgiroNumberScheme:
+			err = ValidateBankgiroNumber(account.Other.Identification)
+		case enablebankinggo.SwedishPlusGiroAccountNumberScheme:
+			err = ValidatePlusgiroNumber(account.Other.Identification)
+		default:
+			err = fmt.Errorf("unsupported giro scheme %q", account.Other.SchemeName)
+		}
+
+		if err != nil {
+			v.add(field+".creditor_account.other", InvalidValueValidationError, "%s", err)
+		}
+	}
+}
+
+func (v *validator) dates(field string, tx *enablebankinggo.CreditTransferTransaction) {
+	today := time.Date(v.now.Year(), v.now.Month(), v.now.Day(), 0, 0, 0, 0, time.UTC)
+	start, startOK := v.date(field+".requested_execution_date", tx.RequestedExecutionDate)
+	if startOK && start.Before(today) {
+		v.add(field+".requested_execution_date", InvalidValueValidationError, "cannot be in the past")
+	}
+
+	if supported := v.supported; supported != nil && startOK {
+		if !supported.RequestedExecutionDateSupported {
+			v.add(field+".requested_execution_date", UnsupportedValueValidationError, "future-dated payments aren't supported by the ASPSP")
+		} else if days := supported.RequestedExecutionDateMaxPeriod; days > 0 && start.After(today.AddDate(0, 0, days)) {
+			v.add(field+".requested_execution_date", UnsupportedValueValidationError, "cannot be more than %d days in the future", days)
+		}
+	}
+
+	end, endOK := v.date(field+".end_date", tx.EndDate)
+	periodic := tx.Frequency != ""
+	if periodic {
+		if !tx.Frequency.IsValid() {
+			v.add(field+".frequency", InvalidValueValidationError, "unknown frequency %q", tx.Frequency)
+		}
+
+		v.required(field+".requested_execution_date", tx.RequestedExecutionDate != "")
+		if startOK && endOK && end.Before(start) {
+			v.add(field+".end_date", InvalidValueValidationError, "cannot be before the requested execution date")
+		}
+	} else {
+		if tx.EndDate != "" {
+			v.add(field+".end_date", InvalidValueValidationError, "only allowed for periodic payments")
+		}
+
+		if tx.ExecutionRule != "" {
+			v.add(field+".execution_rule", InvalidValueValidationError, "only allowed for periodic payments")
+		}
+	}
+
+	if tx.ExecutionRule != "" && !tx.ExecutionRule.IsValid() {
+		v.add(field+".execution_rule", InvalidValueValidationError, "unknown execution rule %q", tx.ExecutionRule)
+	}
+
+	validity := v.capabilities.MaximumConsentValidity
+	if validity <= 0 {
+		return
+	}
+
+	last, lastField := start, field+".requested_execution_date"
+	if endOK {
+		last, lastField = end, field+".end_date"
+	}
+
+	if !last.IsZero() && last.After(v.now.Add(validity)) {
+		v.add(lastField, ConsentValidityValidationError, "exceeds the maximum consent validity of %s", validity)
+	}
+}
+
+// scheme validates that the identification scheme of account is one of schemes, if any.
+func (v *validator) scheme(field string, account *enablebankinggo.AccountIdentification, schemes []enablebankinggo.SchemeName) {
+	if len(schemes) == 0 {
+		return
+	}
+
+	scheme := enablebankinggo.InternationalBankAccountNumberScheme
+	if account.IBAN == "" && account.Other != nil {
+		scheme = enablebankinggo.SchemeName(account.Other.SchemeName)
+	}
+
+	if !slices.Contains(schemes, scheme) {
+		v.add(field, UnsupportedValueValidationError, "scheme %s isn't supported by the ASPSP", scheme)
+	}
+}
+
+// date parses the date (YYYY-MM-DD) of field, if set.
+func (v *validator) date(field, value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		v.add(field, InvalidValueValidationError, "%q isn't a date (YYYY-MM-DD)", value)
+		return time.Time{}, false
+	}
+
+	return t, true
+}
@@ -0,0 +1,317 @@
+package enablebankinggo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DefaultPaymentPollInterval is the default interval between payment status checks.
+const DefaultPaymentPollInterval = 5 * time.Second
+
+var (
+	// ErrPaymentStateMismatch is returned when the state of a payment callback doesn't match the state
+	// of the payment authorization.
+	ErrPaymentStateMismatch = errors.New("payment state mismatch")
+
+	// ErrPaymentAuthorizationFailed is returned when the ASPSP redirects with an error, e.g. when the
+	// PSU cancels the payment authorization.
+	ErrPaymentAuthorizationFailed = errors.New("payment authorization failed")
+)
+
+type (
+	// PaymentAuthorization represents a created payment awaiting authorization by the PSU.
+	PaymentAuthorization struct {
+		// PaymentID is the ID of the payment.
+		PaymentID string
+
+		// State is the state passed to the ASPSP, returned in the callback.
+		State string
+
+		// URL is the URL to redirect PSU to for authorizing the payment.
+		URL string
+
+		// Status is the status of the payment when created.
+		Status PaymentStatus
+	}
+
+	// PaymentCallback represents the query parameters of the redirect after the PSU authorized, or
+	// failed to authorize, a payment.
+	PaymentCallback struct {
+		// State is the state passed when creating the payment.
+		State string
+
+		// Error is the error code, if the authorization failed.
+		Error string
+
+		// ErrorDescription is the description of the error, if any.
+		ErrorDescription string
+	}
+
+	// WaitForPaymentStatusParams represents the parameters for waiting for a payment status.
+	WaitForPaymentStatusParams struct {
+		// Interval is the interval between payment status checks. Defaults to
+		// DefaultPaymentPollInterval.
+		Interval time.Duration
+
+		// Until returns whether to stop waiting at status. Defaults to [PaymentStatus.IsFinal].
+		Until func(status PaymentStatus) bool
+
+		// Notifier notifies of payment status changes, e.g. received by webhooks, replacing polling. The
+		// payment is retrieved initially and when notified of a status to stop at, and only polled if
+		// Interval is set, or if the retrieved status lags behind a notified status, e.g. due to eventual
+		// consistency, in which case it's polled at DefaultPaymentPollInterval. Defaults to the notifier of
+		// the client, see [WithPaymentStatusNotifier].
+		Notifier PaymentStatusNotifier
+
+		// Clock is the clock of the polling interval. Defaults to the clock of the client, see [WithClock], or
+		// SystemClock.
+		Clock Clock
+	}
+
+	// PaymentStatusNotifier notifies of payment status changes, e.g. a webhook receiver.
+	PaymentStatusNotifier interface {
+		// SubscribePaymentStatus returns a channel receiving the status changes of a payment, and a
+		// function to unsubscribe.
+		SubscribePaymentStatus(paymentID string) (<-chan PaymentStatus, func())
+	}
+
+	// PaymentAuthFlow orchestrates the authorization of payments, i.e. creating the payment, redirecting
+	// the PSU to the ASPSP, verifying the callback and waiting for the payment to reach a final status.
+	PaymentAuthFlow struct {
+		// Client is the payments client.
+		Client PaymentsClient
+
+		// RedirectURL is the URL PSU is redirected to after authorizing a payment, used unless set on
+		// the request.
+		RedirectURL string
+
+		// Wait is the parameters for waiting for the payment status after the callback.
+		Wait *WaitForPaymentStatusParams
+	}
+)
+
+// ParsePaymentCallback parses the query parameters of the redirect after a payment authorization.
+func ParsePaymentCallback(query url.Values) (*PaymentCallback, error) {
+	callback := &PaymentCallback{
+		State:            query.Get("state"),
+		Error:            query.Get("error"),
+		ErrorDescription: query.Get("error_description"),
+	}
+
+	if callback.State == "" {
+		return nil, errors.New("state cannot be empty")
+	}
+
+	return callback, nil
+}
+
+// Err returns ErrPaymentAuthorizationFailed with the error description if the authorization failed,
+// otherwise nil.
+func (cb *PaymentCallback) Err() error {
+	if cb.Error == "" {
+		return nil
+	}
+
+	description := cb.ErrorDescription
+	if description == "" {
+		description = cb.Error
+	}
+
+	return fmt.Errorf("%w: %s", ErrPaymentAuthorizationFailed, description)
+}
+
+// Start creates the payment, generating a random state unless set on the request, and returns the
+// authorization to redirect the PSU with. The request isn't modified.
+func (f *PaymentAuthFlow) Start(ctx context.Context, req *CreatePaymentRequest) (*PaymentAuthorization, error) {
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	if f.Client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	r := *req
+	if r.RedirectURL == "" {
+		r.RedirectURL = f.RedirectURL
+	}
+
+	if r.RedirectURL == "" {
+		return nil, errors.New("redirect URL cannot be empty")
+	}
+
+	if r.State == "" {
+		state, err := randomState()
+		if err != nil {
+			return nil, err
+		}
+
+		r.State = state
+	}
+
+	resp, err := f.Client.CreatePayment(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentAuthorization{
+		PaymentID: resp.PaymentID,
+		State:     r.State,
+		URL:       resp.URL,
+		Status:    resp.Status,
+	}, nil
+}
+
+// Complete verifies the callback query parameters against the authorization and waits for the payment
+// to reach a final status, see [WaitForPaymentStatus]. Bound the wait using ctx.
+func (f *PaymentAuthFlow) Complete(ctx context.Context, auth *PaymentAuthorization, query url.Values) (*GetPaymentResponse, error) {
+	if auth == nil {
+		return nil, errors.New("auth cannot be nil")
+	}
+
+	if f.Client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	callback, err := ParsePaymentCallback(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if callback.State != auth.State {
+		return nil, ErrPaymentStateMismatch
+	}
+
+	if err := callback.Err(); err != nil {
+		return nil, err
+	}
+
+	if c, ok := f.Client.(*APIClient); ok {
+		return c.WaitForPaymentStatus(ctx, auth.PaymentID, f.Wait)
+	}
+
+	return WaitForPaymentStatus(ctx, f.Client, auth.PaymentID, f.Wait)
+}
+
+// WithPaymentStatusNotifier sets the notifier of payment status changes used by
+// [APIClient.WaitForPaymentStatus], unless set in the params, e.g. a webhook receiver.
+func WithPaymentStatusNotifier(notifier PaymentStatusNotifier) ClientOption {
+	return func(c *APIClient) {
+		c.paymentStatusNotifier = notifier
+	}
+}
+
+// WaitForPaymentStatus polls the payment until a final status, or the status params.Until stops at.
+// Returns the last payment response, or the error getting the payment, or ctx.Err() when ctx is done.
+func (c *APIClient) WaitForPaymentStatus(ctx context.Context, paymentID string, params *WaitForPaymentStatusParams) (*GetPaymentResponse, error) {
+	p := WaitForPaymentStatusParams{}
+	if params != nil {
+		p = *params
+	}
+
+	if p.Notifier == nil {
+		p.Notifier = c.paymentStatusNotifier
+	}
+
+	if p.Clock == nil {
+		p.Clock = c.clock
+	}
+
+	params = &p
+
+	return WaitForPaymentStatus(ctx, c, paymentID, params)
+}
+
+// WaitForPaymentStatus polls the payment using the provided client, see [APIClient.WaitForPaymentStatus].
+func WaitForPaymentStatus(ctx context.Context, client PaymentsClient, paymentID string, params *WaitForPaymentStatusParams) (*GetPaymentResponse, error) {
+	if paymentID == "" {
+		return nil, errors.New("paymentID cannot be empty")
+	}
+
+	if params == nil {
+		params = &WaitForPaymentStatusParams{}
+	}
+
+	until := params.Until
+	if until == nil {
+		until = PaymentStatus.IsFinal
+	}
+
+	clock := params.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	// Without a notifier the payment is polled, with a notifier only if an interval is set.
+	var notifications <-chan PaymentStatus
+	interval := params.Interval
+	if params.Notifier != nil {
+		// Subscribe before the initial retrieval so status changes in between aren't missed.
+		ch, unsubscribe := params.Notifier.SubscribePaymentStatus(paymentID)
+		defer unsubscribe()
+
+		notifications = ch
+	} else if interval <= 0 {
+		interval = DefaultPaymentPollInterval
+	}
+
+	notified := false
+	for {
+		resp, err := client.GetPayment(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if until(resp.Status) {
+			return resp, nil
+		}
+
+		// The notified status isn't retrievable yet, and no further notification may follow.
+		if notified && interval <= 0 {
+			interval = DefaultPaymentPollInterval
+		}
+
+		var poll <-chan time.Time
+		if interval > 0 {
+			poll = clock.After(interval)
+		}
+
+	wait:
+		for {
+			select {
+			case <-poll:
+				break wait
+			case status, ok := <-notifications:
+				if !ok {
+					notifications = nil
+					if poll == nil {
+						return resp, errors.New("payment status notifications closed")
+					}
+
+					continue
+				}
+
+				if until(status) {
+					notified = true
+					break wait
+				}
+			case <-ctx.Done():
+				return resp, ctx.Err()
+			}
+		}
+	}
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,167 @@
+package enablebankinggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type (
+	// CreatePaymentRequest represents request to create a payment (POST /payments).
+	CreatePaymentRequest struct {
+		// PaymentType is the type of the payment.
+		PaymentType PaymentType `json:"payment_type"`
+
+		// PaymentRequest is the details of the payment, i.e. the debtor and the credit transfers.
+		PaymentRequest *PaymentRequestResource `json:"payment_request"`
+
+		// ASPSP is the ASPSP that PSU is going to authorize the payment with.
+		ASPSP ASPSP `json:"aspsp"`
+
+		// State is an opaque value used by the client to maintain state between the request and
+		// callback. Same string will be returned in query parameter when redirecting to the URL
+		// passed via redirect_url parameter
+		State string `json:"state"`
+
+		// RedirectURL is the URL that PSU will be redirected to after authorizing the payment.
+		RedirectURL string `json:"redirect_url"`
+
+		// PSUType is the PSU type which the payment is initiated for.
+		PSUType PSUType `json:"psu_type,omitempty"`
+
+		// AuthMethod is the desired authorization method (in case ASPSP supports multiple).
+		// Supported methods can be obtained from ASPSP auth_methods.
+		AuthMethod string `json:"auth_method,omitempty"`
+
+		// Credentials is PSU credentials (User ID, company ID etc.) If not provided, then those are
+		// going to be asked from a PSU during authorization.
+		Credentials map[string]any `json:"credentials,omitempty"`
+
+		// CredentialsAutoSubmit controls whether user credentials will be autosubmitted (if passed).
+		// If set to false then credentials form will be prefilled with passed credentials.
+		CredentialsAutoSubmit bool `json:"credentials_autosubmit,omitempty"`
+
+		// Language is the preferred PSU language. Two-letter lowercase language code.
+		Language string `json:"language,omitempty"`
+
+		// PSUID is an optional unique identification of a PSU used by the client application, see
+		// StartAuthorizationRequest.PSUID.
+		PSUID string `json:"psu_id,omitempty"`
+	}
+
+	// CreatePaymentResponse represents response from creating a payment (POST /payments).
+	CreatePaymentResponse struct {
+		// PaymentID is the ID of the payment, used to get its status.
+		PaymentID string `json:"payment_id"`
+
+		// Status is the status of the payment.
+		Status PaymentStatus `json:"status"`
+
+		// URL is the URL to redirect PSU to for authorizing the payment.
+		URL string `json:"url"`
+	}
+
+	// GetPaymentResponse represents response from GET /payments/{payment_id} endpoint.
+	GetPaymentResponse struct {
+		// PaymentID is the ID of the payment.
+		PaymentID string `json:"payment_id"`
+
+		// Status is the current status of the payment.
+		Status PaymentStatus `json:"status"`
+
+		// PaymentDetails is the details of the payment, including the identifications assigned to the
+		// credit transfers.
+		PaymentDetails *PaymentRequestResource `json:"payment_details"`
+	}
+
+	// DeletePaymentRequestParams represents request parameters for DELETE /payments/{payment_id} endpoint.
+	DeletePaymentRequestParams struct {
+		// Headers represents additional headers to include in the request.
+		Headers Header
+	}
+
+	// PaymentsClient client for payment initiation (PIS) API operations.
+	PaymentsClient interface {
+		// CreatePayment create a payment and get a redirect link for the PSU to authorize the payment.
+		CreatePayment(ctx context.Context, req *CreatePaymentRequest) (*CreatePaymentResponse, error)
+
+		// GetPayment get payment data by payment ID.
+		GetPayment(ctx context.Context, paymentID string) (*GetPaymentResponse, error)
+
+		// DeletePayment delete payment by payment ID, e.g. an unfinished or cancelled payment.
+		DeletePayment(ctx context.Context, paymentID string, params *DeletePaymentRequestParams) (*SuccessResponse, error)
+	}
+)
+
+// CreatePayment create a payment and get a redirect link for the PSU to authorize the payment.
+func (c *APIClient) CreatePayment(ctx context.Context, req *CreatePaymentRequest) (*CreatePaymentResponse, error) {
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	if req.PaymentType == "" {
+		return nil, errors.New("req.PaymentType cannot be empty")
+	}
+
+	if req.PaymentRequest == nil || len(req.PaymentRequest.CreditTransferTransaction) == 0 {
+		return nil, errors.New("req.PaymentRequest.CreditTransferTransaction cannot be empty")
+	}
+
+	reqHTTP, err := c.newRequest(ctx, http.MethodPost, "/payments", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreatePaymentResponse
+	err = c.sendRequest(reqHTTP, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetPayment get payment data by payment ID.
+func (c *APIClient) GetPayment(ctx context.Context, paymentID string) (*GetPaymentResponse, error) {
+	if paymentID == "" {
+		return nil, errors.New("paymentID cannot be empty")
+	}
+
+	reqHTTP, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/payments/%s", paymentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetPaymentResponse
+	err = c.sendRequest(reqHTTP, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeletePayment delete payment by payment ID, e.g. an unfinished or cancelled payment.
+func (c *APIClient) DeletePayment(ctx context.Context, paymentID string, params *DeletePaymentRequestParams) (*SuccessResponse, error) {
+	if paymentID == "" {
+		return nil, errors.New("paymentID cannot be empty")
+	}
+
+	reqHTTP, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/payments/%s", paymentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil && params.Headers != nil {
+		params.Headers.FillHTTPHeader(reqHTTP.Header)
+	}
+
+	var resp SuccessResponse
+	err = c.sendRequest(reqHTTP, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
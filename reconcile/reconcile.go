@@ -0,0 +1,283 @@
+// Package reconcile provides a balance reconciliation checker, verifying that the opening balance plus
+// the booked transactions of a period equals the closing balance, and locating gaps indicating missing
+// transactions.
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// Gap represents a difference between consecutive balances, indicating missing or incorrect
+// transactions between them.
+type Gap struct {
+	// After is the last transaction before the gap, nil if the gap is after the opening balance.
+	After *enablebankinggo.Transaction
+
+	// Before is the first transaction after the gap, nil if the gap is before the closing balance.
+	Before *enablebankinggo.Transaction
+
+	// Expected is the expected balance, i.e. the previous balance plus the amount of Before.
+	Expected string
+
+	// Actual is the balance reported by the bank, i.e. the balance after Before or the closing balance.
+	Actual string
+
+	// Difference is Actual minus Expected, i.e. the sum of the missing transactions.
+	Difference string
+}
+
+// String returns a description of the gap.
+func (g *Gap) String() string {
+	after, before := "opening balance", "closing balance"
+	if g.After != nil {
+		after = "transaction " + transactionRef(g.After)
+	}
+
+	if g.Before != nil {
+		before = "transaction " + transactionRef(g.Before)
+	}
+
+	return fmt.Sprintf("gap of %s between %s and %s: expected %s, actual %s", g.Difference, after, before, g.Expected, g.Actual)
+}
+
+// Result represents the result of a reconciliation.
+type Result struct {
+	// Currency is the currency of the balances.
+	Currency string
+
+	// Opening is the opening balance.
+	Opening string
+
+	// Closing is the closing balance.
+	Closing string
+
+	// Total is the sum of the booked transactions, negative for debits.
+	Total string
+
+	// Expected is the expected closing balance, i.e. Opening plus Total.
+	Expected string
+
+	// Difference is Closing minus Expected, zero when reconciled.
+	Difference string
+
+	// Transactions is the number of booked transactions.
+	Transactions int
+
+	// Gaps is the gaps located using the balance after transaction of the transactions, if reported by
+	// the bank. A difference may exist without located gaps.
+	Gaps []*Gap
+}
+
+// Reconciled returns whether the closing balance equals the opening balance plus the booked
+// transactions, without gaps.
+func (r *Result) Reconciled() bool {
+	d, ok := new(big.Rat).SetString(r.Difference)
+	return ok && d.Sign() == 0 && len(r.Gaps) == 0
+}
+
+// Check reconciles the opening and closing balances of a period with the booked transactions of the
+// period. Transactions that aren't booked are ignored. Transactions are sorted by booking date, keeping
+// the order of transactions booked the same date, or the reversed order if listed newest first.
+func Check(opening, closing *enablebankinggo.BalanceResource, transactions []*enablebankinggo.Transaction) (*Result, error) {
+	openingAmount, err := balanceAmount(opening)
+	if err != nil {
+		return nil, fmt.Errorf("opening balance: %w", err)
+	}
+
+	closingAmount, err := balanceAmount(closing)
+	if err != nil {
+		return nil, fmt.Errorf("closing balance: %w", err)
+	}
+
+	currency := opening.BalanceAmmount.Currency
+	if closing.BalanceAmmount.Currency != currency {
+		return nil, fmt.Errorf("opening balance currency %s differs from closing balance currency %s", currency, closing.BalanceAmmount.Currency)
+	}
+
+	booked := make([]*enablebankinggo.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx != nil && (tx.Status == enablebankinggo.AccountedTransactionStatus || tx.Status == "") {
+			booked = append(booked, tx)
+		}
+	}
+
+	// Transactions are commonly listed newest first, keeping the order of transactions booked the same
+	// date when reversed.
+	if len(booked) > 1 && bookingDate(booked[0]) > bookingDate(booked[len(booked)-1]) {
+		slices.Reverse(booked)
+	}
+
+	slices.SortStableFunc(booked, func(a, b *enablebankinggo.Transaction) int {
+		return strings.Compare(bookingDate(a), bookingDate(b))
+	})
+
+	total := new(big.Rat)
+	running := new(big.Rat).Set(openingAmount)
+	var gaps []*Gap
+	var after *enablebankinggo.Transaction
+	located := false
+	for _, tx := range booked {
+		amount, err := signedAmount(tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", transactionRef(tx), err)
+		}
+
+		if tx.TransactionAmount.Currency != "" && tx.TransactionAmount.Currency != currency {
+			return nil, fmt.Errorf("transaction %s: currency %s differs from balance currency %s", transactionRef(tx), tx.TransactionAmount.Currency, currency)
+		}
+
+		total.Add(total, amount)
+		running.Add(running, amount)
+
+		if actual, ok := balanceAfter(tx); ok {
+			if actual.Cmp(running) != 0 {
+				gaps = append(gaps, newGap(after, tx, running, actual))
+			}
+
+			running.Set(actual)
+			located = true
+		}
+
+		after = tx
+	}
+
+	// Gaps can only be located using balances after transactions, otherwise a difference is reported
+	// by the result without a gap.
+	if located && running.Cmp(closingAmount) != 0 {
+		gaps = append(gaps, newGap(after, nil, running, closingAmount))
+	}
+
+	expected := new(big.Rat).Add(openingAmount, total)
+	return &Result{
+		Currency:     currency,
+		Opening:      format(openingAmount),
+		Closing:      format(closingAmount),
+		Total:        format(total),
+		Expected:     format(expected),
+		Difference:   format(new(big.Rat).Sub(closingAmount, expected)),
+		Transactions: len(booked),
+		Gaps:         gaps,
+	}, nil
+}
+
+// CheckBalances reconciles the balances of a period with the booked transactions of the period, see
+// [Check]. The opening balance is the opening booked or previously closed booked balance, and the
+// closing balance is the closing booked or interim booked balance.
+func CheckBalances(balances []*enablebankinggo.BalanceResource, transactions []*enablebankinggo.Transaction) (*Result, error) {
+	opening := findBalance(balances, enablebankinggo.OpeningBookedBalanceType, enablebankinggo.PreviouslyClosedBookedBalanceType)
+	if opening == nil {
+		return nil, errors.New("no opening booked balance")
+	}
+
+	closing := findBalance(balances, enablebankinggo.ClosingBookedBalanceType, enablebankinggo.InterimBookedBalanceType)
+	if closing == nil {
+		return nil, errors.New("no closing booked balance")
+	}
+
+	return Check(opening, closing, transactions)
+}
+
+func findBalance(balances []*enablebankinggo.BalanceResource, balanceTypes ...enablebankinggo.BalanceType) *enablebankinggo.BalanceResource {
+	for _, balanceType := range balanceTypes {
+		for _, balance := range balances {
+			if balance != nil && balance.BalanceType == balanceType && balance.BalanceAmmount != nil {
+				return balance
+			}
+		}
+	}
+
+	return nil
+}
+
+func newGap(after, before *enablebankinggo.Transaction, expected, actual *big.Rat) *Gap {
+	return &Gap{
+		After:      after,
+		Before:     before,
+		Expected:   format(expected),
+		Actual:     format(actual),
+		Difference: format(new(big.Rat).Sub(actual, expected)),
+	}
+}
+
+func balanceAmount(balance *enablebankinggo.BalanceResource) (*big.Rat, error) {
+	if balance == nil || balance.BalanceAmmount == nil {
+		return nil, errors.New("balance cannot be nil")
+	}
+
+	amount, ok := new(big.Rat).SetString(strings.TrimSpace(balance.BalanceAmmount.Amount))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", balance.BalanceAmmount.Amount)
+	}
+
+	return amount, nil
+}
+
+// balanceAfter returns the balance after tx, if reported.
+func balanceAfter(tx *enablebankinggo.Transaction) (*big.Rat, bool) {
+	if tx.BalanceAfterTransaction == nil {
+		return nil, false
+	}
+
+	return new(big.Rat).SetString(strings.TrimSpace(tx.BalanceAfterTransaction.Amount))
+}
+
+// signedAmount returns the amount of tx, negative for debits.
+func signedAmount(tx *enablebankinggo.Transaction) (*big.Rat, error) {
+	if tx.TransactionAmount == nil {
+		return nil, errors.New("transaction amount cannot be nil")
+	}
+
+	amount := strings.TrimSpace(tx.TransactionAmount.Amount)
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", tx.TransactionAmount.Amount)
+	}
+
+	if tx.CreditDebitIndicator == enablebankinggo.DebitCreditDebitIndicator {
+		r.Neg(r.Abs(r))
+	}
+
+	if tx.CreditDebitIndicator == enablebankinggo.CreditCreditDebitIndicator {
+		r.Abs(r)
+	}
+
+	return r, nil
+}
+
+func bookingDate(tx *enablebankinggo.Transaction) string {
+	for _, date := range []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate} {
+		if date != "" {
+			return date
+		}
+	}
+
+	return ""
+}
+
+func transactionRef(tx *enablebankinggo.Transaction) string {
+	for _, ref := range []string{tx.EntryReference, tx.TransactionID, tx.ReferenceNumber} {
+		if ref != "" {
+			return ref
+		}
+	}
+
+	return bookingDate(tx)
+}
+
+// format formats amount with at least two decimals.
+func format(amount *big.Rat) string {
+	s := amount.FloatString(8)
+	s = strings.TrimRight(s, "0")
+	whole, fraction, _ := strings.Cut(s, ".")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	return whole + "." + fraction
+}
@@ -0,0 +1,33 @@
+package enablebankinggo
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// RequestIDHeader is the header identifying a request, generated for every request, for correlating logs
+// and support tickets with Enable Banking.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a context with the request ID sent with requests made with it, instead of a
+// generated one, e.g. to propagate the ID of an inbound request.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// requestID returns the request ID of ctx, or a generated random UUID.
+func requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDContextKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
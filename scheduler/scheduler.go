@@ -0,0 +1,429 @@
+// Package scheduler provides a scheduler of periodic account data refreshes per session, spreading
+// unattended (PSU not present) refreshes to stay within the PSD2 limit of four per day and ASPSP
+// specific limits, with priority refreshes when the PSU is present.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/events"
+)
+
+const (
+	// DefaultMaxUnattendedPerDay is the default maximum number of unattended refreshes per session and
+	// day, i.e. the PSD2 limit of account data requests without the PSU present.
+	DefaultMaxUnattendedPerDay = 4
+
+	// DefaultHistorySize is the default number of runs kept per session.
+	DefaultHistorySize = 100
+
+	// day is the window of the unattended refresh limit.
+	day = 24 * time.Hour
+)
+
+var (
+	// ErrSessionNotFound is returned when a session isn't scheduled.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSyncInProgress is returned when a refresh of the session is already running.
+	ErrSyncInProgress = errors.New("sync in progress")
+
+	// ErrSyncPanicked is the error of a run whose sync function panicked.
+	ErrSyncPanicked = errors.New("sync panicked")
+)
+
+// Trigger represents what triggered a refresh.
+type Trigger string
+
+const (
+	// ScheduledTrigger is an unattended refresh run by the scheduler, counting towards the daily
+	// limit. Account data requests shouldn't include PSU headers.
+	ScheduledTrigger Trigger = "scheduled"
+
+	// PSUPresentTrigger is a priority refresh requested while the PSU is online, not counting towards
+	// the daily limit. Account data requests should include PSU headers, e.g. the PSU IP address.
+	PSUPresentTrigger Trigger = "psu_present"
+)
+
+// SyncFunc refreshes the account data of a session, returning the result of the refresh, if any.
+type SyncFunc func(ctx context.Context, sessionID string, trigger Trigger) (*SyncResult, error)
+
+// SyncResult represents the result of a refresh of a session.
+type SyncResult struct {
+	// Transactions is the transactions added by the refresh by account ID, i.e. transactions not
+	// retrieved before.
+	Transactions map[string][]*enablebankinggo.Transaction
+}
+
+// Config represents the configuration of a [Scheduler].
+type Config struct {
+	// Sync refreshes the account data of a session. Required.
+	Sync SyncFunc
+
+	// MaxUnattendedPerDay is the maximum number of unattended refreshes per session in any 24 hour
+	// window. Defaults to DefaultMaxUnattendedPerDay.
+	MaxUnattendedPerDay int
+
+	// ASPSPMaxUnattendedPerDay overrides MaxUnattendedPerDay by ASPSP name, for ASPSPs with stricter
+	// limits.
+	ASPSPMaxUnattendedPerDay map[string]int
+
+	// Jitter is the maximum random offset added to or subtracted from scheduled refreshes, avoiding
+	// refreshing all sessions at the same time. Defaults to a tenth of the refresh interval.
+	Jitter time.Duration
+
+	// HistorySize is the number of runs kept per session. Defaults to DefaultHistorySize.
+	HistorySize int
+
+	// Seed is the seed of the random number generator of the jitter.
+	Seed uint64
+
+	// Publisher publishes an events.TransactionsAddedType event per account with added transactions
+	// after a successful refresh, if set.
+	Publisher events.Publisher
+}
+
+// Session represents a scheduled session.
+type Session struct {
+	// ID is the session ID.
+	ID string
+
+	// ASPSP is the name of the ASPSP of the session, used to look up ASPSP specific limits.
+	ASPSP string
+
+	// MaxUnattendedPerDay overrides the maximum number of unattended refreshes of the session, if set.
+	MaxUnattendedPerDay int
+}
+
+// Run represents a refresh of a session.
+type Run struct {
+	// SessionID is the session ID.
+	SessionID string
+
+	// Trigger is what triggered the refresh.
+	Trigger Trigger
+
+	// StartedAt is the time the refresh started.
+	StartedAt time.Time
+
+	// FinishedAt is the time the refresh finished.
+	FinishedAt time.Time
+
+	// Err is the error of the refresh, if any.
+	Err error
+
+	// Result is the result of a successful refresh, if any.
+	Result *SyncResult
+}
+
+type scheduledSession struct {
+	session    Session
+	maxPerDay  int
+	next       time.Time
+	running    bool
+	unattended []time.Time
+	history    []*Run
+}
+
+// Scheduler runs periodic unattended refreshes of sessions, spread evenly over the day with jitter
+// and never exceeding the daily limit of a session, and priority refreshes when the PSU is present.
+type Scheduler struct {
+	config Config
+	wake   chan struct{}
+
+	mu       sync.Mutex
+	rnd      *rand.Rand
+	sessions map[string]*scheduledSession
+}
+
+// New creates a new scheduler. Call [Scheduler.Run] to start running scheduled refreshes.
+func New(config Config) (*Scheduler, error) {
+	if config.Sync == nil {
+		return nil, errors.New("config.Sync cannot be nil")
+	}
+
+	if config.MaxUnattendedPerDay <= 0 {
+		config.MaxUnattendedPerDay = DefaultMaxUnattendedPerDay
+	}
+
+	if config.HistorySize <= 0 {
+		config.HistorySize = DefaultHistorySize
+	}
+
+	return &Scheduler{
+		config:   config,
+		wake:     make(chan struct{}, 1),
+		rnd:      rand.New(rand.NewPCG(config.Seed, config.Seed)),
+		sessions: map[string]*scheduledSession{},
+	}, nil
+}
+
+// Add schedules a session, replacing the session if already scheduled while keeping its history.
+// The first refresh is scheduled at a random time within the first refresh interval.
+func (s *Scheduler) Add(session Session) error {
+	if session.ID == "" {
+		return errors.New("session.ID cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maxPerDay := session.MaxUnattendedPerDay
+	if maxPerDay <= 0 {
+		maxPerDay = s.config.MaxUnattendedPerDay
+		if aspspMax, ok := s.config.ASPSPMaxUnattendedPerDay[session.ASPSP]; ok && aspspMax > 0 {
+			maxPerDay = aspspMax
+		}
+	}
+
+	if existing, ok := s.sessions[session.ID]; ok {
+		existing.session = session
+		existing.maxPerDay = maxPerDay
+	} else {
+		interval := day / time.Duration(maxPerDay)
+		s.sessions[session.ID] = &scheduledSession{
+			session:   session,
+			maxPerDay: maxPerDay,
+			next:      time.Now().Add(time.Duration(s.rnd.Int64N(int64(interval)))),
+		}
+	}
+
+	s.notify()
+
+	return nil
+}
+
+// Remove unschedules a session.
+func (s *Scheduler) Remove(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+	s.notify()
+}
+
+// Next returns the time of the next scheduled refresh of a session.
+func (s *Scheduler) Next(sessionID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return time.Time{}, ErrSessionNotFound
+	}
+
+	return sess.next, nil
+}
+
+// History returns the runs of a session, oldest first.
+func (s *Scheduler) History(sessionID string) ([]*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	return slices.Clone(sess.history), nil
+}
+
+// SyncNow refreshes a session immediately with PSUPresentTrigger, e.g. when the PSU opens the
+// application, overriding the schedule. Returns ErrSyncInProgress if a refresh of the session is
+// already running.
+func (s *Scheduler) SyncNow(ctx context.Context, sessionID string) (*Run, error) {
+	s.mu.Lock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrSessionNotFound
+	}
+
+	if sess.running {
+		s.mu.Unlock()
+		return nil, ErrSyncInProgress
+	}
+
+	sess.running = true
+	s.mu.Unlock()
+
+	return s.sync(ctx, sess, PSUPresentTrigger), nil
+}
+
+// Run runs scheduled refreshes until ctx is done, returning the context error. Due sessions are
+// refreshed one at a time.
+func (s *Scheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		for {
+			sess, wait := s.due()
+			if sess == nil {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(wait)
+				break
+			}
+
+			s.sync(ctx, sess, ScheduledTrigger)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// due returns a session due for refresh marked as running, or the duration until the next refresh.
+func (s *Scheduler) due() (*scheduledSession, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	wait := time.Hour
+	for _, sess := range s.sessions {
+		if sess.running {
+			continue
+		}
+
+		if !sess.next.After(now) {
+			sess.running = true
+			return sess, 0
+		}
+
+		wait = min(wait, sess.next.Sub(now))
+	}
+
+	return nil, wait
+}
+
+func (s *Scheduler) sync(ctx context.Context, sess *scheduledSession, trigger Trigger) *Run {
+	run := &Run{
+		SessionID: sess.session.ID,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	// The session is released even if publishing panics, leaving it stuck as running otherwise.
+	defer s.finish(sess, run)
+
+	run.Result, run.Err = s.callSync(ctx, sess.session.ID, trigger)
+	run.FinishedAt = time.Now()
+
+	if run.Err == nil {
+		s.publish(ctx, run)
+	}
+
+	return run
+}
+
+// callSync calls the sync function, recovering a panic as an ErrSyncPanicked error.
+func (s *Scheduler) callSync(ctx context.Context, sessionID string, trigger Trigger) (result *SyncResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%w: %v", ErrSyncPanicked, r)
+		}
+	}()
+
+	return s.config.Sync(ctx, sessionID, trigger)
+}
+
+// finish releases sess after run, recording run in the history and scheduling the next refresh.
+func (s *Scheduler) finish(sess *scheduledSession, run *Run) {
+	if run.FinishedAt.IsZero() {
+		run.FinishedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess.running = false
+	sess.history = append(sess.history, run)
+	if len(sess.history) > s.config.HistorySize {
+		sess.history = slices.Delete(sess.history, 0, len(sess.history)-s.config.HistorySize)
+	}
+
+	if run.Trigger == ScheduledTrigger {
+		sess.unattended = append(sess.unattended, run.StartedAt)
+	}
+
+	sess.next = s.nextLocked(sess, run.StartedAt)
+}
+
+// publish publishes the transactions added by run, in account ID order.
+func (s *Scheduler) publish(ctx context.Context, run *Run) {
+	if s.config.Publisher == nil || run.Result == nil {
+		return
+	}
+
+	accountIDs := make([]string, 0, len(run.Result.Transactions))
+	for accountID, transactions := range run.Result.Transactions {
+		if len(transactions) > 0 {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	slices.Sort(accountIDs)
+
+	for _, accountID := range accountIDs {
+		_ = s.config.Publisher.Publish(ctx, &events.Event{
+			Type:         events.TransactionsAddedType,
+			Source:       events.PollingSource,
+			SessionID:    run.SessionID,
+			AccountID:    accountID,
+			Transactions: run.Result.Transactions[accountID],
+			OccurredAt:   run.FinishedAt,
+		})
+	}
+}
+
+// nextLocked returns the time of the next unattended refresh of sess after a refresh at last, spread
+// evenly over the day with jitter and delayed until allowed by the daily limit.
+func (s *Scheduler) nextLocked(sess *scheduledSession, last time.Time) time.Time {
+	interval := day / time.Duration(sess.maxPerDay)
+	jitter := s.config.Jitter
+	if jitter <= 0 {
+		jitter = interval / 10
+	}
+
+	next := last.Add(interval + time.Duration(s.rnd.Int64N(2*int64(jitter)+1)) - jitter)
+
+	// Only unattended refreshes within the window of the next refresh count towards the limit.
+	sess.unattended = slices.DeleteFunc(sess.unattended, func(t time.Time) bool {
+		return !t.After(next.Add(-day))
+	})
+
+	if len(sess.unattended) >= sess.maxPerDay {
+		allowed := sess.unattended[len(sess.unattended)-sess.maxPerDay].Add(day + time.Second)
+		if allowed.After(next) {
+			next = allowed
+		}
+	}
+
+	return next
+}
+
+// notify wakes up Run to reconsider the schedule.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,163 @@
+// Package snapshot provides a batch job recording one balance snapshot per account per day into the
+// storage layer, forming the basis of balance history charts without reconstructing balances from
+// transactions.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/storage"
+)
+
+// Config represents the configuration of a [Job].
+type Config struct {
+	// Client is used to retrieve balances. Required.
+	Client enablebankinggo.AccountsDataClient
+
+	// Store stores the snapshots and provides the accounts of sessions. Required.
+	Store storage.Store
+
+	// Location is the time zone of snapshot dates of balances without a reference date. Defaults to
+	// UTC.
+	Location *time.Location
+}
+
+// Result represents the result of snapshotting the balances of an account.
+type Result struct {
+	// IdentificationHash is the primary account identification hash.
+	IdentificationHash string
+
+	// Date is the snapshot date.
+	Date string
+
+	// Recorded is whether a snapshot was recorded, false if the account already has a snapshot of the
+	// date.
+	Recorded bool
+
+	// Err is the error snapshotting the balances, if any.
+	Err error
+}
+
+// Job records daily balance snapshots.
+type Job struct {
+	config Config
+}
+
+// NewJob creates a new balance snapshot job.
+func NewJob(config Config) (*Job, error) {
+	if config.Client == nil {
+		return nil, errors.New("config.Client cannot be nil")
+	}
+
+	if config.Store == nil {
+		return nil, errors.New("config.Store cannot be nil")
+	}
+
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+
+	return &Job{config: config}, nil
+}
+
+// RunSession records a balance snapshot of each stored account of a session, see Run.
+func (j *Job) RunSession(ctx context.Context, sessionID string) ([]*Result, error) {
+	if sessionID == "" {
+		return nil, errors.New("sessionID cannot be empty")
+	}
+
+	accounts, err := j.config.Store.ListAccounts(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return j.Run(ctx, accounts...)
+}
+
+// Run retrieves the balances of each account using its UID and records a snapshot, unless the latest
+// snapshot of the account has the same date, see [Date]. Returns a result per account and the errors
+// joined.
+func (j *Job) Run(ctx context.Context, accounts ...*storage.Account) ([]*Result, error) {
+	results := make([]*Result, 0, len(accounts))
+	var errs []error
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+
+		result := j.snapshot(ctx, account)
+		results = append(results, result)
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", account.IdentificationHash, result.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (j *Job) snapshot(ctx context.Context, account *storage.Account) *Result {
+	result := &Result{IdentificationHash: account.IdentificationHash}
+	if account.UID == "" {
+		result.Err = errors.New("account UID cannot be empty")
+		return result
+	}
+
+	resp, err := j.config.Client.GetAccountBalances(ctx, account.UID, nil)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get balances: %w", err)
+		return result
+	}
+
+	snapshot := &storage.BalanceSnapshot{
+		IdentificationHash: account.IdentificationHash,
+		Balances:           resp.Balances,
+		TakenAt:            time.Now(),
+	}
+	result.Date = Date(snapshot, j.config.Location)
+
+	latest, err := j.config.Store.LatestBalanceSnapshot(ctx, account.IdentificationHash)
+	switch {
+	case err == nil && Date(latest, j.config.Location) == result.Date:
+		return result
+	case err != nil && !errors.Is(err, storage.ErrNotFound):
+		result.Err = fmt.Errorf("failed to get latest snapshot: %w", err)
+		return result
+	}
+
+	if err := j.config.Store.AddBalanceSnapshot(ctx, snapshot); err != nil {
+		result.Err = fmt.Errorf("failed to add snapshot: %w", err)
+		return result
+	}
+
+	result.Recorded = true
+	return result
+}
+
+// Date returns the date of a snapshot, i.e. the latest reference date of its balances, or the date it
+// was taken in loc if no balance has a reference date.
+func Date(snapshot *storage.BalanceSnapshot, loc *time.Location) string {
+	date := ""
+	for _, balance := range snapshot.Balances {
+		if balance == nil {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, balance.ReferenceDate); err == nil && balance.ReferenceDate > date {
+			date = balance.ReferenceDate
+		}
+	}
+
+	if date != "" {
+		return date
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return snapshot.TakenAt.In(loc).Format(time.DateOnly)
+}
@@ -0,0 +1,31 @@
+CREATE TABLE enablebanking_accounts (
+	identification_hash TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	uid TEXT NOT NULL,
+	resource JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX enablebanking_accounts_session_id_idx ON enablebanking_accounts (session_id);
+
+CREATE TABLE enablebanking_balance_snapshots (
+	id BIGSERIAL PRIMARY KEY,
+	identification_hash TEXT NOT NULL REFERENCES enablebanking_accounts (identification_hash) ON DELETE CASCADE,
+	balances JSONB NOT NULL,
+	taken_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX enablebanking_balance_snapshots_account_idx ON enablebanking_balance_snapshots (identification_hash, taken_at);
+
+CREATE TABLE enablebanking_transactions (
+	identification_hash TEXT NOT NULL REFERENCES enablebanking_accounts (identification_hash) ON DELETE CASCADE,
+	transaction_key TEXT NOT NULL,
+	booking_date DATE,
+	status TEXT NOT NULL,
+	resource JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (identification_hash, transaction_key)
+);
+
+CREATE INDEX enablebanking_transactions_booking_date_idx ON enablebanking_transactions (identification_hash, booking_date);
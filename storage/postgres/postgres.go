@@ -0,0 +1,370 @@
+// Package postgres provides a Postgres implementation of the storage interfaces using database/sql.
+// Any Postgres driver can be used, e.g. github.com/jackc/pgx/v5/stdlib or github.com/lib/pq.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/storage"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockID is the ID of the advisory lock serializing migrations.
+const migrationLockID = 7263548190
+
+var _ storage.Store = (*Store)(nil)
+
+// Store is a Postgres implementation of [storage.Store].
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new Postgres store. Call Migrate to create or upgrade the schema.
+func New(db *sql.DB) (*Store, error) {
+	if db == nil {
+		return nil, errors.New("db cannot be nil")
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Migrate applies the migrations not yet applied, in order. Applied migrations are recorded in the
+// enablebanking_schema_migrations table. Concurrent migrations are serialized using an advisory lock.
+func (s *Store) Migrate(ctx context.Context) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	_, err = s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS enablebanking_schema_migrations (
+	version TEXT PRIMARY KEY,
+	applied_at TIMESTAMPTZ NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+				return err
+			}
+
+			var applied bool
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM enablebanking_schema_migrations WHERE version = $1)`, version).Scan(&applied)
+			if err != nil || applied {
+				return err
+			}
+
+			query, err := migrations.ReadFile(name)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, string(query)); err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, `INSERT INTO enablebanking_schema_migrations (version, applied_at) VALUES ($1, $2)`, version, time.Now())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// UpsertAccount inserts or updates an account by its identification hash. UpdatedAt defaults to now.
+func (s *Store) UpsertAccount(ctx context.Context, account *storage.Account) error {
+	if account == nil || account.IdentificationHash == "" {
+		return errors.New("account.IdentificationHash cannot be empty")
+	}
+
+	resource, err := json.Marshal(account.Resource)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := account.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO enablebanking_accounts (identification_hash, session_id, uid, resource, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (identification_hash) DO UPDATE
+SET session_id = EXCLUDED.session_id, uid = EXCLUDED.uid, resource = EXCLUDED.resource, updated_at = EXCLUDED.updated_at`,
+		account.IdentificationHash, account.SessionID, account.UID, resource, updatedAt)
+
+	return err
+}
+
+// GetAccount returns an account by its identification hash, or storage.ErrNotFound.
+func (s *Store) GetAccount(ctx context.Context, identificationHash string) (*storage.Account, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT identification_hash, session_id, uid, resource, updated_at
+FROM enablebanking_accounts WHERE identification_hash = $1`, identificationHash)
+
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrNotFound
+	}
+
+	return account, err
+}
+
+// ListAccounts returns the accounts last retrieved with a session, or all accounts if sessionID is
+// empty, sorted by identification hash.
+func (s *Store) ListAccounts(ctx context.Context, sessionID string) ([]*storage.Account, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT identification_hash, session_id, uid, resource, updated_at
+FROM enablebanking_accounts WHERE $1 = '' OR session_id = $1 ORDER BY identification_hash`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*storage.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// DeleteAccount deletes an account with its balance snapshots and transactions.
+func (s *Store) DeleteAccount(ctx context.Context, identificationHash string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM enablebanking_accounts WHERE identification_hash = $1`, identificationHash)
+	return err
+}
+
+// AddBalanceSnapshot stores a balance snapshot of a stored account. TakenAt defaults to now.
+func (s *Store) AddBalanceSnapshot(ctx context.Context, snapshot *storage.BalanceSnapshot) error {
+	if snapshot == nil || snapshot.IdentificationHash == "" {
+		return errors.New("snapshot.IdentificationHash cannot be empty")
+	}
+
+	balances, err := json.Marshal(snapshot.Balances)
+	if err != nil {
+		return err
+	}
+
+	takenAt := snapshot.TakenAt
+	if takenAt.IsZero() {
+		takenAt = time.Now()
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO enablebanking_balance_snapshots (identification_hash, balances, taken_at)
+VALUES ($1, $2, $3)`, snapshot.IdentificationHash, balances, takenAt)
+
+	return err
+}
+
+// LatestBalanceSnapshot returns the latest balance snapshot of an account, or storage.ErrNotFound.
+func (s *Store) LatestBalanceSnapshot(ctx context.Context, identificationHash string) (*storage.BalanceSnapshot, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT identification_hash, balances, taken_at FROM enablebanking_balance_snapshots
+WHERE identification_hash = $1 ORDER BY taken_at DESC, id DESC LIMIT 1`, identificationHash)
+
+	snapshot, err := scanBalanceSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrNotFound
+	}
+
+	return snapshot, err
+}
+
+// ListBalanceSnapshots returns the balance snapshots of an account taken within from and to,
+// unbounded if zero, oldest first.
+func (s *Store) ListBalanceSnapshots(ctx context.Context, identificationHash string, from, to time.Time) ([]*storage.BalanceSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT identification_hash, balances, taken_at FROM enablebanking_balance_snapshots
+WHERE identification_hash = $1 AND ($2::timestamptz IS NULL OR taken_at >= $2) AND ($3::timestamptz IS NULL OR taken_at <= $3)
+ORDER BY taken_at, id`, identificationHash, nullTime(from), nullTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*storage.BalanceSnapshot
+	for rows.Next() {
+		snapshot, err := scanBalanceSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// UpsertTransactions inserts or updates transactions of a stored account by their
+// storage.TransactionKeys in a single database transaction, returning the transactions not previously
+// stored.
+func (s *Store) UpsertTransactions(ctx context.Context, identificationHash string, transactions []*enablebankinggo.Transaction) ([]*enablebankinggo.Transaction, error) {
+	if identificationHash == "" {
+		return nil, errors.New("identificationHash cannot be empty")
+	}
+
+	var added []*enablebankinggo.Transaction
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `INSERT INTO enablebanking_transactions
+(identification_hash, transaction_key, booking_date, status, resource, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $6)
+ON CONFLICT (identification_hash, transaction_key) DO UPDATE
+SET booking_date = EXCLUDED.booking_date, status = EXCLUDED.status, resource = EXCLUDED.resource, updated_at = EXCLUDED.updated_at
+RETURNING (xmax = 0)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		now := time.Now()
+		keys := storage.TransactionKeys(transactions)
+		for i, transaction := range transactions {
+			if transaction == nil {
+				continue
+			}
+
+			resource, err := json.Marshal(transaction)
+			if err != nil {
+				return err
+			}
+
+			var inserted bool
+			err = stmt.QueryRowContext(ctx, identificationHash, keys[i], nullDate(transaction.BookingDate),
+				string(transaction.Status), resource, now).Scan(&inserted)
+			if err != nil {
+				return err
+			}
+
+			if inserted {
+				added = append(added, transaction)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// ListTransactions returns the transactions of an account matching query, newest booking date first.
+// Transactions without a booking date are returned last and excluded by date filters.
+func (s *Store) ListTransactions(ctx context.Context, identificationHash string, query *storage.TransactionQuery) ([]*storage.Transaction, error) {
+	if query == nil {
+		query = &storage.TransactionQuery{}
+	}
+
+	var limit any
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT identification_hash, transaction_key, resource, created_at, updated_at
+FROM enablebanking_transactions
+WHERE identification_hash = $1 AND ($2::date IS NULL OR booking_date >= $2) AND ($3::date IS NULL OR booking_date <= $3)
+AND ($4 = '' OR status = $4)
+ORDER BY booking_date DESC NULLS LAST, created_at DESC, transaction_key
+LIMIT $5`, identificationHash, nullTime(query.From), nullTime(query.To), string(query.Status), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*storage.Transaction
+	for rows.Next() {
+		var transaction storage.Transaction
+		var resource []byte
+		err := rows.Scan(&transaction.IdentificationHash, &transaction.Key, &resource, &transaction.CreatedAt, &transaction.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(resource, &transaction.Resource); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %s: %w", transaction.Key, err)
+		}
+
+		transactions = append(transactions, &transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccount(row scanner) (*storage.Account, error) {
+	var account storage.Account
+	var resource []byte
+	err := row.Scan(&account.IdentificationHash, &account.SessionID, &account.UID, &resource, &account.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(resource, &account.Resource); err != nil {
+		return nil, fmt.Errorf("failed to decode account %s: %w", account.IdentificationHash, err)
+	}
+
+	return &account, nil
+}
+
+func scanBalanceSnapshot(row scanner) (*storage.BalanceSnapshot, error) {
+	var snapshot storage.BalanceSnapshot
+	var balances []byte
+	err := row.Scan(&snapshot.IdentificationHash, &balances, &snapshot.TakenAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(balances, &snapshot.Balances); err != nil {
+		return nil, fmt.Errorf("failed to decode balance snapshot of %s: %w", snapshot.IdentificationHash, err)
+	}
+
+	return &snapshot, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullDate returns date, or NULL if it isn't a valid date.
+func nullDate(date string) sql.NullString {
+	_, err := time.Parse(time.DateOnly, date)
+	return sql.NullString{String: date, Valid: err == nil}
+}
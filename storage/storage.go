@@ -0,0 +1,190 @@
+// Package storage provides persistence interfaces for accounts, balance snapshots and transactions,
+// allowing a sync engine to store account data in any backend. See the postgres package for a
+// reference implementation.
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ErrNotFound is returned when a stored resource doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Account represents a stored account. Accounts are identified by their identification hash, which is
+// stable across sessions, unlike the account UID.
+type Account struct {
+	// IdentificationHash is the primary account identification hash.
+	IdentificationHash string
+
+	// SessionID is the ID of the session the account was last retrieved with.
+	SessionID string
+
+	// UID is the account UID within the session.
+	UID string
+
+	// Resource is the account details.
+	Resource *enablebankinggo.AccountResource
+
+	// UpdatedAt is the time the account was last stored.
+	UpdatedAt time.Time
+}
+
+// BalanceSnapshot represents the balances of an account at a point in time.
+type BalanceSnapshot struct {
+	// IdentificationHash is the primary account identification hash.
+	IdentificationHash string
+
+	// Balances is the balances of the account.
+	Balances []*enablebankinggo.BalanceResource
+
+	// TakenAt is the time the balances were retrieved.
+	TakenAt time.Time
+}
+
+// Transaction represents a stored transaction.
+type Transaction struct {
+	// IdentificationHash is the primary account identification hash.
+	IdentificationHash string
+
+	// Key is the key identifying the transaction within the account, see [TransactionKeys].
+	Key string
+
+	// Resource is the transaction.
+	Resource *enablebankinggo.Transaction
+
+	// CreatedAt is the time the transaction was first stored.
+	CreatedAt time.Time
+
+	// UpdatedAt is the time the transaction was last stored.
+	UpdatedAt time.Time
+}
+
+// TransactionQuery represents the filter of stored transactions to list.
+type TransactionQuery struct {
+	// From is the first booking date to include, if set.
+	From time.Time
+
+	// To is the last booking date to include, if set.
+	To time.Time
+
+	// Status is the transaction status to include, if set.
+	Status enablebankinggo.TransactionStatus
+
+	// Limit is the maximum number of transactions to return, unlimited if zero.
+	Limit int
+}
+
+// AccountStore stores accounts.
+type AccountStore interface {
+	// UpsertAccount inserts or updates an account by its identification hash.
+	UpsertAccount(ctx context.Context, account *Account) error
+
+	// GetAccount returns an account by its identification hash, or ErrNotFound.
+	GetAccount(ctx context.Context, identificationHash string) (*Account, error)
+
+	// ListAccounts returns the accounts last retrieved with a session, or all accounts if sessionID is
+	// empty, sorted by identification hash.
+	ListAccounts(ctx context.Context, sessionID string) ([]*Account, error)
+
+	// DeleteAccount deletes an account with its balance snapshots and transactions.
+	DeleteAccount(ctx context.Context, identificationHash string) error
+}
+
+// BalanceStore stores balance snapshots.
+type BalanceStore interface {
+	// AddBalanceSnapshot stores a balance snapshot.
+	AddBalanceSnapshot(ctx context.Context, snapshot *BalanceSnapshot) error
+
+	// LatestBalanceSnapshot returns the latest balance snapshot of an account, or ErrNotFound.
+	LatestBalanceSnapshot(ctx context.Context, identificationHash string) (*BalanceSnapshot, error)
+
+	// ListBalanceSnapshots returns the balance snapshots of an account taken within from and to,
+	// unbounded if zero, oldest first.
+	ListBalanceSnapshots(ctx context.Context, identificationHash string, from, to time.Time) ([]*BalanceSnapshot, error)
+}
+
+// TransactionStore stores transactions.
+type TransactionStore interface {
+	// UpsertTransactions inserts or updates transactions of an account by their [TransactionKeys],
+	// returning the transactions not previously stored.
+	UpsertTransactions(ctx context.Context, identificationHash string, transactions []*enablebankinggo.Transaction) ([]*enablebankinggo.Transaction, error)
+
+	// ListTransactions returns the transactions of an account matching query, newest booking date
+	// first.
+	ListTransactions(ctx context.Context, identificationHash string, query *TransactionQuery) ([]*Transaction, error)
+}
+
+// Store stores accounts, balance snapshots and transactions.
+type Store interface {
+	AccountStore
+	BalanceStore
+	TransactionStore
+}
+
+// TransactionKey returns the key identifying tx within an account, i.e. the entry reference, which
+// is unique and immutable for an account, or the transaction ID. Transactions without either are
+// keyed by a hash of their dates, amount, credit debit indicator and remittance information, which
+// may change, e.g. when a pending transaction is booked, and is the same for identical transactions,
+// e.g. two equal card payments on the same day. Use [TransactionKeys] to key the transactions of a
+// sync, keeping identical transactions apart.
+func TransactionKey(tx *enablebankinggo.Transaction) string {
+	return transactionKey(tx, 0)
+}
+
+// TransactionKeys returns the keys of transactions, see [TransactionKey], in the same order. The hash
+// of an identical transaction also includes its occurrence within transactions, so the second equal
+// card payment on a day is keyed apart from the first. The occurrence is only stable if transactions
+// are the complete transactions of the days they cover, in the order returned by the API, i.e.
+// transactions of a sync rather than of a single page. The key of nil transactions is empty.
+func TransactionKeys(transactions []*enablebankinggo.Transaction) []string {
+	keys := make([]string, len(transactions))
+	occurrences := map[string]int{}
+	for i, tx := range transactions {
+		if tx == nil {
+			continue
+		}
+
+		key := transactionKey(tx, 0)
+		if n := occurrences[key]; n > 0 && strings.HasPrefix(key, "hash:") {
+			keys[i] = transactionKey(tx, n)
+		} else {
+			keys[i] = key
+		}
+
+		occurrences[key]++
+	}
+
+	return keys
+}
+
+// transactionKey returns the key of tx, see [TransactionKey], hashing occurrence into the key of
+// transactions without an entry reference or transaction ID if not 0.
+func transactionKey(tx *enablebankinggo.Transaction, occurrence int) string {
+	if tx.EntryReference != "" {
+		return "ref:" + tx.EntryReference
+	}
+
+	if tx.TransactionID != "" {
+		return "id:" + tx.TransactionID
+	}
+
+	fields := []string{tx.BookingDate, tx.ValueDate, tx.TransactionDate, string(tx.CreditDebitIndicator)}
+	if tx.TransactionAmount != nil {
+		fields = append(fields, tx.TransactionAmount.Amount, tx.TransactionAmount.Currency)
+	}
+	fields = append(fields, tx.RemittanceInformation...)
+	if occurrence > 0 {
+		fields = append(fields, strconv.Itoa(occurrence))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return "hash:" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,181 @@
+package enablebankinggo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// errStopStreaming stops streaming transactions without failing, e.g. when an iteration is stopped.
+var errStopStreaming = errors.New("stop streaming")
+
+// StreamAccountTransactions retrieves a page of transactions of a specific account like GetAccountTransactions, but
+// decodes the transactions incrementally, calling fn for every transaction as it is parsed, so large pages aren't
+// buffered in memory. Returns the continuation key of the next page, if any, or the error returned by fn.
+// Transactions are always decoded using encoding/json, regardless of [WithCodec].
+func (c *APIClient) StreamAccountTransactions(ctx context.Context, accountID string, params *GetAccountTransactionsRequestParams, fn func(tx *Transaction) error) (string, error) {
+	if accountID == "" {
+		return "", errors.New("accountID cannot be empty")
+	}
+
+	if fn == nil {
+		return "", errors.New("fn cannot be nil")
+	}
+
+	reqHTTP, err := c.newAccountTransactionsRequest(ctx, accountID, params)
+	if err != nil {
+		return "", err
+	}
+
+	dec := &transactionsDecoder{fn: fn}
+	err = c.sendRequest(reqHTTP, dec)
+	if err != nil {
+		return "", err
+	}
+
+	return dec.continuationKey, nil
+}
+
+// StreamTransactions returns an iterator of the transactions of all pages of an account, following continuation
+// keys like IterateTransactions, but decoding every page incrementally, see StreamAccountTransactions. Iteration
+// stops after yielding an error. params isn't modified.
+func (c *APIClient) StreamTransactions(ctx context.Context, accountID string, params *GetAccountTransactionsRequestParams) iter.Seq2[*Transaction, error] {
+	req := GetAccountTransactionsRequestParams{}
+	if params != nil {
+		req = *params
+	}
+
+	return func(yield func(*Transaction, error) bool) {
+		for {
+			stopped := false
+			continuationKey, err := c.StreamAccountTransactions(ctx, accountID, &req, func(tx *Transaction) error {
+				if !yield(tx, nil) {
+					stopped = true
+					return errStopStreaming
+				}
+
+				return nil
+			})
+			if stopped {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if continuationKey == "" {
+				return
+			}
+
+			req.ContinuationKeyQueryParam = continuationKey
+		}
+	}
+}
+
+// transactionsDecoder decodes a HalTransactions response token by token, calling fn for every transaction.
+type transactionsDecoder struct {
+	fn              func(tx *Transaction) error
+	continuationKey string
+}
+
+func (d *transactionsDecoder) decodeResponse(body io.Reader, strict bool) error {
+	dec := json.NewDecoder(body)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		// Empty body, i.e. no transactions.
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected {, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case "transactions":
+			err = d.decodeTransactions(dec)
+			if errors.Is(err, errStopStreaming) {
+				// The remaining body is discarded when the response is closed.
+				return nil
+			}
+		case "continuation_key":
+			var continuationKey *string
+			err = dec.Decode(&continuationKey)
+			if continuationKey != nil {
+				d.continuationKey = *continuationKey
+			}
+		default:
+			if strict {
+				return fmt.Errorf("json: unknown field %q", tok)
+			}
+
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func (d *transactionsDecoder) decodeTransactions(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected transactions array, got %v", tok)
+	}
+
+	for dec.More() {
+		var tx Transaction
+		if err := dec.Decode(&tx); err != nil {
+			return err
+		}
+
+		if err := d.fn(&tx); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package enablebankinggo
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrCertificatePinMismatch is returned when no certificate of the verified chain of the API matches a pin set
+// using [WithCertificatePins].
+var ErrCertificatePinMismatch = errors.New("certificate pin mismatch")
+
+// WithRootCAs sets the root certificate authorities verifying the certificate of the API, e.g. of a TLS
+// intercepting proxy, instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *APIClient) {
+		c.tlsOptions = append(c.tlsOptions, func(config *tls.Config) {
+			config.RootCAs = pool
+		})
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS13. Defaults to TLS 1.2.
+func WithMinTLSVersion(version uint16) ClientOption {
+	return func(c *APIClient) {
+		c.tlsOptions = append(c.tlsOptions, func(config *tls.Config) {
+			config.MinVersion = version
+		})
+	}
+}
+
+// WithCertificatePins pins the certificates of the API, failing connections with [ErrCertificatePinMismatch]
+// unless a certificate of the verified chain matches a pin. Pins are base64 encoded SHA-256 hashes of the subject
+// public key info of a certificate, optionally prefixed by "sha256/", see [CertificatePin]. Pin an intermediate
+// or root certificate, or include backup pins, to survive certificate renewals.
+func WithCertificatePins(pins ...string) ClientOption {
+	return func(c *APIClient) {
+		c.tlsOptions = append(c.tlsOptions, func(config *tls.Config) {
+			allowed := make(map[string]bool, len(pins))
+			for _, pin := range pins {
+				allowed[strings.TrimPrefix(pin, "sha256/")] = true
+			}
+
+			config.VerifyConnection = func(cs tls.ConnectionState) error {
+				for _, chain := range cs.VerifiedChains {
+					for _, cert := range chain {
+						if allowed[CertificatePin(cert)] {
+							return nil
+						}
+					}
+				}
+
+				return ErrCertificatePinMismatch
+			}
+		})
+	}
+}
+
+// WithClientCertificate sets the client certificate presented for mutual TLS, e.g. required by gateways Enable
+// Banking traffic is routed through. Load a certificate and key using [tls.LoadX509KeyPair].
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *APIClient) {
+		c.tlsOptions = append(c.tlsOptions, func(config *tls.Config) {
+			config.Certificates = []tls.Certificate{cert}
+		})
+	}
+}
+
+// CertificatePin returns the pin of cert, i.e. the base64 encoded SHA-256 hash of its subject public key info.
+func CertificatePin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// applyTLSOptions applies the TLS options to a copy of the HTTP client and its transport, leaving a client set
+// using [WithHTTPClient] unchanged. The transport must be an *http.Transport, or nil for [NewTransport].
+func (c *APIClient) applyTLSOptions() error {
+	if len(c.tlsOptions) == 0 {
+		return nil
+	}
+
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = NewTransport()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return errors.New("TLS options require the HTTP transport to be an *http.Transport")
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	for _, option := range c.tlsOptions {
+		option(transport.TLSClientConfig)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package enablebankinggo
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+const (
+	// TransportDefaultMaxIdleConnsPerHost is the default maximum number of idle connections kept per host
+	// by [NewTransport], allowing bursty workloads to reuse connections instead of handshaking.
+	TransportDefaultMaxIdleConnsPerHost = 32
+
+	// TransportDefaultIdleConnTimeout is the default time idle connections are kept by [NewTransport].
+	TransportDefaultIdleConnTimeout = 5 * time.Minute
+
+	// TransportDefaultKeepAlive is the default TCP keep-alive interval of connections of [NewTransport].
+	TransportDefaultKeepAlive = 30 * time.Second
+)
+
+// NewTransport creates an HTTP transport tuned for the Enable Banking API, keeping more idle
+// connections for longer than [http.DefaultTransport], since cold TLS handshakes dominate the latency
+// of bursty workloads.
+func NewTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: TransportDefaultKeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   TransportDefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:       TransportDefaultIdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ClientSessionCache: tls.NewLRUClientSessionCache(64),
+		},
+	}
+}
+
+// NewHTTPClient creates the default HTTP client of the clients, with a timeout of [ClientDefaultTimeout] and a
+// transport created by [NewTransport]. Every client gets a dedicated HTTP client, instead of sharing and mutating
+// [http.DefaultClient].
+func NewHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   ClientDefaultTimeout,
+		Transport: NewTransport(),
+	}
+}
+
+// ConnectionInfo represents connection diagnostics of a request, collected using [httptrace].
+type ConnectionInfo struct {
+	// Reused is whether the connection was reused from a previous request.
+	Reused bool
+
+	// WasIdle is whether the reused connection was idle.
+	WasIdle bool
+
+	// IdleTime is the time the reused connection was idle.
+	IdleTime time.Duration
+
+	// DNSDuration is the time of the DNS lookup, zero if no lookup was done.
+	DNSDuration time.Duration
+
+	// ConnectDuration is the time establishing the TCP connection, zero if no connection was
+	// established.
+	ConnectDuration time.Duration
+
+	// TLSHandshakeDuration is the time of the TLS handshake, zero if no handshake was done.
+	TLSHandshakeDuration time.Duration
+
+	// TLSResumed is whether the TLS session was resumed.
+	TLSResumed bool
+
+	// TimeToFirstByte is the time from starting the request to receiving the first response byte.
+	TimeToFirstByte time.Duration
+}
+
+// WithConnectionDiagnostics enables collecting connection diagnostics of every request, e.g. whether
+// the connection was reused and DNS and TLS timings, exposed as [RequestInfo.Connection].
+func WithConnectionDiagnostics() ClientOption {
+	return func(c *APIClient) {
+		c.connectionDiagnostics = true
+	}
+}
+
+// connectionTracer collects the connection diagnostics of a request. Callbacks may be called
+// concurrently, e.g. when dialing multiple addresses.
+type connectionTracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	info         ConnectionInfo
+}
+
+// traceRequest returns req with a context collecting connection diagnostics, and the tracer.
+func traceRequest(req *http.Request) (*http.Request, *connectionTracer) {
+	t := &connectionTracer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.update(func() {
+				t.info.Reused = info.Reused
+				t.info.WasIdle = info.WasIdle
+				t.info.IdleTime = info.IdleTime
+			})
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.update(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.update(func() { t.info.DNSDuration = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.update(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.update(func() {
+				if err == nil {
+					t.info.ConnectDuration = time.Since(t.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() {
+			t.update(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.update(func() {
+				if err == nil {
+					t.info.TLSHandshakeDuration = time.Since(t.tlsStart)
+					t.info.TLSResumed = state.DidResume
+				}
+			})
+		},
+		GotFirstResponseByte: func() {
+			t.update(func() { t.info.TimeToFirstByte = time.Since(t.start) })
+		},
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+func (t *connectionTracer) update(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fn()
+}
+
+// connectionInfo returns the collected connection diagnostics.
+func (t *connectionTracer) connectionInfo() *ConnectionInfo {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info := t.info
+	return &info
+}
@@ -72,8 +72,8 @@ func getJwtHeader(applicationID string) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(encodedHeader), nil
 }
 
-func getJwtBody(ttl int64) (string, time.Time, error) {
-	iat := time.Now().Unix()
+func getJwtBody(now time.Time, ttl int64) (string, time.Time, error) {
+	iat := now.Unix()
 	encodedBody, err := json.Marshal(struct {
 		Iss string `json:"iss"`
 		Aud string `json:"aud"`
@@ -0,0 +1,291 @@
+// Package verification provides an account ownership verification flow, confirming a PSU controls an
+// account by starting a minimal scope authorization, matching the authorized accounts against the
+// expected account and holder name, and producing a signed verification result.
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/counterparty"
+	"github.com/marefr/enablebankinggo/internal/hmactoken"
+)
+
+// DefaultConsentValidity is the default validity of the minimal scope consent.
+const DefaultConsentValidity = time.Hour
+
+// ErrInvalidSignature is returned when a signed result is invalid.
+var ErrInvalidSignature = hmactoken.ErrInvalidSignature
+
+// Client is the API client used by the verification flow.
+type Client interface {
+	// StartAuthorization starts user authorization with an ASPSP.
+	StartAuthorization(ctx context.Context, req *enablebankinggo.StartAuthorizationRequest) (*enablebankinggo.StartAuthorizationResponse, error)
+
+	// AuthorizeSession authorizes a user session using the authorization code.
+	AuthorizeSession(ctx context.Context, req *enablebankinggo.AuthorizeSessionRequest) (*enablebankinggo.AuthorizeSessionResponse, error)
+
+	// DeleteSession deletes a user session.
+	DeleteSession(ctx context.Context, sessionID string, params *enablebankinggo.DeleteSessionRequestParams) (*enablebankinggo.SuccessResponse, error)
+}
+
+// Expected represents the account expected to be controlled by the PSU. At least one of IBAN and
+// IdentificationHash is required.
+type Expected struct {
+	// IBAN is the IBAN of the account.
+	IBAN string `json:"iban,omitempty"`
+
+	// IdentificationHash is the identification hash of the account, e.g. from a previous session.
+	IdentificationHash string `json:"identification_hash,omitempty"`
+
+	// HolderName is the expected account holder name, if any.
+	HolderName string `json:"holder_name,omitempty"`
+}
+
+// StartRequest represents a request to start verification.
+type StartRequest struct {
+	// ASPSP is the ASPSP of the account.
+	ASPSP enablebankinggo.ASPSP
+
+	// Expected is the account to verify, requested as the only account of the consent if IBAN is set.
+	Expected Expected
+
+	// RedirectURL is the URL the PSU is redirected to after authorization.
+	RedirectURL string
+
+	// State is returned in the redirect.
+	State string
+
+	// PSUType is the PSU type. Defaults to personal.
+	PSUType enablebankinggo.PSUType
+
+	// Language is the preferred PSU language, if any.
+	Language string
+
+	// ConsentValidity is the validity of the consent. Defaults to DefaultConsentValidity.
+	ConsentValidity time.Duration
+}
+
+// Start starts a minimal scope authorization, i.e. without balances and transactions access and
+// short validity, returning the authorization to redirect the PSU to.
+func Start(ctx context.Context, client Client, req *StartRequest) (*enablebankinggo.StartAuthorizationResponse, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	if req == nil {
+		return nil, errors.New("req cannot be nil")
+	}
+
+	if req.Expected.IBAN == "" && req.Expected.IdentificationHash == "" {
+		return nil, errors.New("req.Expected.IBAN or req.Expected.IdentificationHash must be set")
+	}
+
+	validity := req.ConsentValidity
+	if validity <= 0 {
+		validity = DefaultConsentValidity
+	}
+
+	psuType := req.PSUType
+	if psuType == "" {
+		psuType = enablebankinggo.PersonalPSUType
+	}
+
+	access := &enablebankinggo.Access{
+		ValidUntil: time.Now().Add(validity).UTC().Format(time.RFC3339),
+	}
+	if req.Expected.IBAN != "" {
+		access.Accounts = []*enablebankinggo.AccountIdentification{{IBAN: normalizeIBAN(req.Expected.IBAN)}}
+	}
+
+	return client.StartAuthorization(ctx, &enablebankinggo.StartAuthorizationRequest{
+		Access:      access,
+		ASPSP:       req.ASPSP,
+		State:       req.State,
+		RedirectURL: req.RedirectURL,
+		PSUType:     psuType,
+		Language:    req.Language,
+	})
+}
+
+// Match represents how the account was matched.
+type Match string
+
+const (
+	// IBANMatch is a match by IBAN.
+	IBANMatch Match = "iban"
+
+	// IdentificationHashMatch is a match by identification hash.
+	IdentificationHashMatch Match = "identification_hash"
+
+	// HolderNameMatch is a match by holder name.
+	HolderNameMatch Match = "holder_name"
+)
+
+// Result represents the result of a verification.
+type Result struct {
+	// Verified is whether the PSU controls the expected account, i.e. an authorized account matches
+	// the IBAN or identification hash, and the holder name if expected.
+	Verified bool `json:"verified"`
+
+	// Expected is the expected account.
+	Expected Expected `json:"expected"`
+
+	// Matches is how the account was matched.
+	Matches []Match `json:"matches,omitempty"`
+
+	// HolderName is the holder name of the matched account, if any.
+	HolderName string `json:"holder_name,omitempty"`
+
+	// IdentificationHash is the identification hash of the matched account, if any.
+	IdentificationHash string `json:"identification_hash,omitempty"`
+
+	// ASPSP is the ASPSP of the session.
+	ASPSP *enablebankinggo.ASPSP `json:"aspsp,omitempty"`
+
+	// VerifiedAt is the time of the verification.
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// Complete authorizes the session using the authorization code of the redirect, matches the authorized
+// accounts against expected and deletes the session, which is only needed for the verification.
+func Complete(ctx context.Context, client Client, code string, expected Expected) (*Result, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+
+	if code == "" {
+		return nil, errors.New("code cannot be empty")
+	}
+
+	session, err := client.AuthorizeSession(ctx, &enablebankinggo.AuthorizeSessionRequest{Code: code})
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize session: %w", err)
+	}
+
+	result := Verify(session.Accounts, expected)
+	result.ASPSP = session.ASPSP
+
+	if _, err := client.DeleteSession(ctx, session.SessionID, nil); err != nil {
+		return result, fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return result, nil
+}
+
+// Verify matches the accounts against expected. Holder names match if the words of the expected name
+// are contained in the holder name, ignoring case, order and legal entity suffixes.
+func Verify(accounts []*enablebankinggo.AccountResource, expected Expected) *Result {
+	result := &Result{Expected: expected, VerifiedAt: time.Now().UTC()}
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+
+		matches := accountMatches(account, expected)
+		if len(matches) == 0 {
+			continue
+		}
+
+		nameMatches := expected.HolderName == "" || holderNameMatches(account.Name, expected.HolderName)
+		if nameMatches && expected.HolderName != "" {
+			matches = append(matches, HolderNameMatch)
+		}
+
+		// Prefer a fully verified account, falling back to the first account matching the number.
+		if result.Matches == nil || nameMatches {
+			result.Matches = matches
+			result.HolderName = account.Name
+			result.IdentificationHash = account.IdentificationHash
+			result.Verified = nameMatches
+		}
+
+		if result.Verified {
+			break
+		}
+	}
+
+	return result
+}
+
+func accountMatches(account *enablebankinggo.AccountResource, expected Expected) []Match {
+	var matches []Match
+	if iban := normalizeIBAN(expected.IBAN); iban != "" {
+		ibans := []string{}
+		if account.AccountID != nil {
+			ibans = append(ibans, normalizeIBAN(account.AccountID.IBAN))
+		}
+
+		for _, id := range account.AllAccountIDs {
+			if id != nil && id.SchemeName == "IBAN" {
+				ibans = append(ibans, normalizeIBAN(id.Identification))
+			}
+		}
+
+		if slices.Contains(ibans, iban) {
+			matches = append(matches, IBANMatch)
+		}
+	}
+
+	if hash := expected.IdentificationHash; hash != "" {
+		if account.IdentificationHash == hash || slices.Contains(account.IdentificationHashes, hash) {
+			matches = append(matches, IdentificationHashMatch)
+		}
+	}
+
+	return matches
+}
+
+func holderNameMatches(holderName, expected string) bool {
+	holderWords := strings.Fields(counterparty.NormalizeName(holderName))
+	expectedWords := strings.Fields(counterparty.NormalizeName(expected))
+	if len(expectedWords) == 0 {
+		return false
+	}
+
+	for _, word := range expectedWords {
+		if !slices.Contains(holderWords, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// Sign returns result signed using HMAC-SHA256 with key, i.e. the base64url encoded JSON result and
+// signature separated by a dot.
+func Sign(result *Result, key []byte) (string, error) {
+	if result == nil {
+		return "", errors.New("result cannot be nil")
+	}
+
+	if len(key) == 0 {
+		return "", errors.New("key cannot be empty")
+	}
+
+	return hmactoken.Sign(result, key)
+}
+
+// ParseSigned verifies the signature of a signed result, returning the result.
+func ParseSigned(signed string, key []byte) (*Result, error) {
+	var result Result
+	err := hmactoken.Parse(signed, key, &result)
+	if errors.Is(err, ErrInvalidSignature) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	return &result, nil
+}
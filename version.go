@@ -0,0 +1,41 @@
+package enablebankinggo
+
+import (
+	"runtime/debug"
+)
+
+const modulePath = "github.com/marefr/enablebankinggo"
+
+// Version is the version of the library, resolved from the build information of the binary, or "devel" when
+// unknown, e.g. in tests or builds without module support.
+var Version = moduleVersion()
+
+// DefaultUserAgent is the User-Agent header identifying the library sent with every request, see [WithUserAgent].
+var DefaultUserAgent = "enablebankinggo/" + Version
+
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		if dep.Version != "" && dep.Version != "(devel)" {
+			return dep.Version
+		}
+	}
+
+	return "devel"
+}
@@ -0,0 +1,225 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeadLetter represents a stored event whose callback failed.
+type DeadLetter struct {
+	// Event is the event.
+	Event *Event
+
+	// Error is the error of the last failed attempt.
+	Error string
+
+	// Attempts is the number of failed attempts.
+	Attempts int
+
+	// FirstFailedAt is the time of the first failed attempt.
+	FirstFailedAt time.Time
+
+	// LastFailedAt is the time of the last failed attempt.
+	LastFailedAt time.Time
+}
+
+// DeadLetterStore persists events whose callbacks failed, allowing them to be re-driven later.
+type DeadLetterStore interface {
+	// Put stores a dead letter, replacing any dead letter of the same event ID.
+	Put(ctx context.Context, letter *DeadLetter) error
+
+	// Get returns the dead letter of an event ID, or nil if none.
+	Get(ctx context.Context, eventID string) (*DeadLetter, error)
+
+	// List returns up to limit dead letters, or all if limit is zero, oldest first.
+	List(ctx context.Context, limit int) ([]*DeadLetter, error)
+
+	// Delete deletes the dead letter of an event ID, if any.
+	Delete(ctx context.Context, eventID string) error
+}
+
+// RedriveResult represents the result of re-driving dead letters.
+type RedriveResult struct {
+	// Succeeded is the number of events handled and deleted from the store.
+	Succeeded int
+
+	// Failed is the dead letters failing again, updated in the store.
+	Failed []*DeadLetter
+}
+
+// SetDeadLetterStore sets the store events whose callbacks fail are persisted to. Set before serving
+// requests.
+func (h *Handler) SetDeadLetterStore(store DeadLetterStore) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.dlq = store
+}
+
+// Redrive dispatches up to limit dead letters of the store, or all if limit is zero, to the registered
+// callbacks again. Handled events are deleted from the store, dead letters failing again are updated.
+// An error is only returned if the store fails.
+func (h *Handler) Redrive(ctx context.Context, limit int) (*RedriveResult, error) {
+	h.mu.RLock()
+	store := h.dlq
+	h.mu.RUnlock()
+
+	if store == nil {
+		return nil, errors.New("dead-letter store not set")
+	}
+
+	letters, err := store.List(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RedriveResult{}
+	for _, letter := range letters {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		err := h.dispatch(ctx, letter.Event)
+		if err == nil {
+			if err := store.Delete(ctx, letter.Event.ID); err != nil {
+				return result, err
+			}
+
+			result.Succeeded++
+			continue
+		}
+
+		failed := *letter
+		failed.Error = err.Error()
+		failed.Attempts++
+		failed.LastFailedAt = time.Now()
+		if err := store.Put(ctx, &failed); err != nil {
+			return result, err
+		}
+
+		result.Failed = append(result.Failed, &failed)
+	}
+
+	return result, nil
+}
+
+// deadLetter stores event failed with err, returning whether it's stored.
+func (h *Handler) deadLetter(ctx context.Context, event *Event, err error) (bool, error) {
+	h.mu.RLock()
+	store := h.dlq
+	h.mu.RUnlock()
+
+	if store == nil {
+		return false, nil
+	}
+
+	if event.ID == "" {
+		return false, errors.New("event ID cannot be empty")
+	}
+
+	now := time.Now()
+	letter := &DeadLetter{Event: event, Error: err.Error(), Attempts: 1, FirstFailedAt: now, LastFailedAt: now}
+
+	// Redeliveries of a stored event update its dead letter.
+	existing, getErr := store.Get(ctx, event.ID)
+	if getErr != nil {
+		return false, getErr
+	}
+
+	if existing != nil {
+		letter.Attempts = existing.Attempts + 1
+		letter.FirstFailedAt = existing.FirstFailedAt
+	}
+
+	if err := store.Put(ctx, letter); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+var _ DeadLetterStore = (*MemoryDeadLetterStore)(nil)
+
+// MemoryDeadLetterStore is an in-memory [DeadLetterStore], e.g. for testing. Dead letters are lost when
+// the process exits.
+type MemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters map[string]*DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates a new in-memory dead-letter store.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: map[string]*DeadLetter{}}
+}
+
+// Put stores a dead letter, replacing any dead letter of the same event ID.
+func (s *MemoryDeadLetterStore) Put(_ context.Context, letter *DeadLetter) error {
+	if letter == nil || letter.Event == nil {
+		return errors.New("letter event cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := *letter
+	s.letters[letter.Event.ID] = &l
+	return nil
+}
+
+// Get returns the dead letter of an event ID, or nil if none.
+func (s *MemoryDeadLetterStore) Get(_ context.Context, eventID string) (*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.letters[eventID]
+	if !ok {
+		return nil, nil
+	}
+
+	letter := *l
+	return &letter, nil
+}
+
+// List returns up to limit dead letters, or all if limit is zero, oldest first.
+func (s *MemoryDeadLetterStore) List(_ context.Context, limit int) ([]*DeadLetter, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", limit)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(s.letters))
+	for _, l := range s.letters {
+		letter := *l
+		letters = append(letters, &letter)
+	}
+
+	slices.SortFunc(letters, func(a, b *DeadLetter) int {
+		if c := a.FirstFailedAt.Compare(b.FirstFailedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Event.ID, b.Event.ID)
+	})
+
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+
+	return letters, nil
+}
+
+// Delete deletes the dead letter of an event ID, if any.
+func (s *MemoryDeadLetterStore) Delete(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.letters, eventID)
+	return nil
+}
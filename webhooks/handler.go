@@ -0,0 +1,233 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// RequestVerifier verifies webhook requests, e.g. a [Verifier].
+type RequestVerifier interface {
+	// VerifyRequest verifies a webhook request and returns the authenticated event.
+	VerifyRequest(r *http.Request) (*Event, error)
+}
+
+// EventHandlerFunc handles a webhook event.
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an [http.Handler] receiving webhooks, i.e. verifying the request, decoding the event and
+// dispatching it to the callbacks registered for its type. It responds with:
+//
+//   - 200 when the event is handled, or no callback is registered for its type.
+//   - 400 when the event data can't be decoded.
+//   - 401 when the signature is missing, invalid, expired or signed with an unknown key.
+//   - 405 for other methods than POST.
+//   - 413 when the body exceeds the maximum size.
+//   - 500 when a callback fails, and 503 when the key set or a [Stream] is unavailable, so the webhook
+//     is retried.
+//
+// If a dead-letter store is set, see [Handler.SetDeadLetterStore], events whose callbacks fail are
+// stored and acknowledged with 200 instead, to be re-driven using [Handler.Redrive].
+//
+// Several callbacks may be registered for the same type, e.g. a [SessionTracker] and an application
+// callback, and are called in registration order, the event failing if any callback fails. Callbacks may
+// be called more than once for the same event, e.g. redeliveries, use [Event.ID] to deduplicate.
+// Register callbacks before serving requests.
+type Handler struct {
+	verifier RequestVerifier
+
+	mu       sync.RWMutex
+	handlers map[EventType][]EventHandlerFunc
+	other    []EventHandlerFunc
+	all      []EventHandlerFunc
+	onError  func(r *http.Request, err error)
+	dlq      DeadLetterStore
+}
+
+// NewHandler creates a new webhook handler verifying requests with verifier.
+func NewHandler(verifier RequestVerifier) (*Handler, error) {
+	if verifier == nil {
+		return nil, errors.New("verifier cannot be nil")
+	}
+
+	return &Handler{
+		verifier: verifier,
+		handlers: map[EventType][]EventHandlerFunc{},
+	}, nil
+}
+
+// On registers fn as a callback of events of type t, in addition to previously registered callbacks.
+func (h *Handler) On(t EventType, fn EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[t] = append(h.handlers[t], fn)
+}
+
+// OnOther registers fn as a callback of events of types without a callback registered using [Handler.On].
+func (h *Handler) OnOther(fn EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.other = append(h.other, fn)
+}
+
+// OnAll registers fn as a callback of all events, called after the callbacks of their type, e.g. for
+// forwarding every event to a queue.
+func (h *Handler) OnAll(fn EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.all = append(h.all, fn)
+}
+
+// OnSessionStatusChanged registers fn as the callback of SessionStatusChangedEventType events.
+func (h *Handler) OnSessionStatusChanged(fn func(ctx context.Context, event *SessionEvent) error) {
+	h.On(SessionStatusChangedEventType, sessionHandler(fn))
+}
+
+// OnSessionClosed registers fn as the callback of SessionClosedEventType events.
+func (h *Handler) OnSessionClosed(fn func(ctx context.Context, event *SessionEvent) error) {
+	h.On(SessionClosedEventType, sessionHandler(fn))
+}
+
+// OnPaymentStatusChanged registers fn as the callback of PaymentStatusChangedEventType events.
+func (h *Handler) OnPaymentStatusChanged(fn func(ctx context.Context, event *PaymentEvent) error) {
+	h.On(PaymentStatusChangedEventType, func(ctx context.Context, event *Event) error {
+		e := &PaymentEvent{Event: event}
+		if err := decodeEventData(event, &e.PaymentEventData); err != nil {
+			return err
+		}
+
+		return fn(ctx, e)
+	})
+}
+
+// OnError registers fn to be called with errors of requests, e.g. for logging.
+func (h *Handler) OnError(fn func(r *http.Request, err error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onError = fn
+}
+
+// ServeHTTP verifies, decodes and dispatches a webhook.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	event, err := h.verifier.VerifyRequest(r)
+	if err != nil {
+		h.fail(w, r, verifyStatusCode(err), err)
+		return
+	}
+
+	err = h.dispatch(r.Context(), event)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errInvalidEventData):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, ErrStreamFull), errors.Is(err, ErrStreamClosed):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		err = fmt.Errorf("failed to handle %s event %s: %w", event.Type, event.ID, err)
+		if statusCode != http.StatusBadRequest {
+			stored, dlqErr := h.deadLetter(r.Context(), event, err)
+			if dlqErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to store dead letter: %w", dlqErr))
+			}
+
+			if stored {
+				h.report(r, err)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+
+		h.fail(w, r, statusCode, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch calls the callbacks of the type of event, and the callbacks of all events, returning their
+// errors joined.
+func (h *Handler) dispatch(ctx context.Context, event *Event) error {
+	h.mu.RLock()
+	fns, ok := h.handlers[event.Type]
+	if !ok {
+		fns = h.other
+	}
+	fns = append(slices.Clip(fns), h.all...)
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *Handler) report(r *http.Request, err error) {
+	h.mu.RLock()
+	onError := h.onError
+	h.mu.RUnlock()
+
+	if onError != nil {
+		onError(r, err)
+	}
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	h.report(r, err)
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+func verifyStatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrJWKSUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature),
+		errors.Is(err, ErrUnknownKey), errors.Is(err, ErrExpiredSignature):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// errInvalidEventData is returned when the data of an event can't be decoded.
+var errInvalidEventData = errors.New("invalid event data")
+
+func sessionHandler(fn func(ctx context.Context, event *SessionEvent) error) EventHandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		e := &SessionEvent{Event: event}
+		if err := decodeEventData(event, &e.SessionEventData); err != nil {
+			return err
+		}
+
+		return fn(ctx, e)
+	}
+}
+
+func decodeEventData(event *Event, v any) error {
+	if err := json.Unmarshal(event.Data, v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidEventData, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+)
+
+// JWK represents a JSON Web Key of a key set. Only RSA and EC signing keys are supported.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// N and E are the modulus and exponent of RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Crv, X and Y are the curve and coordinates of EC keys.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS represents a JSON Web Key Set.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// PublicKey returns the public key of the JWK, i.e. a *rsa.PublicKey or *ecdsa.PublicKey.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N, "n")
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKInt(k.E, "e")
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("JWK exponent is too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported JWK curve %q", k.Crv)
+		}
+
+		x, err := decodeJWKInt(k.X, "x")
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeJWKInt(k.Y, "y")
+		if err != nil {
+			return nil, err
+		}
+
+		// Validates the point is on the curve.
+		size := (curve.Params().BitSize + 7) / 8
+		point := append([]byte{4}, append(x.FillBytes(make([]byte, size)), y.FillBytes(make([]byte, size))...)...)
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid JWK EC point: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type %q", k.Kty)
+	}
+}
+
+// signingKeys returns the public keys of the signing keys of the set by key ID. Keys that aren't
+// signing keys, or are unsupported, are skipped.
+func (s *JWKS) signingKeys() map[string]crypto.PublicKey {
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range s.Keys {
+		if jwk == nil || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys
+}
+
+// FetchJWKS retrieves the key set of url using httpClient, or http.DefaultClient if nil.
+func FetchJWKS(ctx context.Context, httpClient *http.Client, url string) (*JWKS, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var jwks JWKS
+	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	if !slices.ContainsFunc(jwks.Keys, func(k *JWK) bool { return k != nil }) {
+		return nil, errors.New("JWKS has no keys")
+	}
+
+	return &jwks, nil
+}
+
+func decodeJWKInt(value, name string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("JWK parameter %q is missing", name)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWK parameter %q: %w", name, err)
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
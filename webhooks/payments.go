@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/marefr/enablebankinggo"
+	"github.com/marefr/enablebankinggo/events"
+)
+
+var _ enablebankinggo.PaymentStatusNotifier = (*PaymentStatusReceiver)(nil)
+
+// PaymentStatusReceiver is a [enablebankinggo.PaymentStatusNotifier] notifying of payment status
+// changes received by payment webhooks, allowing [enablebankinggo.APIClient.WaitForPaymentStatus] to
+// resolve from a webhook instead of polling, see [enablebankinggo.WithPaymentStatusNotifier].
+type PaymentStatusReceiver struct {
+	mu        sync.Mutex
+	nextID    int
+	subs      map[string]map[int]chan enablebankinggo.PaymentStatus
+	publisher events.Publisher
+}
+
+// NewPaymentStatusReceiver creates a new payment status receiver.
+func NewPaymentStatusReceiver() *PaymentStatusReceiver {
+	return &PaymentStatusReceiver{subs: map[string]map[int]chan enablebankinggo.PaymentStatus{}}
+}
+
+// Register registers the receiver as the callback of payment events of h.
+func (r *PaymentStatusReceiver) Register(h *Handler) {
+	h.OnPaymentStatusChanged(r.Handle)
+}
+
+// SetPublisher sets the publisher of an events.PaymentFinalizedType event when a payment reaches a final
+// status. Set before serving requests.
+func (r *PaymentStatusReceiver) SetPublisher(publisher events.Publisher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.publisher = publisher
+}
+
+// Handle notifies the subscribers of the payment of a payment event, and publishes an
+// events.PaymentFinalizedType event if the status is final and a publisher is set. Subscribers not
+// keeping up only receive the latest status.
+func (r *PaymentStatusReceiver) Handle(ctx context.Context, event *PaymentEvent) error {
+	if event.PaymentID == "" {
+		return errors.New("event payment ID cannot be empty")
+	}
+
+	if event.Status == "" {
+		return errors.New("event status cannot be empty")
+	}
+
+	r.mu.Lock()
+	for _, ch := range r.subs[event.PaymentID] {
+		// Replace a pending status, sends only happen while holding the lock.
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- event.Status
+	}
+	publisher := r.publisher
+	r.mu.Unlock()
+
+	if publisher == nil || !event.Status.IsFinal() {
+		return nil
+	}
+
+	return publisher.Publish(ctx, &events.Event{
+		Type:       events.PaymentFinalizedType,
+		Source:     events.WebhookSource,
+		PaymentID:  event.PaymentID,
+		Status:     string(event.Status),
+		OccurredAt: event.CreatedAt,
+	})
+}
+
+// SubscribePaymentStatus returns a channel receiving the status changes of a payment, and a function
+// to unsubscribe and close it.
+func (r *PaymentStatusReceiver) SubscribePaymentStatus(paymentID string) (<-chan enablebankinggo.PaymentStatus, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextID
+	r.nextID++
+
+	ch := make(chan enablebankinggo.PaymentStatus, 1)
+	if r.subs[paymentID] == nil {
+		r.subs[paymentID] = map[int]chan enablebankinggo.PaymentStatus{}
+	}
+	r.subs[paymentID][id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			delete(r.subs[paymentID], id)
+			if len(r.subs[paymentID]) == 0 {
+				delete(r.subs, paymentID)
+			}
+
+			close(ch)
+		})
+	}
+}
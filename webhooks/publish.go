@@ -0,0 +1,173 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// EventIDMessageHeader is the message header of the event ID.
+	EventIDMessageHeader = "event-id"
+
+	// EventTypeMessageHeader is the message header of the event type.
+	EventTypeMessageHeader = "event-type"
+)
+
+// Message represents a webhook event forwarded to a message queue.
+type Message struct {
+	// Topic is the topic, subject or queue of the message.
+	Topic string
+
+	// Key is the partitioning or ordering key of the message, i.e. the session or payment ID of the
+	// event, or the event ID.
+	Key string
+
+	// Value is the JSON encoded event.
+	Value []byte
+
+	// Headers is the message headers, i.e. EventIDMessageHeader and EventTypeMessageHeader.
+	Headers map[string]string
+}
+
+// Publisher publishes messages to a message queue, e.g. Kafka, NATS or SQS. Adapt a client using
+// [PublisherFunc].
+type Publisher interface {
+	// Publish publishes a message.
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// PublisherFunc is an adapter to use a function as a [Publisher].
+type PublisherFunc func(ctx context.Context, msg *Message) error
+
+// Publish calls f(ctx, msg).
+func (f PublisherFunc) Publish(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// FanOut returns a publisher publishing every message to all publishers, returning the errors joined.
+func FanOut(publishers ...Publisher) Publisher {
+	return PublisherFunc(func(ctx context.Context, msg *Message) error {
+		var errs []error
+		for _, p := range publishers {
+			if err := p.Publish(ctx, msg); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// NATSConn is the publishing method of a NATS connection, e.g. *nats.Conn.
+type NATSConn interface {
+	// Publish publishes data to subject.
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher returns a publisher publishing messages to conn with the topic as subject. Headers
+// aren't published, the event ID and type are part of the value.
+func NATSPublisher(conn NATSConn) Publisher {
+	return PublisherFunc(func(_ context.Context, msg *Message) error {
+		return conn.Publish(msg.Topic, msg.Value)
+	})
+}
+
+// ForwarderConfig represents the configuration of a [Forwarder].
+type ForwarderConfig struct {
+	// Publisher publishes the events, use FanOut to publish to several queues. Required.
+	Publisher Publisher
+
+	// Topic returns the topic of an event. Defaults to TopicPrefix followed by the event type, e.g.
+	// enablebanking.session.closed.
+	Topic func(event *Event) string
+
+	// TopicPrefix is the prefix of the default topics. Defaults to "enablebanking.".
+	TopicPrefix string
+}
+
+// Forwarder forwards verified webhook events to a message queue, decoupling event processing from the
+// HTTP receiver. Register Handle as a callback of a [Handler], or use Register. Publish
+// errors fail the callback, so the webhook is retried or dead-lettered.
+type Forwarder struct {
+	config ForwarderConfig
+}
+
+// NewForwarder creates a new event forwarder.
+func NewForwarder(config ForwarderConfig) (*Forwarder, error) {
+	if config.Publisher == nil {
+		return nil, errors.New("config.Publisher cannot be nil")
+	}
+
+	if config.TopicPrefix == "" {
+		config.TopicPrefix = "enablebanking."
+	}
+
+	if config.Topic == nil {
+		prefix := config.TopicPrefix
+		config.Topic = func(event *Event) string {
+			return prefix + string(event.Type)
+		}
+	}
+
+	return &Forwarder{config: config}, nil
+}
+
+// Register registers the forwarder as a callback of all events of h, see [Handler.OnAll].
+func (f *Forwarder) Register(h *Handler) {
+	h.OnAll(f.Handle)
+}
+
+// Handle publishes event.
+func (f *Forwarder) Handle(ctx context.Context, event *Event) error {
+	msg, err := f.Message(event)
+	if err != nil {
+		return err
+	}
+
+	err = f.config.Publisher.Publish(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
+
+// Message returns the message of event.
+func (f *Forwarder) Message(event *Event) (*Message, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Topic: f.config.Topic(event),
+		Key:   messageKey(event),
+		Value: value,
+		Headers: map[string]string{
+			EventIDMessageHeader:   event.ID,
+			EventTypeMessageHeader: string(event.Type),
+		},
+	}, nil
+}
+
+// messageKey returns the session or payment ID of event, so events of the same resource are ordered,
+// or the event ID.
+func messageKey(event *Event) string {
+	var data struct {
+		SessionID string `json:"session_id"`
+		PaymentID string `json:"payment_id"`
+	}
+
+	_ = json.Unmarshal(event.Data, &data)
+
+	switch {
+	case data.SessionID != "":
+		return data.SessionID
+	case data.PaymentID != "":
+		return data.PaymentID
+	default:
+		return event.ID
+	}
+}
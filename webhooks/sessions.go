@@ -0,0 +1,188 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+// ErrSessionNotTracked is returned when the status of a session isn't known to a [SessionTracker].
+var ErrSessionNotTracked = errors.New("session not tracked")
+
+// SessionState represents the locally known status of a session.
+type SessionState struct {
+	// SessionID is the session ID.
+	SessionID string
+
+	// Status is the last known status of the session.
+	Status enablebankinggo.SessionStatus
+
+	// UpdatedAt is the time of the event, or retrieval, the status is known from.
+	UpdatedAt time.Time
+}
+
+// SessionStatusChange represents a change of the status of a session.
+type SessionStatusChange struct {
+	// From is the previous status, empty when the session wasn't tracked.
+	From enablebankinggo.SessionStatus
+
+	// Session is the session after the change.
+	Session SessionState
+}
+
+// SessionTrackerConfig represents the configuration of a [SessionTracker].
+type SessionTrackerConfig struct {
+	// Client retrieves the status of sessions not yet known from webhooks, if set.
+	Client enablebankinggo.UserSessionsClient
+
+	// OnChange is called on every status change, e.g. to update a consent.Manager using SetStatus.
+	// Called without holding locks of the tracker.
+	OnChange func(ctx context.Context, change SessionStatusChange)
+}
+
+// SessionTracker keeps a local view of the statuses of sessions from session webhooks, replacing
+// periodic GetSession polling in long-running services. Events older than the known status, e.g.
+// delivered out of order or retried, are ignored.
+type SessionTracker struct {
+	config SessionTrackerConfig
+
+	mu       sync.RWMutex
+	sessions map[string]*SessionState
+}
+
+// NewSessionTracker creates a new session status tracker.
+func NewSessionTracker(config SessionTrackerConfig) *SessionTracker {
+	return &SessionTracker{
+		config:   config,
+		sessions: map[string]*SessionState{},
+	}
+}
+
+// Register registers the tracker as the callback of session events of h.
+func (t *SessionTracker) Register(h *Handler) {
+	h.OnSessionStatusChanged(t.Handle)
+	h.OnSessionClosed(t.Handle)
+}
+
+// Handle updates the status of the session of a session event. Closed events without a status close
+// the session.
+func (t *SessionTracker) Handle(ctx context.Context, event *SessionEvent) error {
+	if event.SessionID == "" {
+		return errors.New("event session ID cannot be empty")
+	}
+
+	status := event.Status
+	if status == "" {
+		if event.Event == nil || event.Type != SessionClosedEventType {
+			return errors.New("event status cannot be empty")
+		}
+
+		status = enablebankinggo.ClosedSessionStatus
+	}
+
+	var at time.Time
+	if event.Event != nil {
+		at = event.CreatedAt
+	}
+
+	t.set(ctx, event.SessionID, status, at, false)
+	return nil
+}
+
+// Status returns the known status of a session. Unknown sessions are retrieved using Client, if set,
+// and tracked from then on.
+func (t *SessionTracker) Status(ctx context.Context, sessionID string) (enablebankinggo.SessionStatus, error) {
+	if s, err := t.Session(sessionID); err == nil {
+		return s.Status, nil
+	}
+
+	if t.config.Client == nil {
+		return "", ErrSessionNotTracked
+	}
+
+	resp, err := t.config.Client.GetSession(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	// A webhook received while retrieving the session takes precedence.
+	return t.set(ctx, sessionID, resp.Status, time.Time{}, true).Status, nil
+}
+
+// Session returns the known state of a session.
+func (t *SessionTracker) Session(sessionID string) (*SessionState, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotTracked
+	}
+
+	state := *s
+	return &state, nil
+}
+
+// Sessions returns the known states of all sessions, sorted by session ID.
+func (t *SessionTracker) Sessions() []*SessionState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	sessions := make([]*SessionState, 0, len(t.sessions))
+	for _, s := range t.sessions {
+		state := *s
+		sessions = append(sessions, &state)
+	}
+
+	slices.SortFunc(sessions, func(a, b *SessionState) int {
+		return strings.Compare(a.SessionID, b.SessionID)
+	})
+
+	return sessions
+}
+
+// Forget stops tracking a session, e.g. after deleting it.
+func (t *SessionTracker) Forget(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.sessions, sessionID)
+}
+
+// set updates the status of a session known at at, now if zero, returning the resulting state. Retrieved
+// statuses only apply to untracked sessions.
+func (t *SessionTracker) set(ctx context.Context, sessionID string, status enablebankinggo.SessionStatus, at time.Time, retrieved bool) SessionState {
+	t.mu.Lock()
+	s, ok := t.sessions[sessionID]
+	if ok && (retrieved || at.Before(s.UpdatedAt)) {
+		state := *s
+		t.mu.Unlock()
+		return state
+	}
+
+	if !ok {
+		s = &SessionState{SessionID: sessionID}
+		t.sessions[sessionID] = s
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	from := s.Status
+	s.Status = status
+	s.UpdatedAt = at
+	state := *s
+	t.mu.Unlock()
+
+	if from != status && t.config.OnChange != nil {
+		t.config.OnChange(ctx, SessionStatusChange{From: from, Session: state})
+	}
+
+	return state
+}
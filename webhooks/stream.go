@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// DefaultStreamBuffer is the default number of events buffered by a [Stream].
+const DefaultStreamBuffer = 64
+
+var (
+	// ErrStreamFull is returned when the buffer of a stream with RejectBackpressure is full.
+	ErrStreamFull = errors.New("webhook stream full")
+
+	// ErrStreamClosed is returned when a webhook is received by a closed stream.
+	ErrStreamClosed = errors.New("webhook stream closed")
+)
+
+// Backpressure represents how a [Stream] handles webhooks received while its buffer is full.
+type Backpressure int
+
+const (
+	// BlockBackpressure blocks the webhook request until the event is buffered, or the request is
+	// cancelled, responding with 503 so the webhook is retried.
+	BlockBackpressure Backpressure = iota
+
+	// RejectBackpressure immediately responds with 503 so the webhook is retried.
+	RejectBackpressure
+)
+
+// StreamConfig represents the configuration of a [Stream].
+type StreamConfig struct {
+	// Buffer is the number of events buffered. Defaults to DefaultStreamBuffer.
+	Buffer int
+
+	// Backpressure is how webhooks received while the buffer is full are handled. Defaults to
+	// BlockBackpressure.
+	Backpressure Backpressure
+
+	// Types is the event types streamed, or all if empty. Other events are acknowledged and dropped.
+	Types []EventType
+}
+
+// Stream is a webhook receiver exposing verified events as a channel, or an [iter.Seq], instead of
+// callbacks. Stream embeds a [Handler] serving the webhooks, so typed callbacks can still be registered,
+// called before events of their types are streamed.
+//
+// A webhook is acknowledged once its event is buffered, i.e. buffered events are lost if the process
+// exits before they are consumed.
+type Stream struct {
+	*Handler
+
+	config StreamConfig
+	ch     chan *Event
+
+	// mu guards sending to ch from closing it, done unblocks pending sends.
+	mu     sync.RWMutex
+	done   chan struct{}
+	closed bool
+	once   sync.Once
+}
+
+// NewStream creates a new webhook stream verifying requests with verifier.
+func NewStream(verifier RequestVerifier, config StreamConfig) (*Stream, error) {
+	handler, err := NewHandler(verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Buffer <= 0 {
+		config.Buffer = DefaultStreamBuffer
+	}
+
+	s := &Stream{
+		Handler: handler,
+		config:  config,
+		ch:      make(chan *Event, config.Buffer),
+		done:    make(chan struct{}),
+	}
+	handler.OnAll(s.push)
+
+	return s, nil
+}
+
+// Events returns the channel of events, closed by Close.
+func (s *Stream) Events() <-chan *Event {
+	return s.ch
+}
+
+// All returns an iterator of the events until the stream is closed or ctx is done.
+func (s *Stream) All(ctx context.Context) iter.Seq[*Event] {
+	return func(yield func(*Event) bool) {
+		for {
+			select {
+			case event, ok := <-s.ch:
+				if !ok || !yield(event) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Close closes the stream, responding to subsequent and blocked webhooks with 503 and closing the
+// events channel. Buffered events can still be consumed.
+func (s *Stream) Close() {
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.closed = true
+		close(s.ch)
+	})
+}
+
+func (s *Stream) push(ctx context.Context, event *Event) error {
+	if len(s.config.Types) > 0 && !slices.Contains(s.config.Types, event.Type) {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+
+	if s.config.Backpressure == RejectBackpressure {
+		select {
+		case s.ch <- event:
+			return nil
+		default:
+			return ErrStreamFull
+		}
+	}
+
+	select {
+	case s.ch <- event:
+		return nil
+	case <-s.done:
+		return ErrStreamClosed
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrStreamFull, ctx.Err())
+	}
+}
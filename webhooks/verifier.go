@@ -0,0 +1,369 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers SHA-256 for crypto.Hash.
+	_ "crypto/sha512" // Registers SHA-384 and SHA-512 for crypto.Hash.
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// DefaultJWKSTTL is the default time the key set is cached before it's refreshed.
+	DefaultJWKSTTL = time.Hour
+
+	// DefaultMinRefreshInterval is the default minimum interval between refreshes of the key set
+	// triggered by unknown keys.
+	DefaultMinRefreshInterval = time.Minute
+
+	// DefaultMaxAge is the default maximum age of a webhook signature.
+	DefaultMaxAge = 5 * time.Minute
+
+	// DefaultMaxBodySize is the default maximum size of a webhook request body.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// clockSkew is the tolerated difference between the clocks of the signer and the verifier.
+const clockSkew = time.Minute
+
+// Config represents the configuration of a [Verifier].
+type Config struct {
+	// JWKSURL is the URL of the key set webhooks are signed with, as provided by Enable Banking. Required, there's
+	// no documented default.
+	JWKSURL string
+
+	// HTTPClient is the client used for fetching the key set. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// JWKSTTL is the time the key set is cached before it's refreshed. Defaults to DefaultJWKSTTL.
+	JWKSTTL time.Duration
+
+	// MinRefreshInterval is the minimum interval between refreshes of the key set triggered by
+	// webhooks signed with unknown keys, e.g. after key rollover. Defaults to
+	// DefaultMinRefreshInterval.
+	MinRefreshInterval time.Duration
+
+	// MaxAge is the maximum age of a signature according to its issued at (iat) header, rejecting
+	// replayed webhooks. Signatures without iat are rejected unless the check is disabled. Defaults to
+	// DefaultMaxAge, negative disables the check.
+	MaxAge time.Duration
+
+	// MaxBodySize is the maximum size of a webhook request body read by VerifyRequest. Defaults to
+	// DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// Clock is the clock used for the signature age and key set expiry. Defaults to
+	// enablebankinggo.SystemClock.
+	Clock enablebankinggo.Clock
+}
+
+// Verifier verifies the JWS signature of webhooks against a key set fetched from JWKSURL. The key set
+// is cached for JWKSTTL and refreshed when a webhook is signed with an unknown key, supporting key
+// rollover. If a refresh fails the cached keys are used until a later refresh succeeds. A Verifier is
+// safe for concurrent use.
+type Verifier struct {
+	config Config
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	refreshedAt time.Time
+
+	// refreshMu serializes refreshes of the key set.
+	refreshMu sync.Mutex
+}
+
+// NewVerifier creates a new webhook verifier.
+func NewVerifier(config Config) (*Verifier, error) {
+	if config.JWKSURL == "" {
+		return nil, errors.New("JWKS URL cannot be empty")
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	if config.JWKSTTL <= 0 {
+		config.JWKSTTL = DefaultJWKSTTL
+	}
+
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = DefaultMinRefreshInterval
+	}
+
+	if config.MaxAge == 0 {
+		config.MaxAge = DefaultMaxAge
+	}
+
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if config.Clock == nil {
+		config.Clock = enablebankinggo.SystemClock
+	}
+
+	return &Verifier{config: config}, nil
+}
+
+// VerifyRequest reads the body of a webhook request and verifies it against the JWS signature of the
+// SignatureHeader, or JWSHeader, header, see [Verifier.Verify].
+func (v *Verifier) VerifyRequest(r *http.Request) (*Event, error) {
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(JWSHeader)
+	}
+
+	if signature == "" {
+		return nil, ErrMissingSignature
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, v.config.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook body: %w", err)
+	}
+
+	if int64(len(body)) > v.config.MaxBodySize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, v.config.MaxBodySize)
+	}
+
+	return v.Verify(r.Context(), signature, body)
+}
+
+// Verify verifies the compact JWS signature of a webhook body and returns the decoded event. Both
+// attached payloads, which must match body, and detached payloads, including unencoded payloads
+// (RFC 7797), are supported. Errors wrap ErrInvalidSignature, ErrUnknownKey, ErrExpiredSignature or
+// ErrJWKSUnavailable when verification fails.
+func (v *Verifier) Verify(ctx context.Context, signature string, body []byte) (*Event, error) {
+	payload, err := v.verifyJWS(ctx, strings.TrimSpace(signature), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var event Event
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// jwsHeader represents the protected header of a webhook signature.
+type jwsHeader struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Iat  int64    `json:"iat"`
+	B64  *bool    `json:"b64"`
+	Crit []string `json:"crit"`
+}
+
+func (v *Verifier) verifyJWS(ctx context.Context, signature string, body []byte) ([]byte, error) {
+	parts := strings.Split(signature, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: JWS must consist of 3 parts", ErrInvalidSignature)
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode JWS header: %v", ErrInvalidSignature, err)
+	}
+
+	var header jwsHeader
+	err = json.Unmarshal(headerJSON, &header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode JWS header: %v", ErrInvalidSignature, err)
+	}
+
+	for _, name := range header.Crit {
+		if name != "b64" && name != "iat" {
+			return nil, fmt.Errorf("%w: unsupported critical JWS header %q", ErrInvalidSignature, name)
+		}
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode JWS signature: %v", ErrInvalidSignature, err)
+	}
+
+	encoded := header.B64 == nil || *header.B64
+	payload := body
+	signingInput := parts[0] + "."
+	switch {
+	case parts[1] != "" && encoded:
+		payload, err = base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to decode JWS payload: %v", ErrInvalidSignature, err)
+		}
+
+		if body != nil && !bytes.Equal(payload, body) {
+			return nil, fmt.Errorf("%w: JWS payload doesn't match body", ErrInvalidSignature)
+		}
+
+		signingInput += parts[1]
+	case parts[1] != "":
+		return nil, fmt.Errorf("%w: unencoded JWS payload must be detached", ErrInvalidSignature)
+	case encoded:
+		signingInput += base64.RawURLEncoding.EncodeToString(body)
+	default:
+		signingInput += string(body)
+	}
+
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("%w: empty payload", ErrInvalidSignature)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifySignature(header.Alg, key, []byte(signingInput), sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	if v.config.MaxAge > 0 {
+		if header.Iat == 0 {
+			return nil, fmt.Errorf("%w: issued at (iat) header missing", ErrExpiredSignature)
+		}
+
+		issuedAt := time.Unix(header.Iat, 0)
+		now := v.config.Clock.Now()
+		if now.Sub(issuedAt) > v.config.MaxAge || issuedAt.Sub(now) > clockSkew {
+			return nil, fmt.Errorf("%w: issued at %s", ErrExpiredSignature, issuedAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return payload, nil
+}
+
+// key returns the public key of kid, refreshing the key set if it's expired or doesn't contain kid. A
+// signature without kid is accepted if the key set has a single key.
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok, fresh := v.cachedKey(kid)
+	if ok && fresh {
+		return key, nil
+	}
+
+	err := v.refresh(ctx, !fresh)
+	if refreshed, found, _ := v.cachedKey(kid); found {
+		return refreshed, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+func (v *Verifier) cachedKey(kid string) (crypto.PublicKey, bool, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	fresh := !v.fetchedAt.IsZero() && v.config.Clock.Now().Sub(v.fetchedAt) < v.config.JWKSTTL
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true, fresh
+		}
+	}
+
+	key, ok := v.keys[kid]
+	return key, ok && kid != "", fresh
+}
+
+// refresh fetches the key set, unless it was refreshed within MinRefreshInterval and isn't expired.
+func (v *Verifier) refresh(ctx context.Context, expired bool) error {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	v.mu.RLock()
+	refreshedAt, fetchedAt := v.refreshedAt, v.fetchedAt
+	v.mu.RUnlock()
+
+	// Another goroutine refreshed the key set while waiting.
+	if expired && !fetchedAt.IsZero() && v.config.Clock.Now().Sub(fetchedAt) < v.config.JWKSTTL {
+		return nil
+	}
+
+	if !expired && v.config.Clock.Now().Sub(refreshedAt) < v.config.MinRefreshInterval {
+		return nil
+	}
+
+	jwks, err := FetchJWKS(ctx, v.config.HTTPClient, v.config.JWKSURL)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.refreshedAt = v.config.Clock.Now()
+	if err != nil {
+		return err
+	}
+
+	v.keys = jwks.signingKeys()
+	v.fetchedAt = v.refreshedAt
+
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput, sig []byte) error {
+	var h crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		h = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		h = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		h = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+
+	hasher := h.New()
+	hasher.Write(signingInput)
+	hashed := hasher.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(k, h, hashed, sig)
+		case "PS":
+			return rsa.VerifyPSS(k, h, hashed, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid ECDSA signature length")
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, hashed, r, s) {
+			return errors.New("ECDSA verification failed")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("JWS algorithm %q doesn't match key type %T", alg, key)
+}
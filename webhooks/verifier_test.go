@@ -0,0 +1,117 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marefr/enablebankinggo/enablebankingtest"
+	"github.com/marefr/enablebankinggo/webhooks"
+)
+
+func newTestSigner(tb testing.TB) *enablebankingtest.WebhookSigner {
+	tb.Helper()
+
+	signer, err := enablebankingtest.NewWebhookSigner()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(signer.Close)
+	return signer
+}
+
+func TestVerifierVerify(t *testing.T) {
+	signer := newTestSigner(t)
+
+	// other publishes a key with an ID not in the key set of signer.
+	other := newTestSigner(t)
+	if err := other.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := signer.NewEvent(webhooks.PaymentStatusChangedEventType, webhooks.PaymentEventData{PaymentID: "payment-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		sign func() (string, error)
+		body []byte
+		err  error
+	}{
+		{
+			name: "valid",
+			sign: func() (string, error) { return signer.Sign(body) },
+			body: body,
+		},
+		{
+			name: "tampered body",
+			sign: func() (string, error) { return signer.Sign(body) },
+			body: []byte(`{"id":"evt-2","type":"payment.status_changed"}`),
+			err:  webhooks.ErrInvalidSignature,
+		},
+		{
+			name: "expired",
+			sign: func() (string, error) {
+				signer.SetNow(func() time.Time { return time.Now().Add(-time.Hour) })
+				defer signer.SetNow(time.Now)
+
+				return signer.Sign(body)
+			},
+			body: body,
+			err:  webhooks.ErrExpiredSignature,
+		},
+		{
+			name: "unknown key",
+			sign: func() (string, error) { return other.Sign(body) },
+			body: body,
+			err:  webhooks.ErrUnknownKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := signer.Verifier(webhooks.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature, err := tt.sign()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := verifier.Verify(context.Background(), signature, tt.body)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.ID != event.ID || got.Type != event.Type {
+				t.Fatalf("expected event %s of type %s, got %s of type %s", event.ID, event.Type, got.ID, got.Type)
+			}
+		})
+	}
+}
+
+func TestNewVerifierRequiresJWKSURL(t *testing.T) {
+	if _, err := webhooks.NewVerifier(webhooks.Config{}); err == nil {
+		t.Fatal("expected error for empty JWKS URL")
+	}
+}
@@ -0,0 +1,107 @@
+// Package webhooks provides receiving of webhooks delivered by Enable Banking, i.e. validating the
+// JWS signature of the webhook against the published JSON Web Key Set (JWKS), decoding the
+// authenticated event and dispatching it to callbacks per event type.
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/marefr/enablebankinggo"
+)
+
+const (
+	// SignatureHeader is the header of the JWS signature of a webhook.
+	SignatureHeader = "Signature"
+
+	// JWSHeader is the alternative header of the JWS signature of a webhook, used if SignatureHeader
+	// is missing.
+	JWSHeader = "JWS"
+)
+
+var (
+	// ErrMissingSignature is returned when a webhook has no JWS signature.
+	ErrMissingSignature = errors.New("missing webhook signature")
+
+	// ErrInvalidSignature is returned when the JWS signature of a webhook is malformed or doesn't match
+	// the payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrUnknownKey is returned when the key a webhook is signed with isn't in the key set, even after
+	// refreshing it.
+	ErrUnknownKey = errors.New("unknown webhook signing key")
+
+	// ErrExpiredSignature is returned when a webhook is signed too long ago, or in the future, e.g.
+	// when replayed, or the signature has no issued at (iat) header to check its age.
+	ErrExpiredSignature = errors.New("expired webhook signature")
+
+	// ErrJWKSUnavailable is returned when the key set can't be fetched and no cached key matches.
+	ErrJWKSUnavailable = errors.New("webhook JWKS unavailable")
+
+	// ErrBodyTooLarge is returned when a webhook request body exceeds the maximum size.
+	ErrBodyTooLarge = errors.New("webhook body too large")
+)
+
+// EventType represents the type of a webhook event. The event type names aren't part of the documented
+// API, they're assumed by this package and events of other types are dispatched as unknown, see
+// [Handler.OnOther].
+type EventType string
+
+const (
+	// SessionStatusChangedEventType is delivered when the status of a session changes, e.g. when
+	// authorized by the PSU.
+	SessionStatusChangedEventType EventType = "session.status_changed"
+
+	// SessionClosedEventType is delivered when a session is closed, e.g. when the consent is revoked
+	// or expires.
+	SessionClosedEventType EventType = "session.closed"
+
+	// PaymentStatusChangedEventType is delivered when the status of a payment changes.
+	PaymentStatusChangedEventType EventType = "payment.status_changed"
+)
+
+// Event represents an authenticated webhook event.
+type Event struct {
+	// ID is the unique ID of the event, the same for redeliveries.
+	ID string `json:"id"`
+
+	// Type is the type of the event.
+	Type EventType `json:"type"`
+
+	// CreatedAt is the time the event was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Data is the event type specific data.
+	Data json.RawMessage `json:"data"`
+}
+
+// SessionEventData represents the data of session events.
+type SessionEventData struct {
+	// SessionID is the ID of the session.
+	SessionID string `json:"session_id"`
+
+	// Status is the status of the session.
+	Status enablebankinggo.SessionStatus `json:"status"`
+}
+
+// PaymentEventData represents the data of payment events.
+type PaymentEventData struct {
+	// PaymentID is the ID of the payment.
+	PaymentID string `json:"payment_id"`
+
+	// Status is the status of the payment.
+	Status enablebankinggo.PaymentStatus `json:"status"`
+}
+
+// SessionEvent represents a session event with its decoded data.
+type SessionEvent struct {
+	*Event
+	SessionEventData
+}
+
+// PaymentEvent represents a payment event with its decoded data.
+type PaymentEvent struct {
+	*Event
+	PaymentEventData
+}